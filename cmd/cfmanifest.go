@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/SAP/cloud-mta/internal/cfmanifest"
+	"github.com/SAP/cloud-mta/internal/logs"
+	"github.com/SAP/cloud-mta/mta"
+)
+
+var importCFManifestCmdPath string
+var importCFManifestCmdManifests []string
+var importCFManifestCmdID string
+var importCFManifestCmdServices []string
+var exportCFManifestCmdPath string
+var exportCFManifestCmdExtensions []string
+var exportCFManifestCmdWorkspaceDir string
+var exportCFManifestCmdModules []string
+var exportCFManifestCmdEnvFileName string
+var exportCFManifestCmdTarget string
+
+func init() {
+	importCFManifestCmd.Flags().StringVarP(&importCFManifestCmdPath, "path", "p", "",
+		"the path to the generated mta.yaml file")
+	importCFManifestCmd.Flags().StringSliceVarP(&importCFManifestCmdManifests, "manifest", "m", nil,
+		"the paths to the Cloud Foundry manifest files")
+	importCFManifestCmd.Flags().StringVarP(&importCFManifestCmdID, "id", "i", "",
+		"the MTA ID; the default ID is the name of the mta.yaml folder")
+	importCFManifestCmd.Flags().StringSliceVarP(&importCFManifestCmdServices, "service", "s", nil,
+		"the service offerings of managed services, in the <service-instance>=<service>[:<plan>] format; other services are imported as existing services")
+
+	exportCFManifestCmd.Flags().StringVarP(&exportCFManifestCmdPath, "path", "p", "",
+		"the path to the mta.yaml file")
+	exportCFManifestCmd.Flags().StringSliceVarP(&exportCFManifestCmdExtensions, "extensions", "x", nil,
+		"the paths to the MTA extension descriptors")
+	exportCFManifestCmd.Flags().StringVarP(&exportCFManifestCmdWorkspaceDir, "workspace", "w", "",
+		"the path to the project folder; the default path is the folder of the mta.yaml file")
+	exportCFManifestCmd.Flags().StringSliceVarP(&exportCFManifestCmdModules, "module", "m", nil,
+		"the names of the exported modules; by default all the modules are exported")
+	exportCFManifestCmd.Flags().StringVarP(&exportCFManifestCmdEnvFileName, "envFile", "e", "",
+		"the environment file path, relative to the module folder; the default file path is \".env\"")
+	exportCFManifestCmd.Flags().StringVarP(&exportCFManifestCmdTarget, "target", "t", "",
+		"the path to the generated manifest file; the default path is \"manifest.yml\" in the project folder")
+}
+
+// importCFManifestCmd - generates an mta.yaml file from Cloud Foundry manifest files.
+var importCFManifestCmd = &cobra.Command{
+	Use:   "cf-manifest",
+	Short: "Import an MTA from Cloud Foundry manifest files",
+	Long: `Generates an mta.yaml file from Cloud Foundry manifest.yml files.
+Each application is imported as a module and each bound service instance is imported as a resource.
+Manifest features that have no MTA equivalent are reported in the messages.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return mta.RunModifyAndWriteHash("import Cloud Foundry manifest", importCFManifestCmdPath, false, func() ([]string, error) {
+			offerings, err := cfmanifest.ParseServiceOfferings(importCFManifestCmdServices)
+			if err != nil {
+				return nil, err
+			}
+			return cfmanifest.Import(importCFManifestCmdPath, importCFManifestCmdManifests, importCFManifestCmdID, offerings)
+		}, 0, true)
+	},
+	Hidden:        false,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+}
+
+// exportCFManifestCmd - generates a Cloud Foundry manifest file from the MTA modules.
+var exportCFManifestCmd = &cobra.Command{
+	Use:   "cf-manifest",
+	Short: "Export MTA modules as a Cloud Foundry manifest",
+	Long: `Generates a Cloud Foundry manifest.yml file from MTA modules, for pushing them with the cf CLI.
+The modules' parameters and properties are resolved based on environment variables and an environment file, after merging the MTA extensions.
+Resources required by the modules are bound as services.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logs.Logger.Info("Export Cloud Foundry manifest")
+		messages, err := cfmanifest.Export(exportCFManifestCmdWorkspaceDir, exportCFManifestCmdPath, exportCFManifestCmdExtensions,
+			exportCFManifestCmdModules, exportCFManifestCmdEnvFileName, exportCFManifestCmdTarget)
+		for _, message := range messages {
+			logs.Logger.Warn(message)
+		}
+		if err != nil {
+			logs.Logger.Error(err)
+		}
+		return err
+	},
+	Hidden:        false,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+}
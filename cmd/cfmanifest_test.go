@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/SAP/cloud-mta/mta"
+)
+
+var _ = Describe("CF Manifest", func() {
+
+	AfterEach(func() {
+		os.RemoveAll(getTestPath("result"))
+	})
+
+	It("Sanity", func() {
+		importCFManifestCmdPath = getTestPath("result", "mta.yaml")
+		importCFManifestCmdManifests = []string{getTestPath("manifest.yml")}
+		importCFManifestCmdServices = []string{"db=hana:hdi-shared"}
+		Ω(importCFManifestCmd.RunE(nil, []string{})).Should(Succeed())
+		result, _, err := mta.GetMtaFromFile(importCFManifestCmdPath, nil, true)
+		Ω(err).Should(Succeed())
+		Ω(result.ID).Should(Equal("result"))
+		Ω(result.Modules[0].Name).Should(Equal("srv"))
+		Ω(result.Resources[0].Type).Should(Equal("org.cloudfoundry.managed-service"))
+		// already exists
+		Ω(importCFManifestCmd.RunE(nil, []string{})).Should(HaveOccurred())
+	})
+
+	It("Export", func() {
+		Ω(os.MkdirAll(getTestPath("result"), os.ModePerm)).Should(Succeed())
+		exportCFManifestCmdPath = getTestPath("mta.yaml")
+		exportCFManifestCmdModules = []string{"backend"}
+		exportCFManifestCmdTarget = getTestPath("result", "manifest.yml")
+		Ω(exportCFManifestCmd.RunE(nil, []string{})).Should(Succeed())
+		Ω(exportCFManifestCmdTarget).Should(BeAnExistingFile())
+		exportCFManifestCmdModules = []string{"unknown"}
+		Ω(exportCFManifestCmd.RunE(nil, []string{})).Should(HaveOccurred())
+	})
+})
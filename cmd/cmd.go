@@ -18,9 +18,15 @@ func init() {
 	rootCmd.AddCommand(existCmd)
 	rootCmd.AddCommand(resolveMtaCmd)
 	rootCmd.AddCommand(validateMtaCmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(generateCmd)
 	addCmd.AddCommand(addModuleCmd, addResourceCmd)
 	getCmd.AddCommand(getModulesCmd, getResourcesCmd, getMtaIDCmd, getResourceConfigCmd, getBuildParametersCmd, getParametersCmd)
 	updateCmd.AddCommand(updateModuleCmd, updateResourceCmd, updateBuildParametersCmd, updateParametersCmd)
+	importCmd.AddCommand(importCFManifestCmd)
+	exportCmd.AddCommand(exportCFManifestCmd, exportK8sCmd)
+	generateCmd.AddCommand(generateVcapCmd)
 
 }
 
@@ -50,3 +56,27 @@ var updateCmd = &cobra.Command{
 	Hidden: true,
 	Run:    nil,
 }
+
+// The parent command imports an MTA from other descriptors.
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import an MTA",
+	Long:  "Import an MTA from other descriptors",
+	Run:   nil,
+}
+
+// The parent command exports the MTA to other descriptors.
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export an MTA",
+	Long:  "Export an MTA to other descriptors",
+	Run:   nil,
+}
+
+// The parent command generates artifacts for the development of the MTA.
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate development artifacts",
+	Long:  "Generate artifacts for the local development of the MTA",
+	Run:   nil,
+}
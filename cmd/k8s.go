@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/SAP/cloud-mta/internal/k8s"
+	"github.com/SAP/cloud-mta/internal/logs"
+)
+
+var exportK8sCmdPath string
+var exportK8sCmdExtensions []string
+var exportK8sCmdWorkspaceDir string
+var exportK8sCmdModules []string
+var exportK8sCmdEnvFileName string
+var exportK8sCmdMapping string
+var exportK8sCmdTarget string
+
+func init() {
+	exportK8sCmd.Flags().StringVarP(&exportK8sCmdPath, "path", "p", "",
+		"the path to the mta.yaml file")
+	exportK8sCmd.Flags().StringSliceVarP(&exportK8sCmdExtensions, "extensions", "x", nil,
+		"the paths to the MTA extension descriptors")
+	exportK8sCmd.Flags().StringVarP(&exportK8sCmdWorkspaceDir, "workspace", "w", "",
+		"the path to the project folder; the default path is the folder of the mta.yaml file")
+	exportK8sCmd.Flags().StringSliceVarP(&exportK8sCmdModules, "module", "m", nil,
+		"the names of the exported modules; by default all the modules are exported")
+	exportK8sCmd.Flags().StringVarP(&exportK8sCmdEnvFileName, "envFile", "e", "",
+		"the environment file path, relative to the module folder; the default file path is \".env\"")
+	exportK8sCmd.Flags().StringVarP(&exportK8sCmdMapping, "config", "c", "",
+		"the path to the mapping configuration file, which defines the images, ports and namespaces of the modules")
+	exportK8sCmd.Flags().StringVarP(&exportK8sCmdTarget, "target", "t", "",
+		"the path to the generated Kubernetes manifest file; the default path is \"k8s.yaml\" in the project folder")
+}
+
+// exportK8sCmd - generates Kubernetes manifests from the MTA modules and resources.
+var exportK8sCmd = &cobra.Command{
+	Use:   "k8s",
+	Short: "Export MTA modules as Kubernetes manifests",
+	Long: `Generates Kubernetes manifests from MTA modules and the resources they require.
+Each module is exported as a deployment and a service, and its resolved properties are exported as a config map;
+properties marked as sensitive in the properties metadata are exported as a secret.
+Each required resource is exported as a placeholder service instance and service binding of the SAP BTP service operator.
+The module images, ports and namespaces are defined in the mapping configuration file.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logs.Logger.Info("Export Kubernetes manifests")
+		messages, err := k8s.Export(exportK8sCmdWorkspaceDir, exportK8sCmdPath, exportK8sCmdExtensions,
+			exportK8sCmdModules, exportK8sCmdEnvFileName, exportK8sCmdMapping, exportK8sCmdTarget)
+		for _, message := range messages {
+			logs.Logger.Warn(message)
+		}
+		if err != nil {
+			logs.Logger.Error(err)
+		}
+		return err
+	},
+	Hidden:        false,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+}
@@ -0,0 +1,26 @@
+package commands
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Export K8s", func() {
+
+	AfterEach(func() {
+		os.RemoveAll(getTestPath("result"))
+	})
+
+	It("Sanity", func() {
+		Ω(os.MkdirAll(getTestPath("result"), os.ModePerm)).Should(Succeed())
+		exportK8sCmdPath = getTestPath("mta.yaml")
+		exportK8sCmdModules = []string{"backend"}
+		exportK8sCmdTarget = getTestPath("result", "k8s.yaml")
+		Ω(exportK8sCmd.RunE(nil, []string{})).Should(Succeed())
+		Ω(exportK8sCmdTarget).Should(BeAnExistingFile())
+		exportK8sCmdModules = []string{"unknown"}
+		Ω(exportK8sCmd.RunE(nil, []string{})).Should(HaveOccurred())
+	})
+})
@@ -33,6 +33,10 @@ var updateParametersCmdHashcode int
 var getMtaIDCmdPath string
 var validateMtaCmdPath string
 var validateMtaCmdExtensions []string
+var validateMtaCmdSchemaEngine string
+var validateMtaCmdFormat string
+
+const validateMtaErrorsMsg = "the validation found %d errors"
 
 func init() {
 
@@ -91,6 +95,10 @@ func init() {
 		"the path to the yaml file")
 	validateMtaCmd.Flags().StringSliceVarP(&validateMtaCmdExtensions, "extensions", "x", nil,
 		"the paths to the MTA extension descriptors")
+	validateMtaCmd.Flags().StringVarP(&validateMtaCmdSchemaEngine, "schema", "s", "",
+		`the engine of the schema validation: "kwalify" (default) or "json-schema", which uses the JSON schema of the IDE`)
+	validateMtaCmd.Flags().StringVarP(&validateMtaCmdFormat, "format", "f", "",
+		`the output format: "json" (default), "sarif", "junit", "checkstyle" or "github" (GitHub Actions annotations); the command fails when the report has errors, except in the JSON format`)
 
 }
 
@@ -282,9 +290,39 @@ var validateMtaCmd = &cobra.Command{
 	Long:  "Validate mta.yaml file and MTA extension files",
 	Args:  cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if validateMtaCmdFormat != "" && validateMtaCmdFormat != validate.JSONFormat {
+			logs.Logger.Info("validate MTA")
+			result, err := validate.ValidateWithOptions(validateMtaCmdPath, validateMtaCmdExtensions,
+				validate.ValidateOptions{SchemaEngine: validateMtaCmdSchemaEngine})
+			if err != nil {
+				return err
+			}
+			output, err := validate.FormatResult(result, validateMtaCmdFormat)
+			if err != nil {
+				return err
+			}
+			if _, err = fmt.Fprint(os.Stdout, output); err != nil {
+				return err
+			}
+			// The command fails when there are errors, so the report can be used to fail a build
+			errorsCount := 0
+			for _, issues := range result {
+				for _, issue := range issues {
+					if issue.Severity == validate.SeverityError {
+						errorsCount++
+					}
+				}
+			}
+			if errorsCount > 0 {
+				return fmt.Errorf(validateMtaErrorsMsg, errorsCount)
+			}
+			return nil
+		}
 		// Extensions don't change the mta ID
 		return mta.RunAndWriteResultAndHash("validate MTA", validateMtaCmdPath, validateMtaCmdExtensions, func() (interface{}, []string, error) {
-			return validate.Validate(validateMtaCmdPath, validateMtaCmdExtensions), nil, nil
+			result, err := validate.ValidateWithOptions(validateMtaCmdPath, validateMtaCmdExtensions,
+				validate.ValidateOptions{SchemaEngine: validateMtaCmdSchemaEngine})
+			return result, nil, err
 		})
 	},
 	Hidden:        true,
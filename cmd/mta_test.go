@@ -2,12 +2,15 @@ package commands
 
 import (
 	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"os"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
 	"github.com/SAP/cloud-mta/mta"
+	"github.com/SAP/cloud-mta/validations"
 )
 
 var _ = Describe("Resource", func() {
@@ -43,3 +46,38 @@ var _ = Describe("Resource", func() {
 		Ω(deleteMtaCmd.RunE(nil, []string{})).Should(Succeed())
 	})
 })
+
+var _ = Describe("Validate MTA", func() {
+
+	BeforeEach(func() {
+		validateMtaCmdPath = getTestPath("result", "mta.yaml")
+		validateMtaCmdExtensions = nil
+		validateMtaCmdSchemaEngine = ""
+		validateMtaCmdFormat = validate.GitHubFormat
+		Ω(os.MkdirAll(getTestPath("result", "srv"), os.ModePerm)).Should(Succeed())
+		Ω(ioutil.WriteFile(validateMtaCmdPath, []byte(`ID: abc
+_schema-version: '3.2'
+version: 1.0.0
+modules:
+- name: srv
+  type: nodejs
+  path: srv
+`), os.ModePerm)).Should(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(getTestPath("result"))
+		validateMtaCmdFormat = ""
+	})
+
+	It("fails after printing the report when there are errors", func() {
+		Ω(ioutil.WriteFile(validateMtaCmdPath, []byte(`_schema-version: '3.2'
+version: 1.0.0
+`), os.ModePerm)).Should(Succeed())
+		Ω(validateMtaCmd.RunE(nil, []string{})).Should(MatchError(fmt.Sprintf(validateMtaErrorsMsg, 1)))
+	})
+
+	It("succeeds when there are no errors", func() {
+		Ω(validateMtaCmd.RunE(nil, []string{})).Should(Succeed())
+	})
+})
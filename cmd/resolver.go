@@ -2,18 +2,40 @@ package commands
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
 	"github.com/SAP/cloud-mta/internal/logs"
 	"github.com/SAP/cloud-mta/internal/resolver"
 	"github.com/SAP/cloud-mta/mta"
-	"github.com/spf13/cobra"
+)
+
+const (
+	resolveNoModulesMsg    = "provide the names of the modules or use the --all flag"
+	resolveHookModuleMsg   = "provide the name of the hook's module with the --module flag"
+	resolveResourceFlagMsg = "the --resource flag cannot be used with the --module, --all or --hook flags"
+	resolveTargetFileMsg   = "the --file flag cannot be used with the --hook or --resource flags"
 )
 
 var resolveCmdPath string
 var resolveCmdExtensions []string
 var resolveCmdWorkspaceDir string
-var resolveCmdModule string
-var resolveCmdEnvFileName string
+var resolveCmdModules []string
+var resolveCmdAll bool
+var resolveCmdEnvFiles []string
 var resolveCmdOutputFormat string
+var resolveCmdFile string
+var resolveCmdProfile string
+var resolveCmdDeployerCompatible bool
+var resolveCmdMaxDepth int
+var resolveCmdTrace bool
+var resolveCmdHook string
+var resolveCmdResource string
 
 func init() {
 	resolveMtaCmd.Flags().StringVarP(&resolveCmdPath, "path", "p", "",
@@ -22,14 +44,28 @@ func init() {
 		"the paths to the MTA extension descriptors")
 	resolveMtaCmd.Flags().StringVarP(&resolveCmdWorkspaceDir, "workspace", "w", "",
 		"the path to the project folder; the default path is the folder of the mta.yaml file")
-	resolveMtaCmd.Flags().StringVarP(&resolveCmdModule, "module", "m", "",
-		"the module name")
-	resolveMtaCmd.Flags().StringVarP(&resolveCmdEnvFileName, "envFile", "e", "",
-		"the environment file path, relative to the module folder; the default file path is \".env\"")
+	resolveMtaCmd.Flags().StringSliceVarP(&resolveCmdModules, "module", "m", nil,
+		"the module names")
+	resolveMtaCmd.Flags().BoolVarP(&resolveCmdAll, "all", "a", false,
+		"resolve all the modules")
+	resolveMtaCmd.Flags().StringSliceVarP(&resolveCmdEnvFiles, "envFile", "e", nil,
+		"the environment file paths, relative to the module folder; the values of later files take precedence, e.g. -e .env,.env.local,.env.prod; the default file path is \".env\"")
 	resolveMtaCmd.Flags().StringVarP(&resolveCmdOutputFormat, "output", "o", "",
-		"the output format; use \"json\" for json-formatted output")
-	_ = resolveMtaCmd.Flags().MarkHidden("output")
-
+		`the output format: "dotenv", "shell" (export commands), "default-env" (default-env.json) or "json" (the result and the messages, as used by tools)`)
+	resolveMtaCmd.Flags().StringVarP(&resolveCmdFile, "file", "f", "",
+		"the name of the file written to each module's folder, instead of printing to stdout; the default output format of the file is \"dotenv\"")
+	resolveMtaCmd.Flags().StringVar(&resolveCmdProfile, "profile", "",
+		"the path to the platform profile, which defines the values of placeholders provided by the deployer, like ${org} and ${default-domain}")
+	resolveMtaCmd.Flags().BoolVar(&resolveCmdDeployerCompatible, "deployerCompatible", false,
+		"resolve like the deployer: structured values inside strings are rendered as {key=value} instead of JSON, and non-string parameters are used as whole values")
+	resolveMtaCmd.Flags().IntVar(&resolveCmdMaxDepth, "maxDepth", resolver.DefaultMaxDepth,
+		"the maximum depth of nested values and placeholder references which are resolved")
+	resolveMtaCmd.Flags().StringVar(&resolveCmdHook, "hook", "",
+		"the name of the module's hook; the hook's parameters and the environment variables of its task are resolved")
+	resolveMtaCmd.Flags().StringVar(&resolveCmdResource, "resource", "",
+		"the name of the resource; the resource's parameters and properties are resolved")
+	resolveMtaCmd.Flags().BoolVar(&resolveCmdTrace, "trace", false,
+		"print how each property was resolved to stderr: the scopes in which its placeholders and variables were looked up and where the values were found; in the json output format the trace is part of the result")
 }
 
 // createMtaCmd Create new MTA project
@@ -37,35 +73,64 @@ var resolveMtaCmd = &cobra.Command{
 	Use:   "resolve",
 	Short: "Resolve variables and placeholders in an MTA file",
 	Long: `The MTA file typically contains variables in the form ~{var-name} and placeholders in the form ${placeholder}.
-The resolve command prints the module's properties from the MTA file to stdout, with variables and placeholders replaced with concrete values, based on environment variables and an environment file.`,
+The resolve command prints the modules' properties from the MTA file to stdout, with variables and placeholders replaced with concrete values, based on environment variables, the default-env.json file in the module folder and an environment file.
+Placeholders that the deployer derives from the application, like ${default-url} and ${app-name}, are resolved from the VCAP_APPLICATION variable.
+The properties can be printed as a .env file, a shell script or a default-env.json file, or written to a file in each module's folder.
+A module's hook or a resource can be resolved instead of the modules, with the --hook or --resource flags.`,
 	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(resolveCmdHook) > 0 || len(resolveCmdResource) > 0 {
+			return resolveTarget()
+		}
+		singleModule := !resolveCmdAll && len(resolveCmdModules) <= 1
 		if resolveCmdOutputFormat == "json" {
 			return mta.RunAndWriteResultAndHash(
 				"Resolve MTA",
 				resolveCmdPath,
 				resolveCmdExtensions,
 				func() (interface{}, []string, error) {
-					return resolver.Resolve(resolveCmdWorkspaceDir, resolveCmdModule, resolveCmdPath, resolveCmdExtensions, resolveCmdEnvFileName)
+					options, err := getResolveOptions()
+					if err != nil {
+						return nil, nil, err
+					}
+					if singleModule {
+						return resolver.Resolve(resolveCmdWorkspaceDir, getResolveCmdModule(), resolveCmdPath, resolveCmdExtensions, "", options)
+					}
+					return resolver.ResolveModules(resolveCmdWorkspaceDir, getResolveCmdModuleNames(), resolveCmdPath, resolveCmdExtensions, "", options)
 				},
 			)
 		}
 
-		// Just write to the output (this option is here for backwards compatibility)
+		if singleModule && len(resolveCmdOutputFormat) == 0 && len(resolveCmdFile) == 0 {
+			// Just write to the output (this option is here for backwards compatibility)
+			logs.Logger.Info("Resolve MTA")
+			options, err := getResolveOptions()
+			if err != nil {
+				logs.Logger.Error(err)
+				return err
+			}
+			result, messages, err := resolver.Resolve(resolveCmdWorkspaceDir, getResolveCmdModule(), resolveCmdPath, resolveCmdExtensions, "", options)
+			if err != nil {
+				logs.Logger.Error(err)
+			} else {
+				for key, val := range result.Properties {
+					fmt.Println(key + "=" + val)
+				}
+				for _, message := range messages {
+					logs.Logger.Warn(message)
+				}
+				for _, message := range result.Messages {
+					logs.Logger.Warn(message)
+				}
+				fmt.Fprint(os.Stderr, resolver.FormatTrace(result.Trace))
+			}
+			return err
+		}
+
 		logs.Logger.Info("Resolve MTA")
-		result, messages, err := resolver.Resolve(resolveCmdWorkspaceDir, resolveCmdModule, resolveCmdPath, resolveCmdExtensions, resolveCmdEnvFileName)
+		err := resolveModules()
 		if err != nil {
 			logs.Logger.Error(err)
-		} else {
-			for key, val := range result.Properties {
-				fmt.Println(key + "=" + val)
-			}
-			for _, message := range messages {
-				logs.Logger.Warn(message)
-			}
-			for _, message := range result.Messages {
-				logs.Logger.Warn(message)
-			}
 		}
 		return err
 	},
@@ -73,3 +138,168 @@ The resolve command prints the module's properties from the MTA file to stdout,
 	SilenceUsage:  true,
 	SilenceErrors: true,
 }
+
+// resolveTarget resolves a hook or a resource and prints it in the output format
+func resolveTarget() error {
+	if len(resolveCmdResource) > 0 && (len(resolveCmdHook) > 0 || len(resolveCmdModules) > 0 || resolveCmdAll) {
+		return errors.New(resolveResourceFlagMsg)
+	}
+	if len(resolveCmdHook) > 0 && (len(resolveCmdModules) != 1 || resolveCmdAll) {
+		return errors.New(resolveHookModuleMsg)
+	}
+	if len(resolveCmdFile) > 0 {
+		return errors.New(resolveTargetFileMsg)
+	}
+
+	name := resolveCmdResource
+	resolve := func() (resolver.ResolveResult, []string, error) {
+		options, err := getResolveOptions()
+		if err != nil {
+			return resolver.ResolveResult{}, nil, err
+		}
+		if len(resolveCmdResource) > 0 {
+			return resolver.ResolveResource(resolveCmdWorkspaceDir, resolveCmdResource, resolveCmdPath, resolveCmdExtensions, options)
+		}
+		return resolver.ResolveHook(resolveCmdWorkspaceDir, resolveCmdModules[0], resolveCmdHook, resolveCmdPath, resolveCmdExtensions, "", options)
+	}
+	if len(resolveCmdHook) > 0 {
+		name = resolveCmdModules[0] + "/" + resolveCmdHook
+	}
+
+	if resolveCmdOutputFormat == "json" {
+		return mta.RunAndWriteResultAndHash("Resolve MTA", resolveCmdPath, resolveCmdExtensions,
+			func() (interface{}, []string, error) {
+				return resolve()
+			})
+	}
+
+	logs.Logger.Info("Resolve MTA")
+	err := printTarget(name, resolve)
+	if err != nil {
+		logs.Logger.Error(err)
+	}
+	return err
+}
+
+func printTarget(name string, resolve func() (resolver.ResolveResult, []string, error)) error {
+	format := resolveCmdOutputFormat
+	if len(format) == 0 {
+		format = resolver.DotenvFormat
+	}
+	if err := resolver.ValidateFormat(format); err != nil {
+		return err
+	}
+	result, messages, err := resolve()
+	if err != nil {
+		return err
+	}
+	content, formatMessages, err := resolver.FormatModule(resolver.ModuleResolveResult{Name: name, ResolveResult: result}, format)
+	if err != nil {
+		return err
+	}
+	fmt.Print(content)
+	if len(result.Parameters) > 0 && format != resolver.DefaultEnvFormat {
+		// The parameters are not environment variables, so they are printed as comments
+		fmt.Println("# parameters:")
+		for _, key := range sortedKeys(result.Parameters) {
+			fmt.Println("# " + key + ": " + strings.Replace(result.Parameters[key], "\n", `\n`, -1))
+		}
+	}
+	for _, message := range append(append(messages, result.Messages...), formatMessages...) {
+		logs.Logger.Warn(message)
+	}
+	if resolveCmdTrace {
+		fmt.Fprint(os.Stderr, resolver.FormatTrace(result.Trace))
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func getResolveOptions() (*resolver.ResolveOptions, error) {
+	profile, err := resolver.ReadProfile(resolveCmdProfile)
+	if err != nil {
+		return nil, err
+	}
+	// The environment files are read by the environment sources, so they are not passed to the resolve functions
+	return &resolver.ResolveOptions{
+		EnvSources:         resolver.DefaultEnvSources(resolveCmdEnvFiles...),
+		Profile:            profile,
+		DeployerCompatible: resolveCmdDeployerCompatible,
+		MaxDepth:           resolveCmdMaxDepth,
+		Trace:              resolveCmdTrace,
+	}, nil
+}
+
+func getResolveCmdModule() string {
+	if len(resolveCmdModules) == 0 {
+		return ""
+	}
+	return resolveCmdModules[0]
+}
+
+// getResolveCmdModuleNames returns the names of the resolved modules; all the modules are resolved when no names are returned
+func getResolveCmdModuleNames() []string {
+	if resolveCmdAll {
+		return nil
+	}
+	return resolveCmdModules
+}
+
+// resolveModules resolves the selected modules and prints them or writes them to the modules' folders in the output format
+func resolveModules() error {
+	if !resolveCmdAll && len(resolveCmdModules) == 0 {
+		return errors.New(resolveNoModulesMsg)
+	}
+	format := resolveCmdOutputFormat
+	if len(format) == 0 {
+		format = resolver.DotenvFormat
+	}
+	if err := resolver.ValidateFormat(format); err != nil {
+		return err
+	}
+	options, err := getResolveOptions()
+	if err != nil {
+		return err
+	}
+	results, messages, err := resolver.ResolveModules(resolveCmdWorkspaceDir, getResolveCmdModuleNames(), resolveCmdPath, resolveCmdExtensions, "", options)
+	if err != nil {
+		return err
+	}
+	for _, result := range results {
+		for _, message := range result.Messages {
+			messages = append(messages, result.Name+": "+message)
+		}
+	}
+
+	var formatMessages []string
+	if len(resolveCmdFile) > 0 {
+		workspaceDir := resolveCmdWorkspaceDir
+		if len(workspaceDir) == 0 {
+			workspaceDir = filepath.Dir(resolveCmdPath)
+		}
+		formatMessages, err = resolver.WriteModuleFiles(workspaceDir, results, format, resolveCmdFile)
+	} else {
+		var content string
+		content, formatMessages, err = resolver.FormatModules(results, format)
+		if err == nil {
+			fmt.Print(content)
+		}
+	}
+	for _, message := range append(messages, formatMessages...) {
+		logs.Logger.Warn(message)
+	}
+	if resolveCmdTrace {
+		for _, result := range results {
+			fmt.Fprint(os.Stderr, "# "+result.Name+"\n"+resolver.FormatTrace(result.Trace))
+		}
+	}
+	return err
+}
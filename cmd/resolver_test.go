@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/SAP/cloud-mta/internal/resolver"
+)
+
+var _ = Describe("Resolve", func() {
+
+	BeforeEach(func() {
+		resolveCmdPath = getTestPath("mta.yaml")
+		resolveCmdModules = nil
+		resolveCmdAll = false
+		resolveCmdOutputFormat = ""
+		resolveCmdFile = ""
+		resolveCmdTrace = false
+		resolveCmdHook = ""
+		resolveCmdResource = ""
+		resolveCmdEnvFiles = nil
+	})
+
+	It("resolves all the modules", func() {
+		resolveCmdAll = true
+		resolveCmdOutputFormat = resolver.ShellFormat
+		Ω(resolveMtaCmd.RunE(nil, []string{})).Should(Succeed())
+	})
+
+	It("resolves a single module", func() {
+		resolveCmdModules = []string{"backend"}
+		Ω(resolveMtaCmd.RunE(nil, []string{})).Should(Succeed())
+	})
+
+	It("resolves modules with several environment files", func() {
+		resolveCmdAll = true
+		resolveCmdEnvFiles = []string{".env", ".env.local"}
+		Ω(resolveMtaCmd.RunE(nil, []string{})).Should(Succeed())
+	})
+
+	It("resolves modules with the trace", func() {
+		resolveCmdAll = true
+		resolveCmdTrace = true
+		Ω(resolveMtaCmd.RunE(nil, []string{})).Should(Succeed())
+	})
+
+	It("resolves a resource", func() {
+		resolveCmdResource = "database"
+		resolveCmdOutputFormat = resolver.ShellFormat
+		Ω(resolveMtaCmd.RunE(nil, []string{})).Should(Succeed())
+	})
+
+	It("returns error when a resource and modules are selected", func() {
+		resolveCmdResource = "database"
+		resolveCmdAll = true
+		Ω(resolveMtaCmd.RunE(nil, []string{})).Should(MatchError(resolveResourceFlagMsg))
+	})
+
+	It("returns error when the module of a hook is not selected", func() {
+		resolveCmdHook = "migrate"
+		Ω(resolveMtaCmd.RunE(nil, []string{})).Should(MatchError(resolveHookModuleMsg))
+	})
+
+	It("returns error when the hook does not exist", func() {
+		resolveCmdHook = "migrate"
+		resolveCmdModules = []string{"backend"}
+		Ω(resolveMtaCmd.RunE(nil, []string{})).Should(MatchError(`could not find the "migrate" hook of the "backend" module`))
+	})
+
+	It("returns error when no modules are selected", func() {
+		resolveCmdOutputFormat = resolver.DotenvFormat
+		Ω(resolveMtaCmd.RunE(nil, []string{})).Should(MatchError(resolveNoModulesMsg))
+	})
+
+	It("returns error for an unknown output format", func() {
+		resolveCmdAll = true
+		resolveCmdOutputFormat = "xml"
+		err := resolveMtaCmd.RunE(nil, []string{})
+		Ω(err).Should(MatchError(fmt.Sprintf(`the "xml" output format is not supported; use one of: %s`, strings.Join(resolver.Formats, ", "))))
+	})
+})
@@ -0,0 +1,121 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/SAP/cloud-mta/internal/logs"
+	"github.com/SAP/cloud-mta/internal/resolver"
+	"github.com/SAP/cloud-mta/internal/vcap"
+)
+
+const (
+	vcapJSONFormat        = "json"
+	generateVcapFormatMsg = `the "%s" output format is not supported; use one of: %s`
+)
+
+var generateVcapCmdPath string
+var generateVcapCmdExtensions []string
+var generateVcapCmdWorkspaceDir string
+var generateVcapCmdModules []string
+var generateVcapCmdSecrets string
+var generateVcapCmdOutputFormat string
+var generateVcapCmdTarget string
+var generateVcapCmdProfile string
+
+func init() {
+	generateVcapCmd.Flags().StringVarP(&generateVcapCmdPath, "path", "p", "",
+		"the path to the mta.yaml file")
+	generateVcapCmd.Flags().StringSliceVarP(&generateVcapCmdExtensions, "extensions", "x", nil,
+		"the paths to the MTA extension descriptors")
+	generateVcapCmd.Flags().StringVarP(&generateVcapCmdWorkspaceDir, "workspace", "w", "",
+		"the path to the project folder; the default path is the folder of the mta.yaml file")
+	generateVcapCmd.Flags().StringSliceVarP(&generateVcapCmdModules, "module", "m", nil,
+		"the names of the modules whose required resources are generated; by default the resources required by all the modules are generated")
+	generateVcapCmd.Flags().StringVarP(&generateVcapCmdSecrets, "secrets", "s", "",
+		"the path to the secrets file, in YAML or JSON format, which defines the credentials of the services by resource name")
+	generateVcapCmd.Flags().StringVarP(&generateVcapCmdOutputFormat, "output", "o", "",
+		`the output format: "json" (the VCAP_SERVICES value), "dotenv", "shell" (an export command) or "default-env" (default-env.json); the default format is "json"`)
+	generateVcapCmd.Flags().StringVarP(&generateVcapCmdTarget, "target", "t", "",
+		"the path to the generated file, instead of printing to stdout")
+	generateVcapCmd.Flags().StringVar(&generateVcapCmdProfile, "profile", "",
+		"the path to the platform profile, which defines the values of placeholders provided by the deployer, like ${org} and ${space}")
+}
+
+// generateVcapCmd - generates a VCAP_SERVICES variable from the resources required by the modules.
+var generateVcapCmd = &cobra.Command{
+	Use:   "vcap",
+	Short: "Generate the VCAP_SERVICES variable for local development",
+	Long: `Generates a VCAP_SERVICES variable with the service instances of the resources required by the modules.
+Each service is derived from the resource's type and its service, service-plan, service-name and service-tags parameters, and has the "mta-resource-name:" tag by which the resolve command matches services to resources.
+The credentials are placeholders, unless they are defined for the resource in the secrets file.
+The variable can be written as a default-env.json file to the module folder, or passed to the resolve command as an environment variable.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logs.Logger.Info("Generate VCAP_SERVICES")
+		err := generateVcap()
+		if err != nil {
+			logs.Logger.Error(err)
+		}
+		return err
+	},
+	Hidden:        false,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+}
+
+func generateVcap() error {
+	format := generateVcapCmdOutputFormat
+	if len(format) == 0 {
+		format = vcapJSONFormat
+	}
+	if format != vcapJSONFormat && resolver.ValidateFormat(format) != nil {
+		return errors.Errorf(generateVcapFormatMsg, format, strings.Join(append([]string{vcapJSONFormat}, resolver.Formats...), ", "))
+	}
+	profile, err := resolver.ReadProfile(generateVcapCmdProfile)
+	if err != nil {
+		return err
+	}
+	services, messages, err := vcap.Generate(generateVcapCmdWorkspaceDir, generateVcapCmdPath, generateVcapCmdExtensions,
+		generateVcapCmdModules, generateVcapCmdSecrets, &resolver.ResolveOptions{Profile: profile})
+	for _, message := range messages {
+		logs.Logger.Warn(message)
+	}
+	if err != nil {
+		return err
+	}
+	content, err := formatVcap(services, format)
+	if err != nil {
+		return err
+	}
+	if len(generateVcapCmdTarget) > 0 {
+		return ioutil.WriteFile(generateVcapCmdTarget, []byte(content), 0644)
+	}
+	fmt.Print(content)
+	return nil
+}
+
+// formatVcap returns the VCAP_SERVICES variable in the output format; in the json format only its value is returned
+func formatVcap(services vcap.Services, format string) (string, error) {
+	value, err := vcap.Marshal(services)
+	if err != nil {
+		return "", err
+	}
+	if format == vcapJSONFormat {
+		var buf bytes.Buffer
+		err = json.Indent(&buf, []byte(value), "", "  ")
+		return buf.String() + "\n", err
+	}
+	result := resolver.ModuleResolveResult{
+		Name:          vcap.ServicesVariable,
+		ResolveResult: resolver.ResolveResult{Properties: map[string]string{vcap.ServicesVariable: value}},
+	}
+	content, _, err := resolver.FormatModule(result, format)
+	return content, err
+}
@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate VCAP_SERVICES", func() {
+
+	BeforeEach(func() {
+		generateVcapCmdPath = getTestPath("mta.yaml")
+		generateVcapCmdModules = []string{"backend"}
+		generateVcapCmdOutputFormat = ""
+		generateVcapCmdTarget = ""
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(getTestPath("result"))
+	})
+
+	It("prints the VCAP_SERVICES value", func() {
+		Ω(generateVcapCmd.RunE(nil, []string{})).Should(Succeed())
+	})
+
+	It("writes the VCAP_SERVICES variable as a default-env.json file", func() {
+		Ω(os.MkdirAll(getTestPath("result"), os.ModePerm)).Should(Succeed())
+		generateVcapCmdOutputFormat = "default-env"
+		generateVcapCmdTarget = getTestPath("result", "default-env.json")
+		Ω(generateVcapCmd.RunE(nil, []string{})).Should(Succeed())
+		content, err := ioutil.ReadFile(generateVcapCmdTarget)
+		Ω(err).Should(Succeed())
+		env := map[string]map[string]interface{}{}
+		Ω(json.Unmarshal(content, &env)).Should(Succeed())
+		Ω(env).Should(HaveKey("VCAP_SERVICES"))
+	})
+
+	It("returns error for an unknown output format", func() {
+		generateVcapCmdOutputFormat = "xml"
+		Ω(generateVcapCmd.RunE(nil, []string{})).Should(MatchError(`the "xml" output format is not supported; use one of: json, dotenv, shell, default-env`))
+	})
+
+	It("returns error when the module does not exist", func() {
+		generateVcapCmdModules = []string{"unknown"}
+		Ω(generateVcapCmd.RunE(nil, []string{})).Should(HaveOccurred())
+	})
+})
@@ -1,5 +1,10 @@
 package main
 
-//go:generate go run ./tools/embed.go -source=./validations/schema/schema_v3.2.yaml -target=./validations/mta_schema.go -name=schemaDef -package=validate
-//go:generate go run ./tools/embed.go -source=./validations/schema/mtaext-schema_v3.2.yaml -target=./validations/mtaext_schema.go -name=extSchemaDef -package=validate
+//go:generate go run ./tools/embed.go -source=./validations/schema/schema_v3.1.yaml -target=./validations/mta_schema_v3_1.go -name=schemaDefV31 -package=validate
+//go:generate go run ./tools/embed.go -source=./validations/schema/schema_v3.2.yaml -target=./validations/mta_schema_v3_2.go -name=schemaDefV32 -package=validate
+//go:generate go run ./tools/embed.go -source=./validations/schema/schema_v3.3.yaml -target=./validations/mta_schema_v3_3.go -name=schemaDefV33 -package=validate
+//go:generate go run ./tools/embed.go -source=./validations/schema/mtaext-schema_v3.1.yaml -target=./validations/mtaext_schema_v3_1.go -name=extSchemaDefV31 -package=validate
+//go:generate go run ./tools/embed.go -source=./validations/schema/mtaext-schema_v3.2.yaml -target=./validations/mtaext_schema_v3_2.go -name=extSchemaDefV32 -package=validate
+//go:generate go run ./tools/embed.go -source=./validations/schema/mtaext-schema_v3.3.yaml -target=./validations/mtaext_schema_v3_3.go -name=extSchemaDefV33 -package=validate
+//go:generate go run ./tools/embed.go -source=./lsp/schema.json -target=./validations/mta_json_schema.go -name=jsonSchemaDef -package=validate
 //go:generate go run ./tools/embed.go -source=./configs/version.yaml -target=./internal/version/version_cfg.go -name=VersionConfig -package=version
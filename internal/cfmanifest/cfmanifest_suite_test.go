@@ -0,0 +1,23 @@
+package cfmanifest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/SAP/cloud-mta/internal/logs"
+)
+
+func TestCfManifest(t *testing.T) {
+	logs.NewLogger()
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "CF Manifest Suite")
+}
+
+func getTestPath(relPath ...string) string {
+	wd, _ := os.Getwd()
+	return filepath.Join(wd, "testdata", filepath.Join(relPath...))
+}
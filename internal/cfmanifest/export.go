@@ -0,0 +1,173 @@
+package cfmanifest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/SAP/cloud-mta/internal/export"
+	"github.com/SAP/cloud-mta/internal/resolver"
+	"github.com/SAP/cloud-mta/mta"
+)
+
+const (
+	invalidParameterMsg = `the "%s" parameter of the "%s" module is not valid; it was ignored`
+
+	defaultManifestFileName = "manifest.yml"
+)
+
+// Export converts the modules of the MTA to a Cloud Foundry manifest and writes it to the target path.
+// When no modules are sent, all the modules are exported. The returned messages describe values that could not be
+// resolved or converted.
+func Export(workspaceDir, path string, extensions []string, moduleNames []string, envFile string, target string) ([]string, error) {
+	if len(workspaceDir) == 0 {
+		workspaceDir = filepath.Dir(path)
+	}
+	if len(target) == 0 {
+		target = filepath.Join(workspaceDir, defaultManifestFileName)
+	}
+	manifest, messages, err := ExportModules(workspaceDir, path, extensions, moduleNames, envFile, filepath.Dir(target))
+	if err != nil {
+		return messages, err
+	}
+	manifestBytes, err := Marshal(manifest)
+	if err != nil {
+		return messages, err
+	}
+	return messages, ioutil.WriteFile(target, manifestBytes, 0644)
+}
+
+// ExportModules converts the modules of the MTA to Cloud Foundry manifest applications, after merging the extensions
+// and resolving the modules' properties and parameters. Application paths are relative to the manifest folder.
+func ExportModules(workspaceDir, path string, extensions []string, moduleNames []string, envFile string, manifestDir string) (*Manifest, []string, error) {
+	mtaObj, messages, err := mta.GetMtaFromFile(path, extensions, false)
+	if err != nil {
+		return nil, messages, err
+	}
+	if len(workspaceDir) == 0 {
+		workspaceDir = filepath.Dir(path)
+	}
+
+	modules, err := export.GetModules(mtaObj, moduleNames)
+	if err != nil {
+		return nil, messages, err
+	}
+
+	m := resolver.NewMTAResolver(mtaObj, workspaceDir)
+	manifest := &Manifest{}
+	for _, module := range modules {
+		env, err := m.ResolveModule(module, envFile)
+		if err != nil {
+			return nil, messages, err
+		}
+		app, appMessages := moduleToApplication(module, env, workspaceDir, manifestDir)
+		messages = append(messages, appMessages...)
+		manifest.Applications = append(manifest.Applications, app)
+	}
+	for _, resource := range mtaObj.Resources {
+		m.ResolveResourceProperties(resource)
+	}
+	// The service names are only known after the resources are resolved
+	for i, module := range modules {
+		manifest.Applications[i].Services = getServiceBindings(module, mtaObj)
+	}
+	messages = append(messages, m.Messages()...)
+
+	return manifest, messages, nil
+}
+
+func moduleToApplication(module *mta.Module, env map[string]string, workspaceDir, manifestDir string) (*Application, []string) {
+	var messages []string
+	app := &Application{Name: module.Name}
+	params := module.Parameters
+
+	if appName, ok := params["app-name"].(string); ok && len(appName) > 0 {
+		app.Name = appName
+	}
+	if len(module.Path) > 0 {
+		app.Path = getApplicationPath(filepath.Join(workspaceDir, module.Path), manifestDir)
+	}
+
+	app.Memory = export.GetStringParameter(params, "memory")
+	app.DiskQuota = export.GetStringParameter(params, "disk-quota")
+	app.Buildpack = export.GetStringParameter(params, "buildpack")
+	app.Command = export.GetStringParameter(params, "command")
+	app.Stack = export.GetStringParameter(params, "stack")
+	app.HealthCheckType = export.GetStringParameter(params, "health-check-type")
+	app.HealthCheckHTTPEndpoint = export.GetStringParameter(params, "health-check-http-endpoint")
+	app.Instances, messages = getIntParameter(module, "instances", messages)
+	app.Timeout, messages = getIntParameter(module, "health-check-timeout", messages)
+	app.NoRoute, _ = params["no-route"].(bool)
+	app.RandomRoute, _ = params["random-route"].(bool)
+
+	if routes, ok := params["routes"].([]interface{}); ok {
+		for _, route := range routes {
+			routeMap := export.ToStringMap(route)
+			routeValue, _ := routeMap["route"].(string)
+			if len(routeValue) == 0 {
+				messages = append(messages, fmt.Sprintf(invalidParameterMsg, "routes", module.Name))
+				continue
+			}
+			protocol, _ := routeMap["protocol"].(string)
+			app.Routes = append(app.Routes, Route{Route: routeValue, Protocol: protocol})
+		}
+	}
+	if docker := export.ToStringMap(params["docker"]); docker != nil {
+		image, _ := docker["image"].(string)
+		username, _ := docker["username"].(string)
+		app.Docker = &Docker{Image: image, Username: username}
+	}
+
+	if len(env) > 0 {
+		app.Env = make(map[string]interface{}, len(env))
+		for key, value := range env {
+			app.Env[key] = value
+		}
+	}
+
+	return app, messages
+}
+
+// getServiceBindings returns the services of the resources required by the module
+func getServiceBindings(module *mta.Module, mtaObj *mta.MTA) []Service {
+	var services []Service
+	for _, requires := range module.Requires {
+		resource := mtaObj.GetResourceByName(requires.Name)
+		if resource == nil || !export.IsServiceResource(resource) {
+			continue
+		}
+		service := Service{Name: export.GetServiceName(resource)}
+		if config := export.ToStringMap(requires.Parameters["config"]); config != nil {
+			service.Parameters = config
+		}
+		services = append(services, service)
+	}
+	return services
+}
+
+func getApplicationPath(modulePath string, manifestDir string) string {
+	relativePath, err := filepath.Rel(manifestDir, modulePath)
+	if err != nil {
+		return filepath.ToSlash(modulePath)
+	}
+	return filepath.ToSlash(relativePath)
+}
+
+func getIntParameter(module *mta.Module, name string, messages []string) (*int, []string) {
+	value, ok := module.Parameters[name]
+	if !ok || value == nil {
+		return nil, messages
+	}
+	switch v := value.(type) {
+	case int:
+		return &v, messages
+	case string:
+		// Numeric strings, e.g. quoted values or values of placeholders, are accepted
+		if intValue, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+			return &intValue, messages
+		}
+	}
+	return nil, append(messages, fmt.Sprintf(invalidParameterMsg, name, module.Name))
+}
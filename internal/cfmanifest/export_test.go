@@ -0,0 +1,85 @@
+package cfmanifest
+
+import (
+	"fmt"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/SAP/cloud-mta/internal/export"
+)
+
+var _ = Describe("ExportModules", func() {
+	projectPath := getTestPath("export")
+	mtaPath := getTestPath("export", "mta.yaml")
+
+	It("converts the resolved modules to applications", func() {
+		manifest, messages, err := ExportModules("", mtaPath, []string{getTestPath("export", "prod.mtaext")}, nil, "", projectPath)
+		Ω(err).Should(Succeed())
+		Ω(manifest.Applications).Should(HaveLen(2))
+
+		srv := manifest.Applications[0]
+		instances := 2
+		timeout := 180
+		Ω(*srv).Should(Equal(Application{
+			Name:      "srv",
+			Path:      "srv",
+			Memory:    "1G",
+			DiskQuota: "1G",
+			Instances: &instances,
+			Timeout:   &timeout,
+			Buildpack: "nodejs_buildpack",
+			Routes:    []Route{{Route: "srv.example.com"}},
+			Env: map[string]interface{}{
+				"LOG_LEVEL": "debug",
+				"CONFIG":    `{"url":"https://ui.example.com"}`,
+			},
+			Services: []Service{
+				{Name: "dev-hana", Parameters: map[string]interface{}{"schema": "APP"}},
+			},
+		}))
+
+		ui := manifest.Applications[1]
+		Ω(ui.Name).Should(Equal("my-ui"))
+		Ω(ui.Instances).Should(BeNil())
+		Ω(messages).Should(ContainElement(fmt.Sprintf(invalidParameterMsg, "instances", "ui")))
+	})
+
+	It("converts only the selected modules", func() {
+		manifest, _, err := ExportModules(projectPath, mtaPath, nil, []string{"ui"}, "", getTestPath())
+		Ω(err).Should(Succeed())
+		Ω(manifest.Applications).Should(HaveLen(1))
+		Ω(manifest.Applications[0].Path).Should(Equal("export/ui"))
+	})
+
+	It("returns error when a selected module does not exist", func() {
+		_, _, err := ExportModules(projectPath, mtaPath, nil, []string{"unknown"}, "", projectPath)
+		Ω(err).Should(MatchError(fmt.Sprintf(export.ModuleNotFoundMsg, "unknown")))
+	})
+
+	It("returns error when the mta.yaml file does not exist", func() {
+		_, _, err := ExportModules(projectPath, getTestPath("export", "notExisting.yaml"), nil, nil, "", projectPath)
+		Ω(err).Should(HaveOccurred())
+	})
+})
+
+var _ = Describe("Export", func() {
+	AfterEach(func() {
+		os.RemoveAll(getTestPath("result"))
+	})
+
+	It("writes a manifest file that can be read back", func() {
+		Ω(os.MkdirAll(getTestPath("result"), os.ModePerm)).Should(Succeed())
+		target := getTestPath("result", "manifest.yml")
+		_, err := Export("", getTestPath("export", "mta.yaml"), nil, []string{"srv"}, "", target)
+		Ω(err).Should(Succeed())
+		file, err := readManifestFile(target)
+		Ω(err).Should(Succeed())
+		Ω(file.manifest.Applications).Should(HaveLen(1))
+		Ω(file.manifest.Applications[0].Path).Should(Equal("../export/srv"))
+		Ω(file.manifest.Applications[0].Services).Should(Equal([]Service{
+			{Name: "dev-hana", Parameters: map[string]interface{}{"schema": "APP"}},
+		}))
+	})
+})
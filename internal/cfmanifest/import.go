@@ -0,0 +1,310 @@
+package cfmanifest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/SAP/cloud-mta/mta"
+)
+
+const (
+	noManifestsMsg            = "provide at least one Cloud Foundry manifest file"
+	duplicateApplicationMsg   = `the "%s" application is defined in both the "%s" and the "%s" manifest files`
+	missingApplicationNameMsg = `an application without a name was found in the "%s" manifest file`
+	invalidServiceOfferingMsg = `the "%s" service offering is invalid; expected the <service-instance>=<service>[:<plan>] format`
+	unsupportedManifestMsg    = `the "%s" attribute in the "%s" manifest file has no MTA equivalent; it was ignored`
+	unsupportedAttributeMsg   = `the "%s" attribute of the "%s" application has no MTA equivalent; it was ignored`
+	multipleBuildpacksMsg     = `the "%s" application has more than one buildpack; only the "%s" buildpack is used in the MTA descriptor`
+	serviceBindingNameMsg     = `the "%s" binding name of the "%s" service in the "%s" application has no MTA equivalent; it was ignored`
+
+	importedSchemaVersion = "3.2"
+	importedVersion       = "1.0.0"
+
+	managedServiceType  = "org.cloudfoundry.managed-service"
+	existingServiceType = "org.cloudfoundry.existing-service"
+	defaultModuleType   = "custom"
+)
+
+// buildpackModuleTypes - maps substrings of well-known buildpack names to MTA module types
+var buildpackModuleTypes = []struct {
+	buildpack  string
+	moduleType string
+}{
+	{"nodejs", "nodejs"},
+	{"java", "java"},
+	{"python", "python"},
+	{"staticfile", "staticfile"},
+	{"go_buildpack", "go"},
+	{"ruby", "ruby"},
+	{"php", "php"},
+	{"dotnet", "dotnet_core"},
+	{"binary", "binary"},
+}
+
+// ServiceOffering - the service and plan used to create a managed service for a service instance in the manifest
+type ServiceOffering struct {
+	Service string
+	Plan    string
+}
+
+// ParseServiceOfferings parses service offerings in the <service-instance>=<service>[:<plan>] format
+func ParseServiceOfferings(values []string) (map[string]ServiceOffering, error) {
+	result := make(map[string]ServiceOffering, len(values))
+	for _, value := range values {
+		parts := strings.SplitN(value, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, errors.Errorf(invalidServiceOfferingMsg, value)
+		}
+		serviceAndPlan := strings.SplitN(parts[1], ":", 2)
+		offering := ServiceOffering{Service: serviceAndPlan[0]}
+		if len(serviceAndPlan) == 2 {
+			offering.Plan = serviceAndPlan[1]
+		}
+		result[parts[0]] = offering
+	}
+	return result, nil
+}
+
+// Import converts the Cloud Foundry manifest files to an MTA descriptor and writes it to the path.
+// The returned messages describe the manifest features which have no MTA equivalent.
+func Import(path string, manifestPaths []string, mtaID string, offerings map[string]ServiceOffering) ([]string, error) {
+	mtaObj, messages, err := ImportManifests(filepath.Dir(path), manifestPaths, mtaID, offerings)
+	if err != nil {
+		return messages, err
+	}
+	mtaBytes, err := mta.Marshal(mtaObj)
+	if err != nil {
+		return messages, err
+	}
+	return messages, ioutil.WriteFile(path, mtaBytes, 0644)
+}
+
+// ImportManifests converts the Cloud Foundry manifest files to an MTA object.
+// Module paths are relative to the project folder. Service instances are mapped to existing services,
+// unless a service offering is defined for them, in which case they are mapped to managed services.
+func ImportManifests(projectPath string, manifestPaths []string, mtaID string, offerings map[string]ServiceOffering) (*mta.MTA, []string, error) {
+	if len(manifestPaths) == 0 {
+		return nil, nil, errors.New(noManifestsMsg)
+	}
+	if len(mtaID) == 0 {
+		mtaID = filepath.Base(projectPath)
+	}
+	schemaVersion := importedSchemaVersion
+	result := &mta.MTA{
+		SchemaVersion: &schemaVersion,
+		ID:            mtaID,
+		Version:       importedVersion,
+	}
+
+	var messages []string
+	// map: application name -> manifest file path
+	appFiles := make(map[string]string)
+	resources := make(map[string]*mta.Resource)
+	for _, manifestPath := range manifestPaths {
+		file, err := readManifestFile(manifestPath)
+		if err != nil {
+			return nil, messages, err
+		}
+		messages = append(messages, getUnsupportedManifestAttributes(file)...)
+
+		rawApps := file.rawApplications()
+		for i, app := range file.manifest.Applications {
+			if len(app.Name) == 0 {
+				return nil, messages, errors.Errorf(missingApplicationNameMsg, manifestPath)
+			}
+			if prevFile, ok := appFiles[app.Name]; ok {
+				return nil, messages, errors.Errorf(duplicateApplicationMsg, app.Name, prevFile, manifestPath)
+			}
+			appFiles[app.Name] = manifestPath
+
+			if i < len(rawApps) {
+				messages = append(messages, getUnsupportedApplicationAttributes(app.Name, rawApps[i])...)
+			}
+			module, moduleMessages := applicationToModule(app, projectPath, filepath.Dir(manifestPath))
+			messages = append(messages, moduleMessages...)
+			result.Modules = append(result.Modules, module)
+
+			for _, service := range app.Services {
+				if _, ok := resources[service.Name]; !ok {
+					resources[service.Name] = serviceToResource(service.Name, offerings)
+				}
+			}
+		}
+	}
+
+	// Keep the resources in a stable order
+	resourceNames := make([]string, 0, len(resources))
+	for name := range resources {
+		resourceNames = append(resourceNames, name)
+	}
+	sort.Strings(resourceNames)
+	for _, name := range resourceNames {
+		result.Resources = append(result.Resources, resources[name])
+	}
+
+	return result, messages, nil
+}
+
+func getUnsupportedManifestAttributes(file *manifestFile) []string {
+	var messages []string
+	for _, key := range sortedKeys(file.raw) {
+		if key != applicationsManifestField {
+			messages = append(messages, fmt.Sprintf(unsupportedManifestMsg, key, file.path))
+		}
+	}
+	return messages
+}
+
+func getUnsupportedApplicationAttributes(appName string, rawApp map[string]interface{}) []string {
+	var messages []string
+	for _, key := range sortedKeys(rawApp) {
+		if !applicationFields[key] {
+			messages = append(messages, fmt.Sprintf(unsupportedAttributeMsg, key, appName))
+		}
+	}
+	return messages
+}
+
+func applicationToModule(app *Application, projectPath string, manifestDir string) (*mta.Module, []string) {
+	var messages []string
+	module := &mta.Module{
+		Name:       app.Name,
+		Path:       getModulePath(app.Path, projectPath, manifestDir),
+		Parameters: map[string]interface{}{},
+	}
+
+	buildpacks := app.Buildpacks
+	if len(buildpacks) == 0 && len(app.Buildpack) > 0 {
+		buildpacks = []string{app.Buildpack}
+	}
+	if len(buildpacks) > 1 {
+		messages = append(messages, fmt.Sprintf(multipleBuildpacksMsg, app.Name, buildpacks[0]))
+	}
+	module.Type = defaultModuleType
+	if len(buildpacks) > 0 {
+		module.Type = getModuleType(buildpacks[0])
+		module.Parameters["buildpack"] = buildpacks[0]
+	}
+
+	setStringParameter(module.Parameters, "memory", app.Memory)
+	setStringParameter(module.Parameters, "disk-quota", app.DiskQuota)
+	setStringParameter(module.Parameters, "command", app.Command)
+	setStringParameter(module.Parameters, "stack", app.Stack)
+	setStringParameter(module.Parameters, "health-check-type", app.HealthCheckType)
+	setStringParameter(module.Parameters, "health-check-http-endpoint", app.HealthCheckHTTPEndpoint)
+	if app.Instances != nil {
+		module.Parameters["instances"] = *app.Instances
+	}
+	if app.Timeout != nil {
+		module.Parameters["health-check-timeout"] = *app.Timeout
+	}
+	if app.NoRoute {
+		module.Parameters["no-route"] = true
+	}
+	if app.RandomRoute {
+		module.Parameters["random-route"] = true
+	}
+	if len(app.Routes) > 0 {
+		routes := make([]interface{}, 0, len(app.Routes))
+		for _, route := range app.Routes {
+			routeMap := map[string]interface{}{"route": route.Route}
+			if len(route.Protocol) > 0 {
+				routeMap["protocol"] = route.Protocol
+			}
+			routes = append(routes, routeMap)
+		}
+		module.Parameters["routes"] = routes
+	}
+	if app.Docker != nil {
+		docker := map[string]interface{}{"image": app.Docker.Image}
+		if len(app.Docker.Username) > 0 {
+			docker["username"] = app.Docker.Username
+		}
+		module.Parameters["docker"] = docker
+	}
+	if len(module.Parameters) == 0 {
+		module.Parameters = nil
+	}
+
+	if len(app.Env) > 0 {
+		module.Properties = map[string]interface{}{}
+		for key, value := range app.Env {
+			module.Properties[key] = value
+		}
+	}
+
+	for _, service := range app.Services {
+		requires := mta.Requires{Name: service.Name}
+		if len(service.Parameters) > 0 {
+			requires.Parameters = map[string]interface{}{"config": service.Parameters}
+		}
+		if len(service.BindingName) > 0 {
+			messages = append(messages, fmt.Sprintf(serviceBindingNameMsg, service.BindingName, service.Name, app.Name))
+		}
+		module.Requires = append(module.Requires, requires)
+	}
+
+	return module, messages
+}
+
+func getModulePath(appPath string, projectPath string, manifestDir string) string {
+	// The cf CLI pushes the folder of the manifest when the path is not defined
+	if len(appPath) == 0 {
+		appPath = "."
+	}
+	absolutePath := appPath
+	if !filepath.IsAbs(appPath) {
+		absolutePath = filepath.Join(manifestDir, appPath)
+	}
+	relativePath, err := filepath.Rel(projectPath, absolutePath)
+	if err != nil {
+		return filepath.ToSlash(absolutePath)
+	}
+	return filepath.ToSlash(relativePath)
+}
+
+func getModuleType(buildpack string) string {
+	for _, mapping := range buildpackModuleTypes {
+		if strings.Contains(buildpack, mapping.buildpack) {
+			return mapping.moduleType
+		}
+	}
+	return defaultModuleType
+}
+
+func serviceToResource(serviceName string, offerings map[string]ServiceOffering) *mta.Resource {
+	resource := &mta.Resource{
+		Name:       serviceName,
+		Parameters: map[string]interface{}{"service-name": serviceName},
+	}
+	if offering, ok := offerings[serviceName]; ok {
+		resource.Type = managedServiceType
+		resource.Parameters["service"] = offering.Service
+		if len(offering.Plan) > 0 {
+			resource.Parameters["service-plan"] = offering.Plan
+		}
+	} else {
+		resource.Type = existingServiceType
+	}
+	return resource
+}
+
+func setStringParameter(params map[string]interface{}, name string, value string) {
+	if len(value) > 0 {
+		params[name] = value
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
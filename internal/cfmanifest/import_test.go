@@ -0,0 +1,153 @@
+package cfmanifest
+
+import (
+	"fmt"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/SAP/cloud-mta/internal/fs"
+	"github.com/SAP/cloud-mta/mta"
+)
+
+var _ = Describe("ImportManifests", func() {
+	projectPath := getTestPath("project")
+	manifestPath := getTestPath("project", "manifest.yml")
+	approuterManifestPath := getTestPath("project", "approuter", "manifest.yml")
+
+	It("converts applications to modules and services to resources", func() {
+		result, messages, err := ImportManifests(projectPath, []string{manifestPath, approuterManifestPath}, "", nil)
+		Ω(err).Should(Succeed())
+		Ω(result.ID).Should(Equal("project"))
+		Ω(*result.SchemaVersion).Should(Equal(importedSchemaVersion))
+		Ω(result.Modules).Should(HaveLen(3))
+
+		srv := result.Modules[0]
+		Ω(srv.Name).Should(Equal("srv"))
+		Ω(srv.Type).Should(Equal("nodejs"))
+		Ω(srv.Path).Should(Equal("srv"))
+		Ω(srv.Parameters).Should(Equal(map[string]interface{}{
+			"buildpack":                  "nodejs_buildpack",
+			"memory":                     "512M",
+			"disk-quota":                 "1G",
+			"instances":                  2,
+			"command":                    "npm start",
+			"health-check-type":          "http",
+			"health-check-http-endpoint": "/health",
+			"health-check-timeout":       180,
+			"routes":                     []interface{}{map[string]interface{}{"route": "srv.example.com"}},
+		}))
+		Ω(srv.Properties).Should(Equal(map[string]interface{}{"NODE_ENV": "production", "TIMEOUT": 30}))
+		Ω(srv.Requires).Should(Equal([]mta.Requires{
+			{Name: "hana"},
+			{Name: "uaa", Parameters: map[string]interface{}{"config": map[string]interface{}{"role": "admin"}}},
+		}))
+
+		web := result.Modules[1]
+		Ω(web.Type).Should(Equal("staticfile"))
+		Ω(web.Parameters).Should(Equal(map[string]interface{}{"buildpack": "staticfile_buildpack", "no-route": true}))
+
+		approuter := result.Modules[2]
+		Ω(approuter.Type).Should(Equal(defaultModuleType))
+		Ω(approuter.Path).Should(Equal("approuter"))
+		Ω(approuter.Parameters).Should(Equal(map[string]interface{}{
+			"memory":       "256M",
+			"random-route": true,
+			"docker":       map[string]interface{}{"image": "org/approuter:1.0"},
+		}))
+
+		Ω(result.Resources).Should(HaveLen(3))
+		Ω(result.Resources[0].Name).Should(Equal("dest"))
+		Ω(result.Resources[1].Name).Should(Equal("hana"))
+		Ω(result.Resources[1].Type).Should(Equal(existingServiceType))
+		Ω(result.Resources[1].Parameters).Should(Equal(map[string]interface{}{"service-name": "hana"}))
+		Ω(result.Resources[2].Name).Should(Equal("uaa"))
+
+		Ω(messages).Should(ConsistOf(
+			fmt.Sprintf(unsupportedManifestMsg, "version", manifestPath),
+			fmt.Sprintf(unsupportedAttributeMsg, "sidecars", "srv"),
+			fmt.Sprintf(serviceBindingNameMsg, "my-uaa", "uaa", "srv"),
+		))
+	})
+
+	It("converts services with an offering to managed services", func() {
+		offerings, err := ParseServiceOfferings([]string{"hana=hana:hdi-shared", "uaa=xsuaa"})
+		Ω(err).Should(Succeed())
+		result, _, err := ImportManifests(projectPath, []string{manifestPath}, "my.mta", offerings)
+		Ω(err).Should(Succeed())
+		Ω(result.ID).Should(Equal("my.mta"))
+		Ω(result.Resources).Should(HaveLen(2))
+		Ω(result.Resources[0].Type).Should(Equal(managedServiceType))
+		Ω(result.Resources[0].Parameters).Should(Equal(map[string]interface{}{
+			"service-name": "hana", "service": "hana", "service-plan": "hdi-shared",
+		}))
+		Ω(result.Resources[1].Parameters).Should(Equal(map[string]interface{}{
+			"service-name": "uaa", "service": "xsuaa",
+		}))
+	})
+
+	It("reports only the first buildpack when several are defined", func() {
+		app := &Application{Name: "app", Buildpacks: []string{"java_buildpack", "other_buildpack"}}
+		module, messages := applicationToModule(app, projectPath, projectPath)
+		Ω(module.Type).Should(Equal("java"))
+		Ω(module.Path).Should(Equal("."))
+		Ω(messages).Should(Equal([]string{fmt.Sprintf(multipleBuildpacksMsg, "app", "java_buildpack")}))
+	})
+
+	It("returns error when no manifest is sent", func() {
+		_, _, err := ImportManifests(projectPath, nil, "", nil)
+		Ω(err).Should(MatchError(noManifestsMsg))
+	})
+
+	It("returns error when an application is defined twice", func() {
+		duplicatePath := getTestPath("duplicate.yml")
+		_, _, err := ImportManifests(projectPath, []string{manifestPath, duplicatePath}, "", nil)
+		Ω(err).Should(MatchError(fmt.Sprintf(duplicateApplicationMsg, "srv", manifestPath, duplicatePath)))
+	})
+
+	It("returns error when an application has no name", func() {
+		path := getTestPath("noName.yml")
+		_, _, err := ImportManifests(projectPath, []string{path}, "", nil)
+		Ω(err).Should(MatchError(fmt.Sprintf(missingApplicationNameMsg, path)))
+	})
+
+	It("returns error when the manifest is invalid", func() {
+		path := getTestPath("invalid.yml")
+		_, _, err := ImportManifests(projectPath, []string{path}, "", nil)
+		Ω(err).Should(HaveOccurred())
+		Ω(err.Error()).Should(ContainSubstring(fmt.Sprintf(manifestUnmarshalFailsMsg, path)))
+	})
+
+	It("returns error when the manifest does not exist", func() {
+		path := getTestPath("notExisting.yml")
+		_, _, err := ImportManifests(projectPath, []string{path}, "", nil)
+		Ω(err).Should(HaveOccurred())
+		Ω(err.Error()).Should(ContainSubstring(fmt.Sprintf(fs.PathNotFoundMsg, path)))
+	})
+})
+
+var _ = Describe("ParseServiceOfferings", func() {
+	It("returns error for an invalid offering", func() {
+		_, err := ParseServiceOfferings([]string{"hana"})
+		Ω(err).Should(MatchError(fmt.Sprintf(invalidServiceOfferingMsg, "hana")))
+	})
+})
+
+var _ = Describe("Import", func() {
+	AfterEach(func() {
+		os.RemoveAll(getTestPath("result"))
+	})
+
+	It("writes a valid mta.yaml file", func() {
+		Ω(os.MkdirAll(getTestPath("result"), os.ModePerm)).Should(Succeed())
+		path := getTestPath("result", "mta.yaml")
+		_, err := Import(path, []string{getTestPath("project", "manifest.yml")}, "imported", nil)
+		Ω(err).Should(Succeed())
+		result, _, err := mta.GetMtaFromFile(path, nil, true)
+		Ω(err).Should(Succeed())
+		Ω(result.ID).Should(Equal("imported"))
+		Ω(result.Modules[0].Path).Should(Equal("../project/srv"))
+		Ω(result.Resources).Should(HaveLen(2))
+	})
+})
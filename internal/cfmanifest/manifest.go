@@ -0,0 +1,175 @@
+// Package cfmanifest converts between Cloud Foundry application manifests (`manifest.yml`) and MTA descriptors.
+package cfmanifest
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/SAP/cloud-mta/internal/fs"
+)
+
+const (
+	manifestUnmarshalFailsMsg = `the "%s" file is not a valid Cloud Foundry manifest`
+
+	applicationsManifestField = "applications"
+)
+
+// Manifest - the Cloud Foundry application manifest.
+type Manifest struct {
+	Applications []*Application `yaml:"applications"`
+}
+
+// Application - an application entry in the Cloud Foundry manifest.
+type Application struct {
+	Name                    string                 `yaml:"name"`
+	Path                    string                 `yaml:"path,omitempty"`
+	Memory                  string                 `yaml:"memory,omitempty"`
+	DiskQuota               string                 `yaml:"disk_quota,omitempty"`
+	Instances               *int                   `yaml:"instances,omitempty"`
+	Buildpack               string                 `yaml:"buildpack,omitempty"`
+	Buildpacks              []string               `yaml:"buildpacks,omitempty"`
+	Command                 string                 `yaml:"command,omitempty"`
+	Stack                   string                 `yaml:"stack,omitempty"`
+	HealthCheckType         string                 `yaml:"health-check-type,omitempty"`
+	HealthCheckHTTPEndpoint string                 `yaml:"health-check-http-endpoint,omitempty"`
+	Timeout                 *int                   `yaml:"timeout,omitempty"`
+	NoRoute                 bool                   `yaml:"no-route,omitempty"`
+	RandomRoute             bool                   `yaml:"random-route,omitempty"`
+	Routes                  []Route                `yaml:"routes,omitempty"`
+	Docker                  *Docker                `yaml:"docker,omitempty"`
+	Env                     map[string]interface{} `yaml:"env,omitempty"`
+	Services                []Service              `yaml:"services,omitempty"`
+}
+
+// Route - an application route in the Cloud Foundry manifest.
+type Route struct {
+	Route    string `yaml:"route"`
+	Protocol string `yaml:"protocol,omitempty"`
+}
+
+// Docker - the docker image of an application in the Cloud Foundry manifest.
+type Docker struct {
+	Image    string `yaml:"image"`
+	Username string `yaml:"username,omitempty"`
+}
+
+// Service - a service instance bound to an application in the Cloud Foundry manifest.
+// In the manifest it is either a plain service instance name or a map with the binding details.
+type Service struct {
+	Name        string                 `yaml:"name"`
+	BindingName string                 `yaml:"binding_name,omitempty"`
+	Parameters  map[string]interface{} `yaml:"parameters,omitempty"`
+}
+
+// applicationFields - the application attributes which have an equivalent in the MTA descriptor
+var applicationFields = map[string]bool{
+	"name":                       true,
+	"path":                       true,
+	"memory":                     true,
+	"disk_quota":                 true,
+	"instances":                  true,
+	"buildpack":                  true,
+	"buildpacks":                 true,
+	"command":                    true,
+	"stack":                      true,
+	"health-check-type":          true,
+	"health-check-http-endpoint": true,
+	"timeout":                    true,
+	"no-route":                   true,
+	"random-route":               true,
+	"routes":                     true,
+	"docker":                     true,
+	"env":                        true,
+	"services":                   true,
+}
+
+// UnmarshalYAML unmarshals a Service object, which can be defined as a service instance name or as a map
+func (service *Service) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		service.Name = node.Value
+		return nil
+	}
+
+	type plainService Service
+	raw := plainService{}
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+	*service = Service(raw)
+	return nil
+}
+
+// MarshalYAML marshals a Service object as a plain service instance name when it has no binding details
+func (service Service) MarshalYAML() (interface{}, error) {
+	if service.BindingName == "" && len(service.Parameters) == 0 {
+		return service.Name, nil
+	}
+	type plainService Service
+	return plainService(service), nil
+}
+
+// Unmarshal returns a reference to the Manifest object from a byte array.
+func Unmarshal(content []byte) (*Manifest, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	manifest := Manifest{}
+	err := dec.Decode(&manifest)
+	return &manifest, err
+}
+
+// Marshal marshals a Manifest object
+func Marshal(manifest *Manifest) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	err := enc.Encode(manifest)
+	if err != nil {
+		return nil, err
+	}
+	err = enc.Close()
+	return buf.Bytes(), err
+}
+
+// manifestFile - a parsed manifest and the attributes that were found in it
+type manifestFile struct {
+	path     string
+	manifest *Manifest
+	// Raw content of the manifest, used for finding attributes that are not supported in the MTA descriptor
+	raw map[string]interface{}
+}
+
+func readManifestFile(path string) (*manifestFile, error) {
+	content, err := fs.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := Unmarshal(content)
+	if err != nil {
+		return nil, errors.Wrapf(err, manifestUnmarshalFailsMsg, path)
+	}
+	raw := map[string]interface{}{}
+	err = yaml.Unmarshal(content, &raw)
+	if err != nil {
+		return nil, errors.Wrapf(err, manifestUnmarshalFailsMsg, path)
+	}
+	return &manifestFile{path: path, manifest: manifest, raw: raw}, nil
+}
+
+// rawApplications returns the applications of the manifest as they are defined in the file
+func (file *manifestFile) rawApplications() []map[string]interface{} {
+	var result []map[string]interface{}
+	apps, _ := file.raw[applicationsManifestField].([]interface{})
+	for _, app := range apps {
+		appMap := map[string]interface{}{}
+		// Nested maps are decoded with interface keys
+		rawMap, _ := app.(map[interface{}]interface{})
+		for key, value := range rawMap {
+			appMap[fmt.Sprint(key)] = value
+		}
+		result = append(result, appMap)
+	}
+	return result
+}
@@ -0,0 +1,74 @@
+package export
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/SAP/cloud-mta/mta"
+)
+
+const (
+	// ModuleNotFoundMsg - the message of the error when a selected module is not defined in the MTA
+	ModuleNotFoundMsg = `could not find the "%s" module`
+
+	configurationResourceType  = "configuration"
+	existingServiceKeyResource = "org.cloudfoundry.existing-service-key"
+)
+
+// GetModules returns the modules of the MTA with the names, in the order of the names.
+// When no names are sent, all the modules are returned.
+func GetModules(mtaObj *mta.MTA, moduleNames []string) ([]*mta.Module, error) {
+	if len(moduleNames) == 0 {
+		return mtaObj.Modules, nil
+	}
+	modules := make([]*mta.Module, 0, len(moduleNames))
+	for _, name := range moduleNames {
+		module, err := mtaObj.GetModuleByName(name)
+		if err != nil {
+			return nil, errors.Errorf(ModuleNotFoundMsg, name)
+		}
+		modules = append(modules, module)
+	}
+	return modules, nil
+}
+
+// IsServiceResource returns true if the deployer creates or binds a service instance for the resource
+func IsServiceResource(resource *mta.Resource) bool {
+	if resource.Active != nil && !*resource.Active {
+		return false
+	}
+	return len(resource.Type) > 0 && resource.Type != configurationResourceType && resource.Type != existingServiceKeyResource
+}
+
+// GetServiceName returns the service instance name of the resource; the deployer uses the resource name by default
+func GetServiceName(resource *mta.Resource) string {
+	if serviceName, ok := resource.Parameters["service-name"].(string); ok && len(serviceName) > 0 {
+		return serviceName
+	}
+	return resource.Name
+}
+
+// GetStringParameter returns the value of the parameter as a string, or an empty string if it's not defined
+func GetStringParameter(params map[string]interface{}, name string) string {
+	value, ok := params[name]
+	if !ok || value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// ToStringMap returns the value as a map with string keys, or nil if it's not a map
+func ToStringMap(value interface{}) map[string]interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			result[fmt.Sprint(key)] = val
+		}
+		return result
+	}
+	return nil
+}
@@ -0,0 +1,387 @@
+package k8s
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/SAP/cloud-mta/internal/export"
+	"github.com/SAP/cloud-mta/internal/resolver"
+	"github.com/SAP/cloud-mta/mta"
+)
+
+const (
+	invalidParameterMsg = `the "%s" parameter of the "%s" module is not valid; it was ignored`
+	imageNotMappedMsg   = `the image of the "%s" module is not defined in the mapping file; the "%s" image is used`
+	existingServiceMsg  = `the "%s" resource is an existing service; the "%s" service instance must exist in the cluster`
+	missingOfferingMsg  = `the "%s" resource does not define the service offering and plan; set them in the generated service instance`
+
+	defaultTargetFileName = "k8s.yaml"
+	defaultPort           = 8080
+	servicePort           = 80
+	defaultImageTag       = "latest"
+	bindingsMountPath     = "/bindings/"
+	appLabel              = "app"
+
+	existingServiceType = "org.cloudfoundry.existing-service"
+)
+
+// memoryRegex - matches memory sizes in the Cloud Foundry format, e.g. 512M or 1G
+var memoryRegex = regexp.MustCompile(`^(?i)(\d+)\s*(K|KB|M|MB|G|GB)$`)
+
+// resourceLimits - maps module parameters to the container resource limits
+var resourceLimits = []struct {
+	parameter string
+	limit     string
+}{
+	{"memory", "memory"},
+	{"disk-quota", "ephemeral-storage"},
+}
+
+// invalidNameChars - characters which are not allowed in Kubernetes object names
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// Export converts the modules of the MTA to Kubernetes manifests and writes them to the target path.
+// When no modules are sent, all the modules are exported. The returned messages describe values that could not be
+// resolved or converted.
+func Export(workspaceDir, path string, extensions []string, moduleNames []string, envFile string, mappingPath string, target string) ([]string, error) {
+	if len(workspaceDir) == 0 {
+		workspaceDir = filepath.Dir(path)
+	}
+	if len(target) == 0 {
+		target = filepath.Join(workspaceDir, defaultTargetFileName)
+	}
+	mapping, err := ReadMapping(mappingPath)
+	if err != nil {
+		return nil, err
+	}
+	objects, messages, err := ExportObjects(workspaceDir, path, extensions, moduleNames, envFile, mapping)
+	if err != nil {
+		return messages, err
+	}
+	content, err := Marshal(objects)
+	if err != nil {
+		return messages, err
+	}
+	return messages, ioutil.WriteFile(target, content, 0644)
+}
+
+// ExportObjects converts the modules of the MTA to Kubernetes objects, after merging the extensions and resolving
+// the modules' properties and parameters. Each module is converted to a deployment, a service, and a config map and
+// a secret with its environment variables; each service resource is converted to a service instance in the namespaces
+// of the modules which require it, which is bound to these modules.
+func ExportObjects(workspaceDir, path string, extensions []string, moduleNames []string, envFile string, mapping *Mapping) ([]interface{}, []string, error) {
+	mtaObj, messages, err := mta.GetMtaFromFile(path, extensions, false)
+	if err != nil {
+		return nil, messages, err
+	}
+	if len(workspaceDir) == 0 {
+		workspaceDir = filepath.Dir(path)
+	}
+
+	modules, err := export.GetModules(mtaObj, moduleNames)
+	if err != nil {
+		return nil, messages, err
+	}
+
+	m := resolver.NewMTAResolver(mtaObj, workspaceDir)
+	moduleEnvs := make([]map[string]string, 0, len(modules))
+	for _, module := range modules {
+		env, err := m.ResolveModule(module, envFile)
+		if err != nil {
+			return nil, messages, err
+		}
+		moduleEnvs = append(moduleEnvs, env)
+	}
+	for _, resource := range mtaObj.Resources {
+		m.ResolveResourceProperties(resource)
+	}
+
+	var objects []interface{}
+	for _, service := range getRequiredServices(mtaObj, modules, mapping) {
+		instances, instanceMessages := resourceToServiceInstances(service.resource, service.namespaces)
+		messages = append(messages, instanceMessages...)
+		for _, instance := range instances {
+			objects = append(objects, instance)
+		}
+	}
+	for i, module := range modules {
+		moduleObjects, moduleMessages := moduleToObjects(module, moduleEnvs[i], mtaObj, mapping)
+		messages = append(messages, moduleMessages...)
+		objects = append(objects, moduleObjects...)
+	}
+	messages = append(messages, m.Messages()...)
+
+	return objects, messages, nil
+}
+
+// requiredService - a service resource and the namespaces of the modules which require it
+type requiredService struct {
+	resource   *mta.Resource
+	namespaces []string
+}
+
+// getRequiredServices returns the service resources required by the modules, in the order of the resources section.
+// The service instance of a resource is created in the namespace of each module which binds to it, since a service
+// binding can only reference a service instance in its own namespace.
+func getRequiredServices(mtaObj *mta.MTA, modules []*mta.Module, mapping *Mapping) []requiredService {
+	namespaces := make(map[string][]string)
+	for _, module := range modules {
+		namespace := mapping.getNamespace(module.Name)
+		for _, requires := range module.Requires {
+			if !containsString(namespaces[requires.Name], namespace) {
+				namespaces[requires.Name] = append(namespaces[requires.Name], namespace)
+			}
+		}
+	}
+	var services []requiredService
+	for _, resource := range mtaObj.Resources {
+		if resourceNamespaces, ok := namespaces[resource.Name]; ok && export.IsServiceResource(resource) {
+			services = append(services, requiredService{resource: resource, namespaces: resourceNamespaces})
+		}
+	}
+	return services
+}
+
+func moduleToObjects(module *mta.Module, env map[string]string, mtaObj *mta.MTA, mapping *Mapping) ([]interface{}, []string) {
+	var messages []string
+	var objects []interface{}
+	name := toObjectName(module.Name)
+	namespace := mapping.getNamespace(module.Name)
+	labels := map[string]string{appLabel: name}
+	meta := func(objectName string) ObjectMeta {
+		return ObjectMeta{Name: objectName, Namespace: namespace, Labels: labels}
+	}
+
+	image, imageMessages := getImage(module, mtaObj, mapping)
+	messages = append(messages, imageMessages...)
+	port := defaultPort
+	if moduleMapping, ok := mapping.Modules[module.Name]; ok && moduleMapping.Port > 0 {
+		port = moduleMapping.Port
+	}
+
+	container := Container{
+		Name:  name,
+		Image: image,
+		Ports: []ContainerPort{{ContainerPort: port}},
+		// Cloud Foundry applications listen on the port in the PORT environment variable
+		Env: []EnvVar{{Name: "PORT", Value: fmt.Sprint(port)}},
+	}
+	if command := export.GetStringParameter(module.Parameters, "command"); len(command) > 0 {
+		container.Command = []string{"/bin/sh", "-c", command}
+	}
+	container.Resources, messages = getResourceLimits(module, messages)
+
+	config, secret := splitSensitiveEnv(module, env)
+	if len(config) > 0 {
+		configMapName := name + "-config"
+		objects = append(objects, &ConfigMap{
+			APIVersion: coreAPIVersion,
+			Kind:       "ConfigMap",
+			Metadata:   meta(configMapName),
+			Data:       config,
+		})
+		container.EnvFrom = append(container.EnvFrom, EnvFromSource{ConfigMapRef: &LocalObjectReference{Name: configMapName}})
+	}
+	if len(secret) > 0 {
+		secretName := name + "-secret"
+		objects = append(objects, &Secret{
+			APIVersion: coreAPIVersion,
+			Kind:       "Secret",
+			Metadata:   meta(secretName),
+			Type:       "Opaque",
+			StringData: secret,
+		})
+		container.EnvFrom = append(container.EnvFrom, EnvFromSource{SecretRef: &LocalObjectReference{Name: secretName}})
+	}
+
+	var volumes []Volume
+	for _, requires := range module.Requires {
+		resource := mtaObj.GetResourceByName(requires.Name)
+		if resource == nil || !export.IsServiceResource(resource) {
+			continue
+		}
+		bindingName := toObjectName(module.Name + "-" + resource.Name)
+		binding := &ServiceBinding{
+			APIVersion: servicesAPIVersion,
+			Kind:       "ServiceBinding",
+			Metadata:   meta(bindingName),
+			Spec: ServiceBindingSpec{
+				ServiceInstanceName: toObjectName(export.GetServiceName(resource)),
+				SecretName:          bindingName,
+				Parameters:          export.ToStringMap(requires.Parameters["config"]),
+			},
+		}
+		objects = append(objects, binding)
+		volumes = append(volumes, Volume{Name: bindingName, Secret: SecretVolumeSource{SecretName: bindingName}})
+		container.VolumeMounts = append(container.VolumeMounts, VolumeMount{
+			Name:      bindingName,
+			MountPath: bindingsMountPath + resource.Name,
+			ReadOnly:  true,
+		})
+	}
+
+	deployment := &Deployment{
+		APIVersion: appsAPIVersion,
+		Kind:       "Deployment",
+		Metadata:   meta(name),
+		Spec: DeploymentSpec{
+			Selector: LabelSelector{MatchLabels: labels},
+			Template: PodTemplate{
+				Metadata: ObjectMeta{Labels: labels},
+				Spec:     PodSpec{Containers: []Container{container}, Volumes: volumes},
+			},
+		},
+	}
+	if instances, ok := module.Parameters["instances"]; ok && instances != nil {
+		if replicas, ok := instances.(int); ok {
+			deployment.Spec.Replicas = &replicas
+		} else {
+			messages = append(messages, fmt.Sprintf(invalidParameterMsg, "instances", module.Name))
+		}
+	}
+	objects = append(objects, deployment)
+
+	if noRoute, _ := module.Parameters["no-route"].(bool); !noRoute {
+		objects = append(objects, &Service{
+			APIVersion: coreAPIVersion,
+			Kind:       "Service",
+			Metadata:   meta(name),
+			Spec: ServiceSpec{
+				Selector: labels,
+				Ports:    []ServicePort{{Port: servicePort, TargetPort: port}},
+			},
+		})
+	}
+
+	return objects, messages
+}
+
+func getImage(module *mta.Module, mtaObj *mta.MTA, mapping *Mapping) (string, []string) {
+	if moduleMapping, ok := mapping.Modules[module.Name]; ok && len(moduleMapping.Image) > 0 {
+		return moduleMapping.Image, nil
+	}
+	tag := defaultImageTag
+	if len(mtaObj.Version) > 0 {
+		tag = strings.ReplaceAll(mtaObj.Version, "+", "_")
+	}
+	image := toObjectName(module.Name) + ":" + tag
+	if len(mapping.Registry) > 0 {
+		return strings.TrimSuffix(mapping.Registry, "/") + "/" + image, nil
+	}
+	return image, []string{fmt.Sprintf(imageNotMappedMsg, module.Name, image)}
+}
+
+func getResourceLimits(module *mta.Module, messages []string) (*ResourceRequirements, []string) {
+	limits := make(map[string]string)
+	for _, resourceLimit := range resourceLimits {
+		parameter, limit := resourceLimit.parameter, resourceLimit.limit
+		value := export.GetStringParameter(module.Parameters, parameter)
+		if len(value) == 0 {
+			continue
+		}
+		quantity, ok := toQuantity(value)
+		if !ok {
+			messages = append(messages, fmt.Sprintf(invalidParameterMsg, parameter, module.Name))
+			continue
+		}
+		limits[limit] = quantity
+	}
+	if len(limits) == 0 {
+		return nil, messages
+	}
+	return &ResourceRequirements{Limits: limits}, messages
+}
+
+// toQuantity converts a memory size in the Cloud Foundry format (e.g. 512M) to a Kubernetes quantity (e.g. 512Mi)
+func toQuantity(value string) (string, bool) {
+	match := memoryRegex.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return "", false
+	}
+	unit := strings.ToUpper(match[2][:1])
+	if unit == "K" {
+		unit = "k"
+	}
+	return match[1] + unit + "i", true
+}
+
+// splitSensitiveEnv splits the environment variables of the module to the regular and the sensitive variables.
+// A variable is sensitive when its property is marked as sensitive in the properties metadata.
+func splitSensitiveEnv(module *mta.Module, env map[string]string) (config map[string]string, secret map[string]string) {
+	sensitive := make(map[string]bool)
+	for key, metadata := range module.PropertiesMetaData {
+		sensitive[key] = sensitive[key] || metadata.Sensitive
+	}
+	for _, requires := range module.Requires {
+		for key, metadata := range requires.PropertiesMetaData {
+			// The properties of a group or a list are serialized into a single variable
+			if len(requires.List) > 0 {
+				key = requires.List
+			} else if len(requires.Group) > 0 {
+				key = requires.Group
+			}
+			sensitive[key] = sensitive[key] || metadata.Sensitive
+		}
+	}
+
+	config = make(map[string]string)
+	secret = make(map[string]string)
+	for key, value := range env {
+		if sensitive[key] {
+			secret[key] = value
+		} else {
+			config[key] = value
+		}
+	}
+	return config, secret
+}
+
+// resourceToServiceInstances returns the service instances of the resource in the namespaces
+func resourceToServiceInstances(resource *mta.Resource, namespaces []string) ([]*ServiceInstance, []string) {
+	serviceName := export.GetServiceName(resource)
+	if resource.Type == existingServiceType {
+		return nil, []string{fmt.Sprintf(existingServiceMsg, resource.Name, toObjectName(serviceName))}
+	}
+	var messages []string
+	offering := export.GetStringParameter(resource.Parameters, "service")
+	plan := export.GetStringParameter(resource.Parameters, "service-plan")
+	if len(offering) == 0 || len(plan) == 0 {
+		messages = append(messages, fmt.Sprintf(missingOfferingMsg, resource.Name))
+	}
+	var instances []*ServiceInstance
+	for _, namespace := range namespaces {
+		instances = append(instances, &ServiceInstance{
+			APIVersion: servicesAPIVersion,
+			Kind:       "ServiceInstance",
+			Metadata:   ObjectMeta{Name: toObjectName(serviceName), Namespace: namespace},
+			Spec: ServiceInstanceSpec{
+				ServiceOfferingName: offering,
+				ServicePlanName:     plan,
+				ExternalName:        serviceName,
+				Parameters:          export.ToStringMap(resource.Parameters["config"]),
+			},
+		})
+	}
+	return instances, messages
+}
+
+// toObjectName converts a name to a valid Kubernetes object name (a DNS label)
+func toObjectName(name string) string {
+	result := invalidNameChars.ReplaceAllString(strings.ToLower(name), "-")
+	if len(result) > 63 {
+		result = result[:63]
+	}
+	return strings.Trim(result, "-")
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,175 @@
+package k8s
+
+import (
+	"fmt"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v3"
+
+	"github.com/SAP/cloud-mta/internal/export"
+)
+
+var _ = Describe("ExportObjects", func() {
+	mtaPath := getTestPath("project", "mta.yaml")
+
+	It("converts the modules and the required resources to Kubernetes objects", func() {
+		mapping, err := ReadMapping(getTestPath("mapping.yaml"))
+		Ω(err).Should(Succeed())
+		objects, messages, err := ExportObjects("", mtaPath, nil, nil, "", mapping)
+		Ω(err).Should(Succeed())
+		Ω(messages).Should(ConsistOf(
+			fmt.Sprintf(existingServiceMsg, "logs", "logs"),
+			fmt.Sprintf(imageNotMappedMsg, "Worker_App", "worker-app:1.0.0"),
+			fmt.Sprintf(invalidParameterMsg, "memory", "Worker_App"),
+			fmt.Sprintf(invalidParameterMsg, "instances", "Worker_App"),
+		))
+		Ω(objects).Should(HaveLen(9))
+
+		instance := objects[0].(*ServiceInstance)
+		Ω(instance.Metadata).Should(Equal(ObjectMeta{Name: "my-hana", Namespace: "apps"}))
+		Ω(instance.Spec).Should(Equal(ServiceInstanceSpec{ServiceOfferingName: "hana", ServicePlanName: "hdi-shared", ExternalName: "My_Hana"}))
+
+		configMap := objects[1].(*ConfigMap)
+		Ω(configMap.Metadata.Name).Should(Equal("srv-config"))
+		Ω(configMap.Metadata.Namespace).Should(Equal("apps"))
+		Ω(configMap.Data).Should(Equal(map[string]string{"LOG_LEVEL": "debug"}))
+		secret := objects[2].(*Secret)
+		Ω(secret.Metadata.Name).Should(Equal("srv-secret"))
+		Ω(secret.StringData).Should(Equal(map[string]string{"PASSWORD": "s3cr3t"}))
+
+		binding := objects[3].(*ServiceBinding)
+		Ω(binding.Metadata.Name).Should(Equal("srv-hana"))
+		Ω(binding.Spec).Should(Equal(ServiceBindingSpec{
+			ServiceInstanceName: "my-hana",
+			SecretName:          "srv-hana",
+			Parameters:          map[string]interface{}{"schema": "APP"},
+		}))
+		existingBinding := objects[4].(*ServiceBinding)
+		Ω(existingBinding.Spec.ServiceInstanceName).Should(Equal("logs"))
+
+		deployment := objects[5].(*Deployment)
+		Ω(*deployment.Spec.Replicas).Should(Equal(2))
+		container := deployment.Spec.Template.Spec.Containers[0]
+		Ω(container.Image).Should(Equal("registry.example.com/srv:1.0.0"))
+		Ω(container.Ports).Should(Equal([]ContainerPort{{ContainerPort: 3000}}))
+		Ω(container.Env).Should(Equal([]EnvVar{{Name: "PORT", Value: "3000"}}))
+		Ω(container.Command).Should(Equal([]string{"/bin/sh", "-c", "npm start"}))
+		Ω(container.Resources.Limits).Should(Equal(map[string]string{"memory": "512Mi", "ephemeral-storage": "1Gi"}))
+		Ω(container.EnvFrom).Should(Equal([]EnvFromSource{
+			{ConfigMapRef: &LocalObjectReference{Name: "srv-config"}},
+			{SecretRef: &LocalObjectReference{Name: "srv-secret"}},
+		}))
+		Ω(container.VolumeMounts).Should(HaveLen(2))
+		Ω(container.VolumeMounts[0]).Should(Equal(VolumeMount{Name: "srv-hana", MountPath: "/bindings/hana", ReadOnly: true}))
+		Ω(deployment.Spec.Template.Spec.Volumes[0]).Should(Equal(Volume{Name: "srv-hana", Secret: SecretVolumeSource{SecretName: "srv-hana"}}))
+
+		service := objects[6].(*Service)
+		Ω(service.Spec).Should(Equal(ServiceSpec{
+			Selector: map[string]string{"app": "srv"},
+			Ports:    []ServicePort{{Port: 80, TargetPort: 3000}},
+		}))
+
+		// The group is sensitive because one of its properties is sensitive
+		workerSecret := objects[7].(*Secret)
+		Ω(workerSecret.Metadata).Should(Equal(ObjectMeta{Name: "worker-app-secret", Namespace: "workers", Labels: map[string]string{"app": "worker-app"}}))
+		Ω(workerSecret.StringData).Should(Equal(map[string]string{"destinations": `[{"token":"secret","url":"http://srv"}]`}))
+		// No service is generated for a module without routes
+		workerDeployment := objects[8].(*Deployment)
+		Ω(workerDeployment.Spec.Replicas).Should(BeNil())
+		Ω(workerDeployment.Spec.Template.Spec.Containers[0].Resources).Should(BeNil())
+	})
+
+	It("uses the image registry of the mapping", func() {
+		objects, messages, err := ExportObjects("", mtaPath, nil, []string{"Worker_App"}, "", &Mapping{Registry: "registry.example.com/"})
+		Ω(err).Should(Succeed())
+		Ω(messages).ShouldNot(ContainElement(ContainSubstring("image")))
+		deployment := objects[1].(*Deployment)
+		Ω(deployment.Spec.Template.Spec.Containers[0].Image).Should(Equal("registry.example.com/worker-app:1.0.0"))
+	})
+
+	It("creates the service instance in the namespace of each module which requires it", func() {
+		mapping := &Mapping{Namespace: "apps", Modules: map[string]ModuleMapping{
+			"worker": {Namespace: "workers"},
+			"jobs":   {Namespace: "workers"},
+		}}
+		objects, _, err := ExportObjects("", getTestPath("sharedService", "mta.yaml"), nil, nil, "", mapping)
+		Ω(err).Should(Succeed())
+		var instanceNamespaces []string
+		bindingNamespaces := make(map[string]string)
+		for _, object := range objects {
+			switch o := object.(type) {
+			case *ServiceInstance:
+				Ω(o.Metadata.Name).Should(Equal("hana"))
+				instanceNamespaces = append(instanceNamespaces, o.Metadata.Namespace)
+			case *ServiceBinding:
+				bindingNamespaces[o.Metadata.Name] = o.Metadata.Namespace
+			}
+		}
+		Ω(instanceNamespaces).Should(Equal([]string{"apps", "workers"}))
+		Ω(bindingNamespaces).Should(Equal(map[string]string{"srv-hana": "apps", "worker-hana": "workers", "jobs-hana": "workers"}))
+	})
+
+	It("returns error when a selected module does not exist", func() {
+		_, _, err := ExportObjects("", mtaPath, nil, []string{"unknown"}, "", &Mapping{})
+		Ω(err).Should(MatchError(fmt.Sprintf(export.ModuleNotFoundMsg, "unknown")))
+	})
+
+	It("returns error when the mta.yaml file does not exist", func() {
+		_, _, err := ExportObjects("", getTestPath("project", "notExisting.yaml"), nil, nil, "", &Mapping{})
+		Ω(err).Should(HaveOccurred())
+	})
+})
+
+var _ = Describe("Export", func() {
+	AfterEach(func() {
+		os.RemoveAll(getTestPath("result"))
+	})
+
+	It("writes the objects as a multi-document YAML file", func() {
+		Ω(os.MkdirAll(getTestPath("result"), os.ModePerm)).Should(Succeed())
+		target := getTestPath("result", "k8s.yaml")
+		_, err := Export("", getTestPath("project", "mta.yaml"), nil, []string{"Worker_App"}, "", getTestPath("mapping.yaml"), target)
+		Ω(err).Should(Succeed())
+		file, err := os.Open(target)
+		Ω(err).Should(Succeed())
+		defer file.Close()
+		dec := yaml.NewDecoder(file)
+		var kinds []string
+		for {
+			object := map[string]interface{}{}
+			if dec.Decode(&object) != nil {
+				break
+			}
+			kinds = append(kinds, object["kind"].(string))
+		}
+		Ω(kinds).Should(Equal([]string{"Secret", "Deployment"}))
+	})
+
+	It("returns error when the mapping file is not valid", func() {
+		_, err := Export("", getTestPath("project", "mta.yaml"), nil, nil, "", getTestPath("invalidMapping.yaml"), getTestPath("result", "k8s.yaml"))
+		Ω(err).Should(HaveOccurred())
+	})
+})
+
+var _ = Describe("toObjectName", func() {
+	It("converts names to DNS labels", func() {
+		Ω(toObjectName("My_Module.v2")).Should(Equal("my-module-v2"))
+		Ω(toObjectName("-abc-")).Should(Equal("abc"))
+	})
+})
+
+var _ = Describe("toQuantity", func() {
+	It("converts Cloud Foundry memory sizes", func() {
+		quantity, ok := toQuantity("256MB")
+		Ω(ok).Should(BeTrue())
+		Ω(quantity).Should(Equal("256Mi"))
+		quantity, _ = toQuantity("2g")
+		Ω(quantity).Should(Equal("2Gi"))
+		quantity, _ = toQuantity("100K")
+		Ω(quantity).Should(Equal("100ki"))
+		_, ok = toQuantity("2T")
+		Ω(ok).Should(BeFalse())
+	})
+})
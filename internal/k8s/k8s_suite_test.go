@@ -0,0 +1,23 @@
+package k8s
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/SAP/cloud-mta/internal/logs"
+)
+
+func TestK8s(t *testing.T) {
+	logs.NewLogger()
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "K8s Suite")
+}
+
+func getTestPath(relPath ...string) string {
+	wd, _ := os.Getwd()
+	return filepath.Join(wd, "testdata", filepath.Join(relPath...))
+}
@@ -0,0 +1,54 @@
+package k8s
+
+import (
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/SAP/cloud-mta/internal/fs"
+)
+
+const mappingUnmarshalFailsMsg = `the "%s" mapping file is not valid`
+
+// Mapping - the configuration of the Kubernetes export, which defines the values that have no MTA equivalent.
+type Mapping struct {
+	// The namespace of the generated objects
+	Namespace string `yaml:"namespace,omitempty"`
+	// The registry of the module images; the default image of a module is <registry>/<module>:<MTA version>
+	Registry string `yaml:"registry,omitempty"`
+	// The mapping of specific modules, by module name
+	Modules map[string]ModuleMapping `yaml:"modules,omitempty"`
+}
+
+// ModuleMapping - the Kubernetes configuration of a module.
+type ModuleMapping struct {
+	// The container image of the module
+	Image string `yaml:"image,omitempty"`
+	// The namespace of the objects generated for the module; overrides the mapping namespace
+	Namespace string `yaml:"namespace,omitempty"`
+	// The port the module's container listens on; the default port is 8080
+	Port int `yaml:"port,omitempty"`
+}
+
+// ReadMapping reads the mapping configuration file. An empty mapping is returned when no path is sent.
+func ReadMapping(path string) (*Mapping, error) {
+	mapping := &Mapping{}
+	if len(path) == 0 {
+		return mapping, nil
+	}
+	content, err := fs.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	err = yaml.Unmarshal(content, mapping)
+	if err != nil {
+		return nil, errors.Wrapf(err, mappingUnmarshalFailsMsg, path)
+	}
+	return mapping, nil
+}
+
+func (mapping *Mapping) getNamespace(moduleName string) string {
+	if moduleMapping, ok := mapping.Modules[moduleName]; ok && len(moduleMapping.Namespace) > 0 {
+		return moduleMapping.Namespace
+	}
+	return mapping.Namespace
+}
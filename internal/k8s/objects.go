@@ -0,0 +1,192 @@
+// Package k8s converts MTA modules and resources to Kubernetes manifests.
+package k8s
+
+import (
+	"bytes"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	appsAPIVersion     = "apps/v1"
+	coreAPIVersion     = "v1"
+	servicesAPIVersion = "services.cloud.sap.com/v1"
+)
+
+// ObjectMeta - the metadata of a Kubernetes object.
+type ObjectMeta struct {
+	Name      string            `yaml:"name"`
+	Namespace string            `yaml:"namespace,omitempty"`
+	Labels    map[string]string `yaml:"labels,omitempty"`
+}
+
+// Deployment - a Kubernetes deployment, generated for each module.
+type Deployment struct {
+	APIVersion string         `yaml:"apiVersion"`
+	Kind       string         `yaml:"kind"`
+	Metadata   ObjectMeta     `yaml:"metadata"`
+	Spec       DeploymentSpec `yaml:"spec"`
+}
+
+// DeploymentSpec - the specification of a Kubernetes deployment.
+type DeploymentSpec struct {
+	Replicas *int          `yaml:"replicas,omitempty"`
+	Selector LabelSelector `yaml:"selector"`
+	Template PodTemplate   `yaml:"template"`
+}
+
+// LabelSelector - selects the pods of a deployment or a service by their labels.
+type LabelSelector struct {
+	MatchLabels map[string]string `yaml:"matchLabels"`
+}
+
+// PodTemplate - the template of the pods created by a deployment.
+type PodTemplate struct {
+	Metadata ObjectMeta `yaml:"metadata"`
+	Spec     PodSpec    `yaml:"spec"`
+}
+
+// PodSpec - the specification of a pod.
+type PodSpec struct {
+	Containers []Container `yaml:"containers"`
+	Volumes    []Volume    `yaml:"volumes,omitempty"`
+}
+
+// Container - a container in a pod.
+type Container struct {
+	Name         string                `yaml:"name"`
+	Image        string                `yaml:"image"`
+	Command      []string              `yaml:"command,omitempty"`
+	Ports        []ContainerPort       `yaml:"ports,omitempty"`
+	Env          []EnvVar              `yaml:"env,omitempty"`
+	EnvFrom      []EnvFromSource       `yaml:"envFrom,omitempty"`
+	Resources    *ResourceRequirements `yaml:"resources,omitempty"`
+	VolumeMounts []VolumeMount         `yaml:"volumeMounts,omitempty"`
+}
+
+// ContainerPort - a port exposed by a container.
+type ContainerPort struct {
+	ContainerPort int `yaml:"containerPort"`
+}
+
+// EnvVar - an environment variable of a container.
+type EnvVar struct {
+	Name  string `yaml:"name"`
+	Value string `yaml:"value"`
+}
+
+// EnvFromSource - a config map or a secret whose entries are set as environment variables of a container.
+type EnvFromSource struct {
+	ConfigMapRef *LocalObjectReference `yaml:"configMapRef,omitempty"`
+	SecretRef    *LocalObjectReference `yaml:"secretRef,omitempty"`
+}
+
+// LocalObjectReference - a reference to an object in the same namespace.
+type LocalObjectReference struct {
+	Name string `yaml:"name"`
+}
+
+// ResourceRequirements - the compute resources of a container.
+type ResourceRequirements struct {
+	Limits map[string]string `yaml:"limits,omitempty"`
+}
+
+// VolumeMount - a volume mounted in a container.
+type VolumeMount struct {
+	Name      string `yaml:"name"`
+	MountPath string `yaml:"mountPath"`
+	ReadOnly  bool   `yaml:"readOnly,omitempty"`
+}
+
+// Volume - a volume of a pod. Only secret volumes are generated.
+type Volume struct {
+	Name   string             `yaml:"name"`
+	Secret SecretVolumeSource `yaml:"secret"`
+}
+
+// SecretVolumeSource - a secret mounted as a volume.
+type SecretVolumeSource struct {
+	SecretName string `yaml:"secretName"`
+}
+
+// Service - a Kubernetes service which exposes the pods of a module.
+type Service struct {
+	APIVersion string      `yaml:"apiVersion"`
+	Kind       string      `yaml:"kind"`
+	Metadata   ObjectMeta  `yaml:"metadata"`
+	Spec       ServiceSpec `yaml:"spec"`
+}
+
+// ServiceSpec - the specification of a Kubernetes service.
+type ServiceSpec struct {
+	Selector map[string]string `yaml:"selector"`
+	Ports    []ServicePort     `yaml:"ports"`
+}
+
+// ServicePort - a port exposed by a Kubernetes service.
+type ServicePort struct {
+	Port       int `yaml:"port"`
+	TargetPort int `yaml:"targetPort"`
+}
+
+// ConfigMap - a Kubernetes config map, generated from the resolved properties of a module.
+type ConfigMap struct {
+	APIVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Metadata   ObjectMeta        `yaml:"metadata"`
+	Data       map[string]string `yaml:"data"`
+}
+
+// Secret - a Kubernetes secret, generated from the resolved sensitive properties of a module.
+type Secret struct {
+	APIVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Metadata   ObjectMeta        `yaml:"metadata"`
+	Type       string            `yaml:"type"`
+	StringData map[string]string `yaml:"stringData"`
+}
+
+// ServiceInstance - a placeholder for a service instance of the SAP BTP service operator, generated for each resource.
+type ServiceInstance struct {
+	APIVersion string              `yaml:"apiVersion"`
+	Kind       string              `yaml:"kind"`
+	Metadata   ObjectMeta          `yaml:"metadata"`
+	Spec       ServiceInstanceSpec `yaml:"spec"`
+}
+
+// ServiceInstanceSpec - the specification of a service instance.
+type ServiceInstanceSpec struct {
+	ServiceOfferingName string                 `yaml:"serviceOfferingName"`
+	ServicePlanName     string                 `yaml:"servicePlanName"`
+	ExternalName        string                 `yaml:"externalName,omitempty"`
+	Parameters          map[string]interface{} `yaml:"parameters,omitempty"`
+}
+
+// ServiceBinding - a placeholder for a service binding of the SAP BTP service operator, generated for each required resource.
+type ServiceBinding struct {
+	APIVersion string             `yaml:"apiVersion"`
+	Kind       string             `yaml:"kind"`
+	Metadata   ObjectMeta         `yaml:"metadata"`
+	Spec       ServiceBindingSpec `yaml:"spec"`
+}
+
+// ServiceBindingSpec - the specification of a service binding.
+type ServiceBindingSpec struct {
+	ServiceInstanceName string                 `yaml:"serviceInstanceName"`
+	SecretName          string                 `yaml:"secretName"`
+	Parameters          map[string]interface{} `yaml:"parameters,omitempty"`
+}
+
+// Marshal marshals the Kubernetes objects to a multi-document YAML
+func Marshal(objects []interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	for _, object := range objects {
+		if err := enc.Encode(object); err != nil {
+			return nil, err
+		}
+	}
+	err := enc.Close()
+	return buf.Bytes(), err
+}
@@ -0,0 +1,101 @@
+package resolver
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SAP/cloud-mta/mta"
+)
+
+// DefaultMaxDepth - the default maximum depth of nested values and placeholder references which are resolved
+const DefaultMaxDepth = 64
+
+const (
+	cyclicReferenceMsg = `could not resolve the "${%s}" placeholder because of a cyclic reference: %s`
+	maxDepthMsg        = `could not resolve the value because it exceeds the maximum depth of %d nested values and references`
+	chainSeparator     = " -> "
+)
+
+// resolutionChain - the parameters which are being resolved, used for detecting cyclic references.
+// A parameter is identified by the location of its value, since parameters with the same name can be defined
+// in different scopes.
+type resolutionChain struct {
+	locations []string
+	names     []string
+}
+
+func (c *resolutionChain) push(location string, name string) {
+	c.locations = append(c.locations, location)
+	c.names = append(c.names, name)
+}
+
+func (c *resolutionChain) pop() {
+	c.locations = c.locations[:len(c.locations)-1]
+	c.names = c.names[:len(c.names)-1]
+}
+
+func (c *resolutionChain) contains(location string) bool {
+	return c.index(location) >= 0
+}
+
+func (c *resolutionChain) index(location string) int {
+	for i, curr := range c.locations {
+		if curr == location {
+			return i
+		}
+	}
+	return -1
+}
+
+// describe returns the cycle which is closed by the parameter in the location, e.g. a -> b -> a
+func (c *resolutionChain) describe(location string, name string) string {
+	start := c.index(location)
+	if start < 0 {
+		start = 0
+	}
+	names := append(append([]string{}, c.names[start:]...), name)
+	return strings.Join(names, chainSeparator)
+}
+
+// enterNested is called before resolving a value; it returns false when the maximum depth is exceeded and the value
+// must not be resolved
+func (m *MTAResolver) enterNested() bool {
+	maxDepth := m.options.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxDepth
+	}
+	if m.depth >= maxDepth {
+		message := fmt.Sprintf(maxDepthMsg, maxDepth)
+		if len(m.chain.names) > 0 {
+			message += ": " + strings.Join(m.chain.names, chainSeparator)
+		}
+		m.addMessage(message)
+		return false
+	}
+	m.depth++
+	return true
+}
+
+func (m *MTAResolver) exitNested() {
+	m.depth--
+}
+
+// resolveParameterValue resolves the value of the parameter in the location, with the parameter in the resolution chain
+func (m *MTAResolver) resolveParameterValue(location string, name string, resolveValue func() any) any {
+	m.chain.push(location, name)
+	defer m.chain.pop()
+	return resolveValue()
+}
+
+func requiresLocation(sourceModule *mta.Module, requires *mta.Requires, paramName string) string {
+	moduleName := ""
+	if sourceModule != nil {
+		moduleName = sourceModule.Name
+	}
+	return moduleName + "/" + requires.Name + "/" + paramName
+}
+
+// contextLocation returns the location of values which were configured externally, e.g. in environment variables
+func contextLocation(name string, paramName string) string {
+	return "context:" + name + "/" + paramName
+}
@@ -0,0 +1,84 @@
+package resolver
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/SAP/cloud-mta/mta"
+)
+
+const (
+	envVarCollisionMsg = `the "%s" environment variable of the "%s" module is defined by %s; the deployer uses the value of %s`
+
+	moduleDefiner   = "the module properties"
+	requiresDefiner = `the "%s" required dependency`
+	groupDefiner    = `the "%s" group`
+	listDefiner     = `the "%s" list`
+)
+
+// EnvVarDefinition - a definition of a module's environment variable
+type EnvVarDefinition struct {
+	// The index of the requires section which defines the variable by its properties or by its group or list name,
+	// or -1 when the variable is defined by the module properties
+	RequiresIndex int
+	// The description of the definition, e.g. `the "db" required dependency`
+	Definer string
+}
+
+// EnvVarCollision - an environment variable of a module which is defined several times
+type EnvVarCollision struct {
+	Name string
+	// The definitions of the variable, in the order in which the deployer applies them; the last one is used
+	Definitions []EnvVarDefinition
+}
+
+// GetEnvVarCollisions returns the environment variables of the module which are defined several times, by the module
+// properties, the properties of the required dependencies or the group or list names, sorted by name.
+// Like in the deployer, the module properties are overwritten by the properties of the required dependencies without
+// a group or a list name, in the order of the requires sections, and then by the groups and the lists; the requires
+// sections which share a group or a list name are combined into the same variable.
+func GetEnvVarCollisions(module *mta.Module) []EnvVarCollision {
+	definitions := map[string][]EnvVarDefinition{}
+	for key := range module.Properties {
+		definitions[key] = []EnvVarDefinition{{RequiresIndex: -1, Definer: moduleDefiner}}
+	}
+	for i, requires := range module.Requires {
+		if len(requires.Group) == 0 && len(requires.List) == 0 {
+			for key := range requires.Properties {
+				definitions[key] = append(definitions[key], EnvVarDefinition{RequiresIndex: i, Definer: fmt.Sprintf(requiresDefiner, requires.Name)})
+			}
+		}
+	}
+	combined := map[string]bool{}
+	for i, requires := range module.Requires {
+		name, definer := requires.Group, groupDefiner
+		if len(requires.List) > 0 {
+			name, definer = requires.List, listDefiner
+		}
+		if len(name) > 0 && !combined[name] {
+			definitions[name] = append(definitions[name], EnvVarDefinition{RequiresIndex: i, Definer: fmt.Sprintf(definer, name)})
+			combined[name] = true
+		}
+	}
+
+	var collisions []EnvVarCollision
+	for name, nameDefinitions := range definitions {
+		if len(nameDefinitions) > 1 {
+			collisions = append(collisions, EnvVarCollision{Name: name, Definitions: nameDefinitions})
+		}
+	}
+	sort.Slice(collisions, func(i, j int) bool {
+		return collisions[i].Name < collisions[j].Name
+	})
+	return collisions
+}
+
+// Message returns the description of the collision in the module
+func (collision EnvVarCollision) Message(moduleName string) string {
+	definers := make([]string, len(collision.Definitions))
+	for i, definition := range collision.Definitions {
+		definers[i] = definition.Definer
+	}
+	return fmt.Sprintf(envVarCollisionMsg, collision.Name, moduleName, strings.Join(definers, ", "), definers[len(definers)-1])
+}
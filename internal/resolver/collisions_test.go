@@ -0,0 +1,60 @@
+package resolver
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/SAP/cloud-mta/mta"
+)
+
+var _ = Describe("Environment variable collisions", func() {
+	It("returns the definitions of the variables which are defined several times", func() {
+		module := mta.Module{
+			Name:       "mod",
+			Properties: map[string]interface{}{"url": "mod", "plugins": "mod", "name": "mod"},
+			Requires: []mta.Requires{
+				{Name: "db", Properties: map[string]interface{}{"url": "db"}},
+				{Name: "audit", List: "plugins", Properties: map[string]interface{}{"url": "audit"}},
+				{Name: "metrics", List: "plugins", Properties: map[string]interface{}{"url": "metrics"}},
+			},
+		}
+		Ω(GetEnvVarCollisions(&module)).Should(Equal([]EnvVarCollision{
+			{Name: "plugins", Definitions: []EnvVarDefinition{
+				{RequiresIndex: -1, Definer: `the module properties`},
+				{RequiresIndex: 1, Definer: `the "plugins" list`},
+			}},
+			{Name: "url", Definitions: []EnvVarDefinition{
+				{RequiresIndex: -1, Definer: `the module properties`},
+				{RequiresIndex: 0, Definer: `the "db" required dependency`},
+			}},
+		}))
+	})
+
+	It("applies the groups and the lists after the required dependencies without them, like the deployer", func() {
+		module := mta.Module{
+			Name: "mod",
+			Requires: []mta.Requires{
+				{Name: "audit", Group: "destinations", Properties: map[string]interface{}{"url": "audit"}},
+				{Name: "backend", Properties: map[string]interface{}{"destinations": "backend"}},
+			},
+		}
+		collisions := GetEnvVarCollisions(&module)
+		Ω(collisions).Should(Equal([]EnvVarCollision{
+			{Name: "destinations", Definitions: []EnvVarDefinition{
+				{RequiresIndex: 1, Definer: `the "backend" required dependency`},
+				{RequiresIndex: 0, Definer: `the "destinations" group`},
+			}},
+		}))
+		Ω(collisions[0].Message("mod")).Should(Equal(`the "destinations" environment variable of the "mod" module is defined by ` +
+			`the "backend" required dependency, the "destinations" group; the deployer uses the value of the "destinations" group`))
+	})
+
+	It("describes the collision and the definition which is used", func() {
+		collision := EnvVarCollision{Name: "url", Definitions: []EnvVarDefinition{
+			{RequiresIndex: -1, Definer: `the module properties`},
+			{RequiresIndex: 0, Definer: `the "db" required dependency`},
+		}}
+		Ω(collision.Message("srv")).Should(Equal(`the "url" environment variable of the "srv" module is defined by ` +
+			`the module properties, the "db" required dependency; the deployer uses the value of the "db" required dependency`))
+	})
+})
@@ -0,0 +1,59 @@
+package resolver
+
+import (
+	"reflect"
+
+	"github.com/SAP/cloud-mta/mta"
+)
+
+// copyMTA returns a deep copy of the MTA, which can be resolved without changing the MTA
+func copyMTA(mtaObj *mta.MTA) *mta.MTA {
+	return deepCopy(reflect.ValueOf(mtaObj)).Interface().(*mta.MTA)
+}
+
+// deepCopy copies the pointers, interfaces, maps, slices and structs in the value recursively
+func deepCopy(value reflect.Value) reflect.Value {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return value
+		}
+		elem := deepCopy(value.Elem())
+		if value.Kind() == reflect.Interface {
+			result := reflect.New(value.Type()).Elem()
+			result.Set(elem)
+			return result
+		}
+		result := reflect.New(elem.Type())
+		result.Elem().Set(elem)
+		return result
+	case reflect.Map:
+		if value.IsNil() {
+			return value
+		}
+		result := reflect.MakeMapWithSize(value.Type(), value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			result.SetMapIndex(iter.Key(), deepCopy(iter.Value()))
+		}
+		return result
+	case reflect.Slice:
+		if value.IsNil() {
+			return value
+		}
+		result := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		for i := 0; i < value.Len(); i++ {
+			result.Index(i).Set(deepCopy(value.Index(i)))
+		}
+		return result
+	case reflect.Struct:
+		result := reflect.New(value.Type()).Elem()
+		for i := 0; i < value.NumField(); i++ {
+			if result.Field(i).CanSet() {
+				result.Field(i).Set(deepCopy(value.Field(i)))
+			}
+		}
+		return result
+	}
+	return value
+}
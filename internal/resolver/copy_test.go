@@ -0,0 +1,32 @@
+package resolver
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/SAP/cloud-mta/mta"
+)
+
+var _ = Describe("copyMTA", func() {
+	It("copies the nested values of the MTA", func() {
+		active := true
+		original := &mta.MTA{
+			ID: "mta",
+			Modules: []*mta.Module{{
+				Name:       "a",
+				Properties: map[string]interface{}{"list": []interface{}{map[string]interface{}{"key": "value"}}},
+				Requires:   []mta.Requires{{Name: "r", Properties: map[string]interface{}{"url": "~{url}"}}},
+			}},
+			Resources: []*mta.Resource{{Name: "r", Active: &active}},
+		}
+		copied := copyMTA(original)
+		Ω(copied).Should(Equal(original))
+
+		copied.Modules[0].Properties["list"].([]interface{})[0].(map[string]interface{})["key"] = "changed"
+		copied.Modules[0].Requires[0].Properties["url"] = "http://resolved"
+		*copied.Resources[0].Active = false
+		Ω(original.Modules[0].Properties["list"]).Should(Equal([]interface{}{map[string]interface{}{"key": "value"}}))
+		Ω(original.Modules[0].Requires[0].Properties["url"]).Should(Equal("~{url}"))
+		Ω(*original.Resources[0].Active).Should(BeTrue())
+	})
+})
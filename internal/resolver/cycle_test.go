@@ -0,0 +1,53 @@
+package resolver
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/SAP/cloud-mta/mta"
+)
+
+var _ = Describe("Cyclic references", func() {
+	yamlPath := getTestPath("cycle-project", "mta.yaml")
+
+	BeforeEach(func() {
+		envGetter = func() []string { return nil }
+	})
+
+	It("does not resolve cyclic references and names the chain", func() {
+		result, _, err := Resolve("", "srv", yamlPath, nil, "", nil)
+		Ω(err).Should(Succeed())
+		Ω(result.Properties["SELF"]).Should(Equal("prefix-${self}"))
+		Ω(result.Properties["CHAIN"]).Should(Equal("b-${a}"))
+		Ω(result.Properties["DEEP"]).Should(Equal("end"))
+		Ω(result.Messages).Should(ContainElement(fmt.Sprintf(cyclicReferenceMsg, "self", "self -> self")))
+		Ω(result.Messages).Should(ContainElement(fmt.Sprintf(cyclicReferenceMsg, "a", "a -> b -> a")))
+	})
+
+	It("does not resolve cyclic references in maps and lists", func() {
+		result, _, err := Resolve("", "srv", yamlPath, nil, "", nil)
+		Ω(err).Should(Succeed())
+		Ω(result.Messages).Should(ContainElement(fmt.Sprintf(cyclicReferenceMsg, "config", "config -> config")))
+		Ω(result.Messages).Should(ContainElement(fmt.Sprintf(cyclicReferenceMsg, "items", "items -> items")))
+	})
+
+	It("resolves a parameter which references a parameter with the same name in another scope", func() {
+		res := &mta.Resource{
+			Name:       "db",
+			Parameters: map[string]interface{}{"service-name": "${service-name}"},
+		}
+		m := NewMTAResolver(&mta.MTA{Parameters: map[string]interface{}{"service-name": "my-db"}}, "")
+		m.ResolveResourceProperties(res)
+		Ω(res.Parameters).Should(HaveKeyWithValue("service-name", "my-db"))
+		Ω(m.messages).Should(BeEmpty())
+	})
+
+	It("stops resolving values which exceed the maximum depth", func() {
+		result, _, err := Resolve("", "srv", yamlPath, nil, "", &ResolveOptions{MaxDepth: 3})
+		Ω(err).Should(Succeed())
+		Ω(result.Properties["DEEP"]).ShouldNot(Equal("end"))
+		Ω(result.Messages).Should(ContainElement(HavePrefix(fmt.Sprintf(maxDepthMsg, 3))))
+	})
+})
@@ -0,0 +1,55 @@
+package resolver
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("default-env.json", func() {
+	yamlPath := getTestPath("default-env-project", "mta.yaml")
+
+	BeforeEach(func() {
+		envGetter = func() []string { return nil }
+	})
+
+	It("resolves from default-env.json and VCAP_APPLICATION", func() {
+		result, _, err := Resolve("", "app", yamlPath, nil, "", nil)
+		Ω(err).Should(Succeed())
+		Ω(result.Properties).Should(Equal(map[string]string{
+			"URL":          "https://my-app.cfapps.example.com",
+			"HOST":         "explicit-host",
+			"DEFAULT_HOST": "my-app",
+			"DOMAIN":       "cfapps.example.com",
+			"NAME":         "my-app",
+			"SPACE":        "acme/dev",
+			// The .env file takes precedence over default-env.json
+			"LOG_LEVEL": "debug",
+			"TIMEOUT":   "30",
+			"DB":        "my-hdi",
+		}))
+		Ω(result.Messages).Should(BeEmpty())
+	})
+
+	It("prefers environment variables of the module over VCAP_APPLICATION", func() {
+		envGetter = func() []string { return []string{"app/default-url=http://localhost:4004"} }
+		result, _, err := Resolve("", "app", yamlPath, nil, "", nil)
+		Ω(err).Should(Succeed())
+		Ω(result.Properties["URL"]).Should(Equal("http://localhost:4004"))
+	})
+
+	It("reports an invalid default-env.json file", func() {
+		result, _, err := Resolve("", "invalid", yamlPath, nil, "", nil)
+		Ω(err).Should(Succeed())
+		Ω(result.Properties["URL"]).Should(Equal("${default-url}"))
+		Ω(result.Messages).Should(ContainElement(fmt.Sprintf(invalidDefaultEnv, getTestPath("default-env-project", "invalid", "default-env.json"))))
+	})
+
+	It("reports an invalid VCAP_APPLICATION variable", func() {
+		envGetter = func() []string { return []string{"VCAP_APPLICATION=[1"} }
+		result, _, err := Resolve("", "invalid", yamlPath, nil, "", nil)
+		Ω(err).Should(Succeed())
+		Ω(result.Messages).Should(ContainElement(invalidVcapApplicationMsg))
+	})
+})
@@ -0,0 +1,131 @@
+package resolver
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// deployerString converts a value which is used inside a larger string the same way the deployer does.
+// The deployer renders structured values with the Java toString format and not as JSON:
+// maps are rendered as {key1=value1, key2=value2}, lists as [value1, value2] and null as null.
+// The deployer keeps the order of the map keys from the descriptor. The order is lost when the descriptor is parsed
+// to maps, so it's taken from the map in the descriptors with the same keys; the keys of other maps, e.g. maps which
+// were merged from an extension, are sorted.
+func deployerString(valueObj any, keyOrders mapKeyOrders) string {
+	switch v := valueObj.(type) {
+	case nil:
+		return "null"
+	case string:
+		return v
+	case float64:
+		return javaDoubleString(v)
+	case float32:
+		return javaDoubleString(float64(v))
+	case map[any]any:
+		return deployerString(convertToJSONSafe(v), keyOrders)
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		entries := make([]string, 0, len(keys))
+		for _, key := range keyOrders.orderKeys(keys) {
+			entries = append(entries, key+"="+deployerString(v[key], keyOrders))
+		}
+		return "{" + strings.Join(entries, ", ") + "}"
+	case []map[string]any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, deployerString(item, keyOrders))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, deployerString(item, keyOrders))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	}
+	return fmt.Sprint(valueObj)
+}
+
+// mapKeyOrders - the keys of the maps in the descriptors in their order, by the sorted keys of the maps
+type mapKeyOrders map[string][]string
+
+// getMapKeyOrders returns the order of the keys of the maps in the descriptors. When several maps have the same keys,
+// the order of the first map is used; the MTA descriptor is searched before the extensions.
+func getMapKeyOrders(descriptors []descriptorFile) mapKeyOrders {
+	keyOrders := mapKeyOrders{}
+	var walk func(node *yaml.Node)
+	walk = func(node *yaml.Node) {
+		if node.Kind == yaml.MappingNode {
+			keys := make([]string, 0, len(node.Content)/2)
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				keys = append(keys, node.Content[i].Value)
+			}
+			id := mapKeysID(keys)
+			if _, ok := keyOrders[id]; !ok {
+				keyOrders[id] = keys
+			}
+		}
+		for _, child := range node.Content {
+			walk(child)
+		}
+	}
+	for i := len(descriptors) - 1; i >= 0; i-- {
+		walk(descriptors[i].node)
+	}
+	return keyOrders
+}
+
+// orderKeys returns the keys in the order of the map in the descriptors with the same keys, or sorted
+func (keyOrders mapKeyOrders) orderKeys(keys []string) []string {
+	if order, ok := keyOrders[mapKeysID(keys)]; ok {
+		return order
+	}
+	sorted := append([]string{}, keys...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+// mapKeysID returns an ID of the keys of a map, which doesn't depend on their order
+func mapKeysID(keys []string) string {
+	sorted := append([]string{}, keys...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, "\x00")
+}
+
+// javaDoubleString formats a floating point number like the Java Double.toString method,
+// e.g. 1.0, 0.5, 1.0E7 and 1.0E-4
+func javaDoubleString(value float64) string {
+	if math.IsNaN(value) {
+		return "NaN"
+	}
+	if math.IsInf(value, 1) {
+		return "Infinity"
+	}
+	if math.IsInf(value, -1) {
+		return "-Infinity"
+	}
+	abs := math.Abs(value)
+	if abs == 0 || (abs >= 1e-3 && abs < 1e7) {
+		str := strconv.FormatFloat(value, 'f', -1, 64)
+		if !strings.Contains(str, ".") {
+			str += ".0"
+		}
+		return str
+	}
+	str := strconv.FormatFloat(value, 'E', -1, 64)
+	parts := strings.SplitN(str, "E", 2)
+	mantissa := parts[0]
+	if !strings.Contains(mantissa, ".") {
+		mantissa += ".0"
+	}
+	exponent, _ := strconv.Atoi(parts[1])
+	return mantissa + "E" + strconv.Itoa(exponent)
+}
@@ -0,0 +1,102 @@
+package resolver
+
+import (
+	"math"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v3"
+)
+
+var _ = Describe("Deployer compatibility", func() {
+	yamlPath := getTestPath("deployer-project", "mta.yaml")
+
+	BeforeEach(func() {
+		envGetter = func() []string { return nil }
+	})
+
+	It("renders structured values inside strings like the deployer", func() {
+		result, _, err := Resolve("", "srv", yamlPath, nil, "", &ResolveOptions{DeployerCompatible: true})
+		Ω(err).Should(Succeed())
+		Ω(result.Properties).Should(Equal(map[string]string{
+			"TEXT":   "the config: {stuct_field=abc}",
+			"LIST":   "items: [a, 1, 1.5, true, null]",
+			"WHOLE":  `{"stuct_field":"abc"}`,
+			"API":    "api: {timeout=30, retry=true}",
+			"MEMORY": "512",
+		}))
+		Ω(result.Messages).Should(BeEmpty())
+	})
+
+	It("renders structured values inside strings as JSON by default", func() {
+		result, _, err := Resolve("", "srv", yamlPath, nil, "", nil)
+		Ω(err).Should(Succeed())
+		Ω(result.Properties).Should(Equal(map[string]string{
+			"TEXT":   `the config: {"stuct_field":"abc"}`,
+			"LIST":   `items: ["a",1,1.5,true,null]`,
+			"WHOLE":  `{"stuct_field":"abc"}`,
+			"API":    `api: {"retry":true,"timeout":30}`,
+			"MEMORY": "${memory}",
+		}))
+		Ω(result.Messages).Should(ConsistOf("Missing db/memory"))
+	})
+})
+
+var _ = DescribeTable("deployerString",
+	func(value interface{}, expected string) {
+		Ω(deployerString(value, nil)).Should(Equal(expected))
+	},
+	Entry("string", "abc", "abc"),
+	Entry("null", nil, "null"),
+	Entry("integer", 10, "10"),
+	Entry("boolean", false, "false"),
+	Entry("map", map[string]interface{}{"stuct_field": "abc"}, "{stuct_field=abc}"),
+	Entry("map with interface keys", map[interface{}]interface{}{"b": 1, "a": "x"}, "{a=x, b=1}"),
+	Entry("nested values", map[string]interface{}{"list": []interface{}{"a", map[string]interface{}{"k": nil}}}, "{list=[a, {k=null}]}"),
+	Entry("list of maps", []map[string]interface{}{{"a": 1}, {"b": 2}}, "[{a=1}, {b=2}]"),
+	Entry("empty map", map[string]interface{}{}, "{}"),
+	Entry("empty list", []interface{}{}, "[]"),
+)
+
+var _ = Describe("deployerString with the key order of the descriptors", func() {
+	var keyOrders mapKeyOrders
+
+	BeforeEach(func() {
+		node := &yaml.Node{}
+		Ω(yaml.Unmarshal([]byte(`
+config:
+  timeout: 30
+  retry: true
+  nested:
+    z: 1
+    a: 2
+`), node)).Should(Succeed())
+		keyOrders = getMapKeyOrders([]descriptorFile{{path: "mta.yaml", node: node.Content[0]}})
+	})
+
+	It("renders the map keys in the order of the descriptor", func() {
+		value := map[string]interface{}{"retry": true, "timeout": 30, "nested": map[string]interface{}{"a": 2, "z": 1}}
+		Ω(deployerString(value, keyOrders)).Should(Equal("{timeout=30, retry=true, nested={z=1, a=2}}"))
+	})
+
+	It("sorts the keys of maps which are not in the descriptor", func() {
+		value := map[string]interface{}{"retry": true, "timeout": 30, "added": "x"}
+		Ω(deployerString(value, keyOrders)).Should(Equal("{added=x, retry=true, timeout=30}"))
+	})
+})
+
+var _ = DescribeTable("javaDoubleString",
+	func(value float64, expected string) {
+		Ω(javaDoubleString(value)).Should(Equal(expected))
+	},
+	Entry("whole number", 1.0, "1.0"),
+	Entry("fraction", 0.5, "0.5"),
+	Entry("negative", -2.25, "-2.25"),
+	Entry("zero", 0.0, "0.0"),
+	Entry("large number", 1e7, "1.0E7"),
+	Entry("large fraction", 12345678.9, "1.23456789E7"),
+	Entry("small number", 0.0001, "1.0E-4"),
+	Entry("not a number", math.NaN(), "NaN"),
+	Entry("infinity", math.Inf(1), "Infinity"),
+)
@@ -0,0 +1,136 @@
+package resolver
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// The .env files are parsed line by line like godotenv.Parse does. godotenv reads the lines with a bufio.Scanner,
+// which fails on lines longer than 64k characters (e.g. a VCAP_SERVICES line), so the lines are split here instead.
+
+const (
+	dotenvEmptyLineMsg = "zero length string"
+	dotenvNoValueMsg   = "Can't separate key from value"
+)
+
+var (
+	dotenvSingleQuotedRegex = regexp.MustCompile(`\A'(.*)'\z`)
+	dotenvDoubleQuotedRegex = regexp.MustCompile(`\A"(.*)"\z`)
+	dotenvEscapeRegex       = regexp.MustCompile(`\\.`)
+	dotenvUnescapeRegex     = regexp.MustCompile(`\\([^$])`)
+	dotenvVariableRegex     = regexp.MustCompile(`(\\)?(\$)(\()?\{?([A-Z0-9_]+)?\}?`)
+)
+
+// parseDotenv returns the environment variables of the .env file content. Values can reference the variables
+// which are defined before them.
+func parseDotenv(content string) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if isIgnoredDotenvLine(line) {
+			continue
+		}
+		key, value, err := parseDotenvLine(line, values)
+		if err != nil {
+			return nil, err
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+func isIgnoredDotenvLine(line string) bool {
+	trimmed := strings.Trim(line, " \n\t")
+	return len(trimmed) == 0 || strings.HasPrefix(trimmed, "#")
+}
+
+func parseDotenvLine(line string, values map[string]string) (string, string, error) {
+	if len(line) == 0 {
+		return "", "", errors.New(dotenvEmptyLineMsg)
+	}
+	line = removeDotenvComment(line)
+
+	firstEquals := strings.Index(line, "=")
+	firstColon := strings.Index(line, ":")
+	parts := strings.SplitN(line, "=", 2)
+	if firstColon != -1 && (firstColon < firstEquals || firstEquals == -1) {
+		// A YAML-style line
+		parts = strings.SplitN(line, ":", 2)
+	}
+	if len(parts) != 2 {
+		return "", "", errors.New(dotenvNoValueMsg)
+	}
+
+	key := strings.Trim(strings.TrimPrefix(parts[0], "export"), " ")
+	return key, parseDotenvValue(parts[1], values), nil
+}
+
+// removeDotenvComment removes the comment at the end of the line, but keeps the hashes in quotes
+func removeDotenvComment(line string) string {
+	if !strings.Contains(line, "#") {
+		return line
+	}
+	quotesAreOpen := false
+	var segmentsToKeep []string
+	for _, segment := range strings.Split(line, "#") {
+		if strings.Count(segment, `"`) == 1 || strings.Count(segment, "'") == 1 {
+			if quotesAreOpen {
+				quotesAreOpen = false
+				segmentsToKeep = append(segmentsToKeep, segment)
+			} else {
+				quotesAreOpen = true
+			}
+		}
+		if len(segmentsToKeep) == 0 || quotesAreOpen {
+			segmentsToKeep = append(segmentsToKeep, segment)
+		}
+	}
+	return strings.Join(segmentsToKeep, "#")
+}
+
+func parseDotenvValue(value string, values map[string]string) string {
+	value = strings.Trim(value, " ")
+	if len(value) <= 1 {
+		return value
+	}
+
+	singleQuoted := dotenvSingleQuotedRegex.MatchString(value)
+	doubleQuoted := dotenvDoubleQuotedRegex.MatchString(value)
+	if singleQuoted || doubleQuoted {
+		value = value[1 : len(value)-1]
+	}
+	if doubleQuoted {
+		value = dotenvEscapeRegex.ReplaceAllStringFunc(value, func(match string) string {
+			switch strings.TrimPrefix(match, `\`) {
+			case "n":
+				return "\n"
+			case "r":
+				return "\r"
+			default:
+				return match
+			}
+		})
+		value = dotenvUnescapeRegex.ReplaceAllString(value, "$1")
+	}
+	if !singleQuoted {
+		value = expandDotenvVariables(value, values)
+	}
+	return value
+}
+
+func expandDotenvVariables(value string, values map[string]string) string {
+	return dotenvVariableRegex.ReplaceAllStringFunc(value, func(s string) string {
+		submatch := dotenvVariableRegex.FindStringSubmatch(s)
+		if submatch == nil {
+			return s
+		}
+		if submatch[1] == `\` || submatch[2] == "(" {
+			return submatch[0][1:]
+		} else if submatch[4] != "" {
+			return values[submatch[4]]
+		}
+		return s
+	})
+}
@@ -0,0 +1,39 @@
+package resolver
+
+import (
+	"strings"
+
+	"github.com/joho/godotenv"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("parseDotenv", func() {
+	It("parses the lines like godotenv", func() {
+		content := `# comment
+export A=1
+B = "two words" # comment
+C='single $A'
+D="multi\nline ${A} \$A"
+E: yaml-style
+F=has#hash
+G="quoted # hash"
+log-level=debug
+H=$A$B
+`
+		expected, err := godotenv.Unmarshal(content)
+		Ω(err).Should(Succeed())
+		Ω(parseDotenv(content)).Should(Equal(expected))
+		Ω(parseDotenv(strings.Replace(content, "\n", "\r\n", -1))).Should(Equal(expected))
+	})
+
+	It("parses lines which are longer than 64k characters", func() {
+		value := strings.Repeat("x", 100*1024)
+		Ω(parseDotenv("A=1\nLONG=" + value + "\nB=2")).Should(Equal(map[string]string{"A": "1", "LONG": value, "B": "2"}))
+	})
+
+	It("returns an error for a line without a value", func() {
+		_, err := parseDotenv("A=1\nB\n")
+		Ω(err).Should(MatchError(dotenvNoValueMsg))
+	})
+})
@@ -0,0 +1,163 @@
+package resolver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/SAP/cloud-mta/mta"
+)
+
+// EnvSourceContext - the context in which the environment variables are read
+type EnvSourceContext struct {
+	// The path to the project folder
+	WorkspaceDir string
+	// The resolved module; it is nil when a resource is resolved
+	Module *mta.Module
+}
+
+// EnvValues - the environment variables of a source
+type EnvValues struct {
+	// The variables, by name. Variables in the form <module-or-resource>/<name> are only used for the module or resource.
+	Values map[string]string
+	// The origin and the file of the values, which are shown in the resolution trace
+	Origin string
+	File   string
+}
+
+// EnvSource - a source of environment variables for the resolution. When several sources are used, the values
+// of the later sources take precedence.
+type EnvSource interface {
+	// Read returns the environment variables of the source; it returns nil when the source does not exist
+	Read(ctx EnvSourceContext) (*EnvValues, error)
+}
+
+type environSource struct{}
+
+// ProcessEnv returns the source of the process environment variables
+func ProcessEnv() EnvSource {
+	return environSource{}
+}
+
+func (s environSource) Read(ctx EnvSourceContext) (*EnvValues, error) {
+	values := map[string]string{}
+	for _, val := range envGetter() {
+		pos := strings.Index(val, "=")
+		if pos > 0 {
+			key := strings.Trim(val[:pos], " ")
+			values[key] = strings.Trim(val[pos+1:], " ")
+		}
+	}
+	return &EnvValues{Values: values, Origin: EnvironmentOrigin}, nil
+}
+
+type mapSource struct {
+	values map[string]string
+	origin string
+}
+
+// EnvMap returns a source of in-memory environment variables; the origin is shown in the resolution trace
+func EnvMap(values map[string]string, origin string) EnvSource {
+	return mapSource{values: values, origin: origin}
+}
+
+func (s mapSource) Read(ctx EnvSourceContext) (*EnvValues, error) {
+	return &EnvValues{Values: s.values, Origin: s.origin}, nil
+}
+
+type fileSource struct {
+	path   string
+	origin string
+	read   func(path string) (map[string]string, error)
+}
+
+// DotenvFile returns the source of a .env file. A relative path is relative to the module folder, and the file
+// is not read for modules without a path or for resources. The file is optional.
+func DotenvFile(path string) EnvSource {
+	return fileSource{path: path, origin: EnvFileOrigin, read: readDotenvFile}
+}
+
+// JSONFile returns the source of a JSON file in the default-env.json format. Values which are not strings
+// (e.g. VCAP_SERVICES) are serialized to JSON. A relative path is relative to the module folder, and the file
+// is not read for modules without a path or for resources. The file is optional.
+func JSONFile(path string) EnvSource {
+	return fileSource{path: path, origin: DefaultEnvOrigin, read: readDefaultEnvFile}
+}
+
+func (s fileSource) Read(ctx EnvSourceContext) (*EnvValues, error) {
+	path := s.path
+	if len(path) == 0 {
+		return nil, nil
+	}
+	if !filepath.IsAbs(path) {
+		if ctx.Module == nil || len(ctx.Module.Path) == 0 {
+			return nil, nil
+		}
+		path = resolvePath(path, ctx.WorkspaceDir, ctx.Module.Path)
+	}
+	values, err := s.read(path)
+	if err != nil || values == nil {
+		return nil, err
+	}
+	return &EnvValues{Values: values, Origin: s.origin, File: path}, nil
+}
+
+// readDotenvFile reads the environment variables from a .env file, if it exists.
+// An error is returned when the file exists but cannot be read or parsed.
+func readDotenvFile(path string) (map[string]string, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// The file is optional
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, readEnvFileFailMsg, path)
+	}
+	values, err := parseDotenv(string(content))
+	if err != nil {
+		return nil, errors.Wrapf(err, readEnvFileFailMsg, path)
+	}
+	return values, nil
+}
+
+// DefaultEnvSources returns the default sources of the environment variables, in order of precedence: the process
+// environment variables, the default-env.json file and the environment files in the module folder.
+// The default environment file is ".env".
+func DefaultEnvSources(envFiles ...string) []EnvSource {
+	if len(envFiles) == 0 {
+		envFiles = []string{defaultEnvFileName}
+	}
+	sources := []EnvSource{ProcessEnv(), JSONFile(defaultEnvJSONFileName)}
+	for _, envFile := range envFiles {
+		sources = append(sources, DotenvFile(envFile))
+	}
+	return sources
+}
+
+// envSources returns the sources of the environment variables from the options, or the default sources
+func (m *MTAResolver) envSources(envFilePath string) []EnvSource {
+	if m.options.EnvSources != nil {
+		return m.options.EnvSources
+	}
+	return DefaultEnvSources(envFilePath)
+}
+
+// addEnvironment adds the environment variables of the sources to the resolve context; the module is nil when
+// a resource is resolved
+func (m *MTAResolver) addEnvironment(module *mta.Module, envFilePath string) {
+	for _, source := range m.envSources(envFilePath) {
+		values, err := source.Read(EnvSourceContext{WorkspaceDir: m.WorkingDir, Module: module})
+		if err != nil {
+			m.addMessage(err.Error())
+		}
+		if values == nil {
+			continue
+		}
+		for key, value := range values.Values {
+			m.addValueToContext(key, value, values.Origin, values.File)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package resolver
+
+import (
+	"fmt"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Environment sources", func() {
+	path := getTestPath("envsource-project", "mta.yaml")
+
+	BeforeEach(func() {
+		envGetter = func() []string { return []string{"log-level=trace", "region=ap10", "db/name=env-db"} }
+	})
+
+	It("uses the process environment, default-env.json and .env by default", func() {
+		result, _, err := Resolve("", "srv", path, nil, "", nil)
+		Ω(err).Should(Succeed())
+		Ω(result.Properties).Should(Equal(map[string]string{
+			"LEVEL":  "info",
+			"PORT":   "4000",
+			"REGION": "eu10",
+			"DB":     "env-db",
+		}))
+	})
+
+	It("layers the environment files in order", func() {
+		options := &ResolveOptions{EnvSources: DefaultEnvSources(".env", ".env.local", ".env.prod")}
+		result, _, err := Resolve("", "srv", path, nil, "", options)
+		Ω(err).Should(Succeed())
+		Ω(result.Properties).Should(Equal(map[string]string{
+			"LEVEL":  "error",
+			"PORT":   "4004",
+			"REGION": "eu10",
+			"DB":     "env-db",
+		}))
+	})
+
+	It("uses only the configured sources", func() {
+		options := &ResolveOptions{EnvSources: []EnvSource{
+			JSONFile(defaultEnvJSONFileName),
+			EnvMap(map[string]string{"port": "8080", "db/name": "map-db"}, OptionsOrigin),
+		}}
+		result, messages, err := Resolve("", "srv", path, nil, "", options)
+		Ω(err).Should(Succeed())
+		Ω(result.Properties).Should(Equal(map[string]string{
+			"LEVEL":  "warning",
+			"PORT":   "8080",
+			"REGION": "us10",
+			"DB":     "map-db",
+		}))
+		Ω(messages).Should(BeEmpty())
+	})
+
+	It("reads files with absolute paths", func() {
+		envFile, err := filepath.Abs(getTestPath("envsource-project", "srv", ".env.prod"))
+		Ω(err).Should(Succeed())
+		options := &ResolveOptions{EnvSources: []EnvSource{DotenvFile(envFile)}}
+		result, _, err := Resolve("", "srv", path, nil, "", options)
+		Ω(err).Should(Succeed())
+		Ω(result.Properties["LEVEL"]).Should(Equal("error"))
+		Ω(result.Properties["PORT"]).Should(Equal("${port}"))
+	})
+
+	It("shows the origin of the values in the trace", func() {
+		options := &ResolveOptions{Trace: true, EnvSources: []EnvSource{
+			EnvMap(map[string]string{"log-level": "debug"}, OptionsOrigin),
+		}}
+		result, _, err := Resolve("", "srv", path, nil, "", options)
+		Ω(err).Should(Succeed())
+		Ω(result.Trace[0].Name).Should(Equal("LEVEL"))
+		Ω(result.Trace[0].References[0].Source).Should(Equal(&ValueSource{Scope: GlobalScope, Origin: OptionsOrigin}))
+	})
+
+	It("does not read the files relative to the module folder for a resource", func() {
+		options := &ResolveOptions{EnvSources: []EnvSource{DotenvFile(".env"), ProcessEnv()}}
+		result, _, err := ResolveResource("", "db", path, nil, options)
+		Ω(err).Should(Succeed())
+		Ω(result.Properties).Should(Equal(map[string]string{"db-name": "env-db"}))
+	})
+
+	It("reports the files which exist but cannot be read", func() {
+		envFolder, err := filepath.Abs(getTestPath("envsource-project", "srv"))
+		Ω(err).Should(Succeed())
+		for _, source := range []EnvSource{DotenvFile(envFolder), JSONFile(envFolder)} {
+			options := &ResolveOptions{EnvSources: []EnvSource{source}}
+			result, _, err := Resolve("", "srv", path, nil, "", options)
+			Ω(err).Should(Succeed())
+			Ω(result.Messages[0]).Should(HavePrefix(fmt.Sprintf(readEnvFileFailMsg, envFolder)))
+		}
+	})
+})
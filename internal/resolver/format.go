@@ -0,0 +1,173 @@
+package resolver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"github.com/pkg/errors"
+)
+
+// The output formats of the resolved environment variables
+const (
+	// DotenvFormat - KEY="value" lines, which can be read as a .env file
+	DotenvFormat = "dotenv"
+	// ShellFormat - a POSIX shell script with an export command for each variable
+	ShellFormat = "shell"
+	// DefaultEnvFormat - a default-env.json file, as used by the SAP application router and @sap/xsenv
+	DefaultEnvFormat = "default-env"
+)
+
+const (
+	unknownFormatMsg       = `the "%s" output format is not supported; use one of: %s`
+	invalidShellVarNameMsg = `the "%s" environment variable of the "%s" module is not a valid shell variable name; it was skipped`
+	moduleWithoutPathMsg   = `the "%s" module does not have a path; its environment file was not written`
+	writeModuleFileFailMsg = `could not write the environment file of the "%s" module`
+)
+
+// Formats - the supported output formats
+var Formats = []string{DotenvFormat, ShellFormat, DefaultEnvFormat}
+
+var shellVarNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ValidateFormat returns an error if the output format is not supported
+func ValidateFormat(format string) error {
+	for _, f := range Formats {
+		if f == format {
+			return nil
+		}
+	}
+	return errors.Errorf(unknownFormatMsg, format, strings.Join(Formats, ", "))
+}
+
+// FormatModule returns the module's environment variables in the output format.
+// The returned messages describe variables which cannot be represented in the format.
+func FormatModule(result ModuleResolveResult, format string) (string, []string, error) {
+	switch format {
+	case DotenvFormat:
+		content, err := godotenv.Marshal(result.Properties)
+		if err != nil {
+			return "", nil, err
+		}
+		return content + "\n", nil, nil
+	case ShellFormat:
+		content, messages := formatShell(result)
+		return content, messages, nil
+	case DefaultEnvFormat:
+		content, err := marshalDefaultEnv(toDefaultEnv(result.Properties))
+		return content, nil, err
+	}
+	return "", nil, ValidateFormat(format)
+}
+
+// FormatModules returns the environment variables of all the modules in the output format.
+// In the default-env format the result is a JSON object with the modules' environments by module name;
+// in the other formats each module's variables are preceded by a comment with the module name.
+func FormatModules(results []ModuleResolveResult, format string) (string, []string, error) {
+	if len(results) == 1 {
+		return FormatModule(results[0], format)
+	}
+	if format == DefaultEnvFormat {
+		envs := make(map[string]interface{}, len(results))
+		for _, result := range results {
+			envs[result.Name] = toDefaultEnv(result.Properties)
+		}
+		content, err := marshalDefaultEnv(envs)
+		return content, nil, err
+	}
+
+	var buf bytes.Buffer
+	var messages []string
+	for i, result := range results {
+		content, moduleMessages, err := FormatModule(result, format)
+		if err != nil {
+			return "", messages, err
+		}
+		messages = append(messages, moduleMessages...)
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		buf.WriteString("# " + result.Name + "\n")
+		buf.WriteString(content)
+	}
+	return buf.String(), messages, nil
+}
+
+// WriteModuleFiles writes the environment variables of each module in the output format to a file in the module's folder.
+// The file name is relative to the module folder.
+func WriteModuleFiles(workspaceDir string, results []ModuleResolveResult, format string, fileName string) ([]string, error) {
+	var messages []string
+	for _, result := range results {
+		if len(result.Path) == 0 {
+			messages = append(messages, fmt.Sprintf(moduleWithoutPathMsg, result.Name))
+			continue
+		}
+		content, moduleMessages, err := FormatModule(result, format)
+		if err != nil {
+			return messages, err
+		}
+		messages = append(messages, moduleMessages...)
+		err = ioutil.WriteFile(filepath.Join(workspaceDir, result.Path, fileName), []byte(content), 0644)
+		if err != nil {
+			return messages, errors.Wrapf(err, writeModuleFileFailMsg, result.Name)
+		}
+	}
+	return messages, nil
+}
+
+func formatShell(result ModuleResolveResult) (string, []string) {
+	var buf bytes.Buffer
+	var messages []string
+	for _, key := range sortedKeys(result.Properties) {
+		if !shellVarNameRegex.MatchString(key) {
+			messages = append(messages, fmt.Sprintf(invalidShellVarNameMsg, key, result.Name))
+			continue
+		}
+		// Single-quoted values are not expanded by the shell and can span multiple lines
+		buf.WriteString("export " + key + "='" + strings.Replace(result.Properties[key], "'", `'\''`, -1) + "'\n")
+	}
+	return buf.String(), messages
+}
+
+// toDefaultEnv returns the environment variables in the default-env.json structure, where JSON object and array
+// values (e.g. VCAP_SERVICES) are embedded as JSON and not as strings
+func toDefaultEnv(properties map[string]string) map[string]interface{} {
+	env := make(map[string]interface{}, len(properties))
+	for key, value := range properties {
+		trimmed := strings.TrimSpace(value)
+		if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+			var jsonValue interface{}
+			if json.Unmarshal([]byte(trimmed), &jsonValue) == nil {
+				env[key] = jsonValue
+				continue
+			}
+		}
+		env[key] = value
+	}
+	return env
+}
+
+func marshalDefaultEnv(env interface{}) (string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	// URLs in the values must not be escaped
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	err := enc.Encode(env)
+	return buf.String(), err
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,133 @@
+package resolver
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/joho/godotenv"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ResolveModules", func() {
+	yamlPath := getTestPath("modules-project", "mta.yaml")
+
+	BeforeEach(func() {
+		envGetter = func() []string { return nil }
+	})
+
+	It("resolves all the modules separately", func() {
+		results, _, err := ResolveModules("", nil, yamlPath, nil, "", nil)
+		Ω(err).Should(Succeed())
+		Ω(results).Should(HaveLen(3))
+		Ω(results[0].Name).Should(Equal("a"))
+		Ω(results[0].Path).Should(Equal("a"))
+		Ω(results[0].Properties["VALUE"]).Should(Equal("fromA"))
+		// The environment file of module a is not used for module b
+		Ω(results[1].Properties["VALUE"]).Should(Equal("${value}"))
+		Ω(results[1].Messages).Should(ConsistOf("Missing value"))
+		Ω(results[2].Path).Should(BeEmpty())
+	})
+
+	It("resolves the selected modules", func() {
+		results, _, err := ResolveModules("", []string{"c", "a"}, yamlPath, nil, "", nil)
+		Ω(err).Should(Succeed())
+		Ω(results).Should(HaveLen(2))
+		Ω(results[0].Name).Should(Equal("c"))
+		Ω(results[1].Name).Should(Equal("a"))
+	})
+
+	It("returns error when a module does not exist", func() {
+		_, _, err := ResolveModules("", []string{"a", "unknown"}, yamlPath, nil, "", nil)
+		Ω(err).Should(MatchError(fmt.Sprintf(moduleNotFoundMsg, "unknown")))
+	})
+
+	It("returns error when the mta.yaml file does not exist", func() {
+		_, _, err := ResolveModules("", nil, getTestPath("modules-project", "notExisting.yaml"), nil, "", nil)
+		Ω(err).Should(HaveOccurred())
+	})
+})
+
+var _ = Describe("Format", func() {
+	result := ModuleResolveResult{
+		Name: "a",
+		Path: "a",
+		ResolveResult: ResolveResult{Properties: map[string]string{
+			"VALUE":        "fromA",
+			"CONFIG":       `{"url":"https://a.example.com?x=1&y=2"}`,
+			"MESSAGE":      "it's\nmulti-line $HOME",
+			"invalid-name": "value",
+		}},
+	}
+
+	It("formats as a dotenv file which can be read back", func() {
+		content, messages, err := FormatModule(result, DotenvFormat)
+		Ω(err).Should(Succeed())
+		Ω(messages).Should(BeEmpty())
+		env, err := godotenv.Unmarshal(content)
+		Ω(err).Should(Succeed())
+		Ω(env).Should(Equal(result.Properties))
+	})
+
+	It("formats as a shell script and skips invalid variable names", func() {
+		content, messages, err := FormatModule(result, ShellFormat)
+		Ω(err).Should(Succeed())
+		Ω(messages).Should(ConsistOf(fmt.Sprintf(invalidShellVarNameMsg, "invalid-name", "a")))
+		Ω(content).Should(Equal(`export CONFIG='{"url":"https://a.example.com?x=1&y=2"}'
+export MESSAGE='it'\''s
+multi-line $HOME'
+export VALUE='fromA'
+`))
+	})
+
+	It("formats as a default-env.json file with embedded JSON values", func() {
+		content, _, err := FormatModule(result, DefaultEnvFormat)
+		Ω(err).Should(Succeed())
+		Ω(content).Should(ContainSubstring(`"CONFIG": {
+    "url": "https://a.example.com?x=1&y=2"
+  }`))
+		Ω(content).Should(ContainSubstring(`"VALUE": "fromA"`))
+	})
+
+	It("formats several modules with the module names", func() {
+		other := ModuleResolveResult{Name: "b", ResolveResult: ResolveResult{Properties: map[string]string{"VALUE": "b"}}}
+		content, _, err := FormatModules([]ModuleResolveResult{{Name: "a", ResolveResult: ResolveResult{Properties: map[string]string{"VALUE": "a"}}}, other}, ShellFormat)
+		Ω(err).Should(Succeed())
+		Ω(content).Should(Equal("# a\nexport VALUE='a'\n\n# b\nexport VALUE='b'\n"))
+
+		content, _, err = FormatModules([]ModuleResolveResult{result, other}, DefaultEnvFormat)
+		Ω(err).Should(Succeed())
+		Ω(content).Should(HavePrefix("{\n  \"a\": {"))
+		Ω(content).Should(ContainSubstring("\"b\": {\n    \"VALUE\": \"b\"\n  }"))
+	})
+
+	It("returns error for an unknown format", func() {
+		_, _, err := FormatModule(result, "xml")
+		Ω(err).Should(MatchError(fmt.Sprintf(unknownFormatMsg, "xml", strings.Join(Formats, ", "))))
+	})
+})
+
+var _ = Describe("WriteModuleFiles", func() {
+	AfterEach(func() {
+		os.Remove(getTestPath("modules-project", "a", ".env.resolved"))
+		os.Remove(getTestPath("modules-project", "b", ".env.resolved"))
+	})
+
+	It("writes a file to each module's folder", func() {
+		envGetter = func() []string { return nil }
+		results, _, err := ResolveModules("", nil, getTestPath("modules-project", "mta.yaml"), nil, "", nil)
+		Ω(err).Should(Succeed())
+		messages, err := WriteModuleFiles(getTestPath("modules-project"), results, DotenvFormat, ".env.resolved")
+		Ω(err).Should(Succeed())
+		Ω(messages).Should(ConsistOf(fmt.Sprintf(moduleWithoutPathMsg, "c")))
+		content, err := ioutil.ReadFile(getTestPath("modules-project", "a", ".env.resolved"))
+		Ω(err).Should(Succeed())
+		env, err := godotenv.Unmarshal(string(content))
+		Ω(err).Should(Succeed())
+		Ω(env["VALUE"]).Should(Equal("fromA"))
+		Ω(env["MESSAGE"]).Should(Equal("it's\nmulti-line"))
+		Ω(getTestPath("modules-project", "b", ".env.resolved")).Should(BeAnExistingFile())
+	})
+})
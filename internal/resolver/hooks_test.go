@@ -0,0 +1,72 @@
+package resolver
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Resolve hooks and resources", func() {
+	yamlPath := getTestPath("hooks-project", "mta.yaml")
+
+	BeforeEach(func() {
+		envGetter = func() []string { return nil }
+	})
+
+	Describe("ResolveHook", func() {
+		It("resolves the hook's parameters and the environment variables of its task", func() {
+			result, _, err := ResolveHook("", "srv", "migrate", yamlPath, nil, "", nil)
+			Ω(err).Should(Succeed())
+			Ω(result.Parameters).Should(Equal(map[string]string{
+				"name":    "migrate-eu10",
+				"command": "npm run migrate -- --level debug",
+				"memory":  "256M",
+			}))
+			Ω(result.Properties).Should(Equal(map[string]string{
+				"LOG_LEVEL":        "info",
+				"DB_SERVICE":       "eu10-db",
+				"MIGRATION_DB":     "eu10-db",
+				"MIGRATION_SCHEMA": "${missing-schema}",
+			}))
+			Ω(result.Messages).Should(Equal([]string{"Missing db/missing-schema"}))
+		})
+
+		It("returns error when the hook does not exist", func() {
+			_, _, err := ResolveHook("", "srv", "unknown", yamlPath, nil, "", nil)
+			Ω(err).Should(MatchError(fmt.Sprintf(hookNotFoundMsg, "unknown", "srv")))
+		})
+
+		It("returns error when the module does not exist", func() {
+			_, _, err := ResolveHook("", "unknown", "migrate", yamlPath, nil, "", nil)
+			Ω(err).Should(MatchError(fmt.Sprintf(moduleNotFoundMsg, "unknown")))
+		})
+	})
+
+	Describe("ResolveResource", func() {
+		It("resolves the resource's parameters and properties", func() {
+			envGetter = func() []string { return []string{"schema-prefix=test"} }
+			result, _, err := ResolveResource("", "db", yamlPath, nil, nil)
+			Ω(err).Should(Succeed())
+			Ω(result.Parameters).Should(Equal(map[string]string{
+				"service-name": "eu10-db",
+				"config":       `{"schema":"test_app"}`,
+			}))
+			Ω(result.Properties).Should(Equal(map[string]string{
+				"service": "eu10-db",
+				"schema":  "${missing-schema}",
+			}))
+			Ω(result.Messages).Should(Equal([]string{"Missing db/missing-schema"}))
+		})
+
+		It("returns error when the resource does not exist", func() {
+			_, _, err := ResolveResource("", "unknown", yamlPath, nil, nil)
+			Ω(err).Should(MatchError(fmt.Sprintf(resourceNotFoundMsg, "unknown")))
+		})
+
+		It("returns error when the resource name is empty", func() {
+			_, _, err := ResolveResource("", "", yamlPath, nil, nil)
+			Ω(err).Should(MatchError(emptyResourceNameMsg))
+		})
+	})
+})
@@ -0,0 +1,23 @@
+package resolver
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Required lists", func() {
+	BeforeEach(func() {
+		envGetter = func() []string { return nil }
+	})
+
+	It("assembles the properties of the requires sections which share a list into an array", func() {
+		result, _, err := Resolve("", "srv", getTestPath("list-project", "mta.yaml"), nil, "", nil)
+		Ω(err).Should(Succeed())
+		Ω(result.Properties).Should(Equal(map[string]string{
+			"plugins": `[{"plugin-name":"audit","url":"https://audit.example.com"},` +
+				`{"plugin-name":"metrics","url":"https://metrics.example.com"},` +
+				`{"plugin-name":"~{name}"}]`,
+		}))
+		Ω(result.Messages).Should(Equal([]string{"Missing configuration other.project:plugins/name"}))
+	})
+})
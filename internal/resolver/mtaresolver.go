@@ -3,11 +3,11 @@ package resolver
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
 
-	"github.com/joho/godotenv"
 	"github.com/pkg/errors"
 
 	"github.com/SAP/cloud-mta/internal/logs"
@@ -15,12 +15,20 @@ import (
 )
 
 const (
-	emptyModuleNameMsg = "provide a name for the module"
-	moduleNotFoundMsg  = `could not find the "%s" module`
-	marshalFailsMag    = `could not marshal the "%s" environment variable`
-	missingPrefixMsg   = `could not resolve the value for the "~{%s}" variable; missing required prefix`
-
-	defaultEnvFileName = ".env"
+	emptyModuleNameMsg   = "provide a name for the module"
+	moduleNotFoundMsg    = `could not find the "%s" module`
+	emptyResourceNameMsg = "provide a name for the resource"
+	resourceNotFoundMsg  = `could not find the "%s" resource`
+	hookNotFoundMsg      = `could not find the "%s" hook of the "%s" module`
+	marshalFailsMag      = `could not marshal the "%s" environment variable`
+	missingPrefixMsg     = `could not resolve the value for the "~{%s}" variable; missing required prefix`
+	invalidDefaultEnv    = `could not parse the "%s" file; it was ignored`
+	readEnvFileFailMsg   = `could not read the "%s" file; it was ignored`
+	groupAndListMsg      = `the "%s" required dependency of the "%s" module has both a group and a list; the deployer rejects this combination, the "%s" group was ignored`
+	groupListNameMsg     = `the "%s" name is used both as a group and as a list in the "%s" module; the deployer rejects this combination`
+
+	defaultEnvFileName     = ".env"
+	defaultEnvJSONFileName = "default-env.json"
 )
 
 var envGetter = os.Environ
@@ -28,56 +36,257 @@ var envGetter = os.Environ
 // ResolveResult is the result of the Resolve function. This is serialized to json when requested.
 type ResolveResult struct {
 	Properties map[string]string `json:"properties"`
+	// The resolved parameters of a hook or a resource
+	Parameters map[string]string `json:"parameters,omitempty"`
 	Messages   []string          `json:"messages"`
+	// How each property was resolved; it is only set when the resolution is traced
+	Trace []*PropertyTrace `json:"trace,omitempty"`
+}
+
+// ResolveOptions - the optional settings of the resolution
+type ResolveOptions struct {
+	// The platform profile, which defines the values of placeholders that are provided by the deployer
+	Profile *Profile
+	// Resolve like the deployer: structured values inside strings are rendered in the deployer's format instead of
+	// as JSON, with the map keys in the order of the descriptors, and non-string parameters of the referenced modules
+	// and resources are used as whole values
+	DeployerCompatible bool
+	// The maximum depth of nested values and placeholder references; the default is DefaultMaxDepth
+	MaxDepth int
+	// Return the trace of each property: the scopes in which its placeholders and variables were looked up,
+	// and where their values were found
+	Trace bool
+	// The sources of the environment variables, in order of precedence; the values of the later sources take
+	// precedence. The default sources are returned by DefaultEnvSources, with the environment file of the resolution.
+	EnvSources []EnvSource
+	// The VCAP_SERVICES and VCAP_APPLICATION values; they take precedence over the environment variables and files
+	VcapServices    string
+	VcapApplication string
 }
 
-// Resolve - resolve module's parameters
-func Resolve(workspaceDir, moduleName, path string, extensions []string, envFile string) (result ResolveResult, messages []string, err error) {
+// Resolve - resolve module's parameters; the options can be nil
+func Resolve(workspaceDir, moduleName, path string, extensions []string, envFile string, options *ResolveOptions) (result ResolveResult, messages []string, err error) {
 	if len(moduleName) == 0 {
 		return result, nil, errors.New(emptyModuleNameMsg)
 	}
-	mtaRaw, messages, err := mta.GetMtaFromFile(path, extensions, false)
+	m, messages, err := newResolver(workspaceDir, path, extensions, options)
 	if err != nil {
 		return result, messages, err
 	}
-	if len(workspaceDir) == 0 {
-		workspaceDir = filepath.Dir(path)
-	}
 
-	// If environment file name is not provided - set the default file name to .env
-	envFilePath := defaultEnvFileName
-	if len(envFile) > 0 {
-		envFilePath = envFile
+	for _, module := range m.GetModules() {
+		if module.Name == moduleName {
+			result, err = m.resolveModuleResult(module, envFile)
+			return result, messages, err
+		}
 	}
 
-	m := NewMTAResolver(mtaRaw, workspaceDir)
+	return result, messages, errors.Errorf(moduleNotFoundMsg, moduleName)
+}
 
-	for _, module := range m.GetModules() {
-		if module.Name == moduleName {
-			m.ResolvePropertiesAndParameters(module, envFilePath)
+// resolveModuleResult resolves the module with the environment file; the default file name is ".env"
+func (m *MTAResolver) resolveModuleResult(module *mta.Module, envFile string) (result ResolveResult, err error) {
+	result.Properties, err = m.ResolveModule(module, envFile)
+	if err != nil {
+		return result, err
+	}
+	result.Messages = m.messages
+	result.Trace = m.Trace()
+	return result, nil
+}
 
-			propVarMap, err := getPropertiesAsEnvVar(module)
+// ResolveHook - resolve the parameters and the required dependencies of a module's hook; the options can be nil.
+// The result properties are the environment variables of the hook's task and the result parameters are the hook's parameters.
+func ResolveHook(workspaceDir, moduleName, hookName, path string, extensions []string, envFile string, options *ResolveOptions) (result ResolveResult, messages []string, err error) {
+	if len(moduleName) == 0 {
+		return result, nil, errors.New(emptyModuleNameMsg)
+	}
+	m, messages, err := newResolver(workspaceDir, path, extensions, options)
+	if err != nil {
+		return result, messages, err
+	}
+	module, err := m.GetModuleByName(moduleName)
+	if err != nil {
+		return result, messages, errors.Errorf(moduleNotFoundMsg, moduleName)
+	}
+	for i := range module.Hooks {
+		hook := &module.Hooks[i]
+		if hook.Name == hookName {
+			result.Properties, err = m.ResolveHook(module, hook, envFile)
+			if err == nil {
+				result.Parameters, err = serializePropertiesAsEnvVars(hook.Parameters)
+			}
 			if err != nil {
 				return result, messages, err
 			}
-			result.Properties = propVarMap
 			result.Messages = m.messages
+			result.Trace = m.Trace()
 			return result, messages, nil
 		}
 	}
+	return result, messages, errors.Errorf(hookNotFoundMsg, hookName, moduleName)
+}
 
-	return result, messages, errors.Errorf(moduleNotFoundMsg, moduleName)
+// ResolveResource - resolve the parameters and properties of a resource; the options can be nil.
+// The resource's values are resolved based on the environment variables.
+func ResolveResource(workspaceDir, resourceName, path string, extensions []string, options *ResolveOptions) (result ResolveResult, messages []string, err error) {
+	if len(resourceName) == 0 {
+		return result, nil, errors.New(emptyResourceNameMsg)
+	}
+	m, messages, err := newResolver(workspaceDir, path, extensions, options)
+	if err != nil {
+		return result, messages, err
+	}
+	resource := m.GetResourceByName(resourceName)
+	if resource == nil {
+		return result, messages, errors.Errorf(resourceNotFoundMsg, resourceName)
+	}
+	m.ResolveResource(resource)
+	result.Properties, err = serializePropertiesAsEnvVars(resource.Properties)
+	if err == nil {
+		result.Parameters, err = serializePropertiesAsEnvVars(resource.Parameters)
+	}
+	if err != nil {
+		return result, messages, err
+	}
+	result.Messages = m.messages
+	result.Trace = m.Trace()
+	return result, messages, nil
+}
+
+// newResolver returns a resolver of the MTA file with the extensions
+func newResolver(workspaceDir, path string, extensions []string, options *ResolveOptions) (*MTAResolver, []string, error) {
+	mtaRaw, messages, err := mta.GetMtaFromFile(path, extensions, false)
+	if err != nil {
+		return nil, messages, err
+	}
+	return newResolverOfMTA(mtaRaw, workspaceDir, path, options, getDescriptors(path, extensions, options)), messages, nil
+}
+
+// newResolverOfMTA returns a resolver of the MTA, which was read from the path; the descriptors are used for the trace
+// and for the order of the map keys in the deployer compatibility mode
+func newResolverOfMTA(mtaRaw *mta.MTA, workspaceDir, path string, options *ResolveOptions, descriptors []descriptorFile) *MTAResolver {
+	if len(workspaceDir) == 0 {
+		workspaceDir = filepath.Dir(path)
+	}
+	m := NewMTAResolver(mtaRaw, workspaceDir)
+	m.SetOptions(options)
+	if options != nil && options.Trace {
+		m.enableTrace(descriptors)
+	}
+	if options != nil && options.DeployerCompatible {
+		m.keyOrders = getMapKeyOrders(descriptors)
+	}
+	return m
+}
+
+// getDescriptors returns the parsed MTA descriptors when the options require them
+func getDescriptors(path string, extensions []string, options *ResolveOptions) []descriptorFile {
+	if options == nil || !options.Trace && !options.DeployerCompatible {
+		return nil
+	}
+	return readDescriptors(path, extensions)
+}
+
+// ModuleResolveResult is the result of resolving a module in the ResolveModules function
+type ModuleResolveResult struct {
+	Name string `json:"name"`
+	// The module path, relative to the project folder
+	Path string `json:"path,omitempty"`
+	ResolveResult
+}
+
+// ResolveModules - resolve the parameters of several modules; when no module names are sent, all the modules are resolved.
+// Each module is resolved separately, so the values of a module's environment file are not used for other modules.
+func ResolveModules(workspaceDir string, moduleNames []string, path string, extensions []string, envFile string, options *ResolveOptions) (results []ModuleResolveResult, messages []string, err error) {
+	mtaRaw, messages, err := mta.GetMtaFromFile(path, extensions, false)
+	if err != nil {
+		return nil, messages, err
+	}
+	modules := mtaRaw.Modules
+	if len(moduleNames) > 0 {
+		modules = make([]*mta.Module, 0, len(moduleNames))
+		for _, moduleName := range moduleNames {
+			module, err := mtaRaw.GetModuleByName(moduleName)
+			if err != nil {
+				return nil, messages, errors.Errorf(moduleNotFoundMsg, moduleName)
+			}
+			modules = append(modules, module)
+		}
+	}
+
+	descriptors := getDescriptors(path, extensions, options)
+	for _, module := range modules {
+		// The resolution changes the MTA, so each module is resolved in a copy of it
+		m := newResolverOfMTA(copyMTA(mtaRaw), workspaceDir, path, options, descriptors)
+		moduleCopy, err := m.GetModuleByName(module.Name)
+		if err != nil {
+			return nil, messages, err
+		}
+		result, err := m.resolveModuleResult(moduleCopy, envFile)
+		if err != nil {
+			return nil, messages, err
+		}
+		results = append(results, ModuleResolveResult{Name: module.Name, Path: module.Path, ResolveResult: result})
+	}
+	return results, messages, nil
+}
+
+// readDefaultEnvFile reads the environment variables from a default-env.json file, if it exists.
+// An error is returned when the file exists but cannot be read or parsed.
+// Values which are not strings (e.g. VCAP_SERVICES) are serialized to JSON.
+func readDefaultEnvFile(path string) (map[string]string, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// The file is optional
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, readEnvFileFailMsg, path)
+	}
+	defaultEnv := map[string]any{}
+	if err := json.Unmarshal(content, &defaultEnv); err != nil {
+		return nil, errors.Errorf(invalidDefaultEnv, path)
+	}
+	return serializePropertiesAsEnvVars(defaultEnv)
 }
 
-func getPropertiesAsEnvVar(module *mta.Module) (map[string]string, error) {
+// getPropertiesAsEnvVar returns the module's environment variables. The properties of requires sections with a group
+// or a list are assembled into a JSON array, which is provided by the group or list name; requires sections which
+// share the group or the list name are combined into the same array.
+// When several sources define the same variable, the deployer uses the value of the last one: the module properties
+// are overwritten by the required dependencies without a group or a list, in the order of the requires sections,
+// and then by the groups and the lists.
+// The returned messages describe these collisions and combinations of groups and lists which the deployer rejects.
+func getPropertiesAsEnvVar(module *mta.Module) (map[string]string, []string, error) {
 	envVar := map[string]any{}
 	for key, val := range module.Properties {
 		envVar[key] = val
 	}
 
+	var messages []string
+	groups := map[string]bool{}
+	lists := map[string]bool{}
+	combined := map[string][]map[string]any{}
+	var combinedNames []string
 	for _, requires := range module.Requires {
+		name := requires.Group
+		if len(requires.List) > 0 {
+			if len(requires.Group) > 0 {
+				messages = append(messages, fmt.Sprintf(groupAndListMsg, requires.Name, module.Name, requires.Group))
+			}
+			name = requires.List
+			lists[name] = true
+		} else if len(name) > 0 {
+			groups[name] = true
+		}
+		if len(name) > 0 && groups[name] && lists[name] && !containsString(messages, fmt.Sprintf(groupListNameMsg, name, module.Name)) {
+			messages = append(messages, fmt.Sprintf(groupListNameMsg, name, module.Name))
+		}
+
 		propMap := envVar
-		if len(requires.Group) > 0 {
+		if len(name) > 0 {
+			// The requires sections of a group or a list are combined into the same variable
 			propMap = map[string]any{}
 		}
 
@@ -85,20 +294,26 @@ func getPropertiesAsEnvVar(module *mta.Module) (map[string]string, error) {
 			propMap[key] = val
 		}
 
-		if len(requires.Group) > 0 {
-			//append the array element to group
-			group, ok := envVar[requires.Group]
-			if ok {
-				groupArray := group.([]map[string]any)
-				envVar[requires.Group] = append(groupArray, propMap)
-			} else {
-				envVar[requires.Group] = []map[string]any{propMap}
+		if len(name) > 0 {
+			//append the array element to group or list
+			if _, ok := combined[name]; !ok {
+				combinedNames = append(combinedNames, name)
 			}
+			combined[name] = append(combined[name], propMap)
 		}
 	}
+	// The deployer applies the groups and the lists after the required dependencies without them
+	for _, name := range combinedNames {
+		envVar[name] = combined[name]
+	}
+
+	for _, collision := range GetEnvVarCollisions(module) {
+		messages = append(messages, collision.Message(module.Name))
+	}
 
 	//serialize
-	return serializePropertiesAsEnvVars(envVar)
+	props, err := serializePropertiesAsEnvVars(envVar)
+	return props, messages, err
 }
 
 func serializePropertiesAsEnvVars(envVar map[string]any) (map[string]string, error) {
@@ -125,6 +340,16 @@ type MTAResolver struct {
 	WorkingDir string
 	context    *ResolveContext
 	messages   []string
+	options    ResolveOptions
+	// The generated placeholder values, by module and placeholder name
+	generatedValues map[string]string
+	// The parameters which are being resolved and the depth of the resolved value
+	chain resolutionChain
+	depth int
+	// The property traces; nil when the resolution is not traced
+	trace *resolutionTrace
+	// The order of the map keys in the descriptors, for the deployer compatibility mode
+	keyOrders mapKeyOrders
 }
 
 const resourceType = 1
@@ -144,11 +369,11 @@ type mtaSource struct {
 
 // NewMTAResolver is a factory function for MTAResolver
 func NewMTAResolver(m *mta.MTA, workspaceDir string) *MTAResolver {
-	resolver := &MTAResolver{*m, workspaceDir, &ResolveContext{
+	resolver := &MTAResolver{MTA: *m, WorkingDir: workspaceDir, context: &ResolveContext{
 		global:    map[string]string{},
 		modules:   map[string]map[string]string{},
 		resources: map[string]map[string]string{},
-	}, []string{}}
+	}, messages: []string{}}
 
 	for _, module := range m.Modules {
 		resolver.context.modules[module.Name] = map[string]string{}
@@ -168,10 +393,22 @@ func resolvePath(path string, parts ...string) string {
 	return absolutePath
 }
 
+// addVcapOptions adds the VCAP variables of the options to the resolve context
+func (m *MTAResolver) addVcapOptions() {
+	if len(m.options.VcapServices) > 0 {
+		m.addValueToContext("VCAP_SERVICES", m.options.VcapServices, OptionsOrigin, "")
+	}
+	if len(m.options.VcapApplication) > 0 {
+		m.addValueToContext("VCAP_APPLICATION", m.options.VcapApplication, OptionsOrigin, "")
+	}
+}
+
 // ResolveResourceProperties is the main function to trigger the resolution
 func (m *MTAResolver) ResolveResourceProperties(resource *mta.Resource) {
 	for key, value := range resource.Parameters {
-		resource.Parameters[key] = m.resolvePlaceholders(nil, nil, nil, value)
+		resource.Parameters[key] = m.resolveParameterValue(resource.Name+"/"+key, key, func() any {
+			return m.resolvePlaceholders(nil, nil, nil, value)
+		})
 	}
 }
 
@@ -182,63 +419,134 @@ func (m *MTAResolver) ResolvePropertiesAndParameters(module *mta.Module, envFile
 		m.Parameters = map[string]any{}
 	}
 
-	//add env variables
-	for _, val := range envGetter() {
-		pos := strings.Index(val, "=")
-		if pos > 0 {
-			key := strings.Trim(val[:pos], " ")
-			value := strings.Trim(val[pos+1:], " ")
-			m.addValueToContext(key, value)
-		}
-	}
-
-	//add .env file in module's path to the module context
-	if len(module.Path) > 0 {
-		envFile := resolvePath(envFilePath, m.WorkingDir, module.Path)
-		envMap, err := godotenv.Read(envFile)
-		if err == nil {
-			for key, value := range envMap {
-				m.addValueToContext(key, value)
-			}
-		}
-	}
+	//add env variables, and by default the default-env.json and .env files in module's path; the .env file values take precedence
+	m.addEnvironment(module, envFilePath)
+	m.addVcapOptions()
 	m.addServiceNames(module)
+	m.addApplicationParameters(module)
 
 	//top level properties
 	for key, value := range module.Properties {
+		m.startPropertyTrace(key, "", "modules", module.Name, "properties", key)
 		//no expected variables
 		propValue := m.resolve(module, nil, value)
 		module.Properties[key] = m.resolvePlaceholders(module, nil, nil, propValue)
+		m.endPropertyTrace(module.Properties[key])
 	}
 
 	// top level parameters
 	for key, value := range module.Parameters {
 		// replace value with resolved value
-		paramValue := m.resolve(module, nil, value)
-		module.Parameters[key] = m.resolvePlaceholders(module, nil, nil, paramValue)
+		module.Parameters[key] = m.resolveParameterValue(module.Name+"/"+key, key, func() any {
+			paramValue := m.resolve(module, nil, value)
+			return m.resolvePlaceholders(module, nil, nil, paramValue)
+		})
 	}
 
 	//required properties / parameters:
-	for _, req := range module.Requires {
+	m.resolveRequires(module, module.Requires, "modules", module.Name)
+}
+
+// resolveRequires resolves the properties and parameters of the module's or the hook's required dependencies.
+// The path is the path of the requires sections' parent in the MTA descriptor.
+func (m *MTAResolver) resolveRequires(module *mta.Module, requires []mta.Requires, path ...string) {
+	for _, req := range requires {
 		requiredSource := m.findProvider(req.Name)
 
 		// properties
 		for propName, PropValue := range req.Properties {
+			m.startPropertyTrace(propName, req.Name, append(path, "requires", req.Name, "properties", propName)...)
 			resolvedValue := m.resolve(module, &req, PropValue)
 			//replace value with resolved value
 			req.Properties[propName] = m.resolvePlaceholders(module, requiredSource, &req, resolvedValue)
+			m.endPropertyTrace(req.Properties[propName])
 		}
 
 		// parameters
 		for key, value := range req.Parameters {
 			// replace value with resolved value
-			paramValue := m.resolve(module, &req, value)
-			req.Parameters[key] = m.resolvePlaceholders(module, nil, nil, paramValue)
+			req.Parameters[key] = m.resolveParameterValue(requiresLocation(module, &req, key), key, func() any {
+				paramValue := m.resolve(module, &req, value)
+				return m.resolvePlaceholders(module, nil, nil, paramValue)
+			})
 		}
 	}
 }
 
-func (m *MTAResolver) addValueToContext(key, value string) {
+// ResolveModule resolves the module's properties and parameters and returns the module's environment variables.
+// The environment file path is relative to the module folder; the default file path is ".env".
+func (m *MTAResolver) ResolveModule(module *mta.Module, envFilePath string) (map[string]string, error) {
+	if len(envFilePath) == 0 {
+		envFilePath = defaultEnvFileName
+	}
+	m.ResolvePropertiesAndParameters(module, envFilePath)
+	props, messages, err := getPropertiesAsEnvVar(module)
+	for _, message := range messages {
+		m.addMessage(message)
+	}
+	return props, err
+}
+
+// ResolveHook resolves the module and the parameters and required dependencies of its hook, and returns the
+// environment variables of the hook's task: the module's environment variables and the properties of the hook's
+// required dependencies. The environment file path is relative to the module folder; the default file path is ".env".
+func (m *MTAResolver) ResolveHook(module *mta.Module, hook *mta.Hook, envFilePath string) (map[string]string, error) {
+	if len(envFilePath) == 0 {
+		envFilePath = defaultEnvFileName
+	}
+	m.ResolvePropertiesAndParameters(module, envFilePath)
+
+	for key, value := range hook.Parameters {
+		hook.Parameters[key] = m.resolveParameterValue(module.Name+"/hooks/"+hook.Name+"/"+key, key, func() any {
+			paramValue := m.resolve(module, nil, value)
+			return m.resolvePlaceholders(module, nil, nil, paramValue)
+		})
+	}
+	m.resolveRequires(module, hook.Requires, "modules", module.Name, "hooks", hook.Name)
+
+	// The hook's task runs with the module's environment and the hook's required dependencies
+	task := mta.Module{Name: module.Name, Properties: module.Properties, Requires: append(append([]mta.Requires{}, module.Requires...), hook.Requires...)}
+	props, messages, err := getPropertiesAsEnvVar(&task)
+	for _, message := range messages {
+		m.addMessage(message)
+	}
+	return props, err
+}
+
+// ResolveResource resolves the resource's parameters and properties, based on the environment variables
+func (m *MTAResolver) ResolveResource(resource *mta.Resource) {
+	if m.Parameters == nil {
+		m.Parameters = map[string]any{}
+	}
+	m.addEnvironment(nil, "")
+	m.addVcapOptions()
+	m.addServiceNames(nil)
+	m.ResolveResourceProperties(resource)
+
+	source := &mtaSource{Name: resource.Name, Properties: resource.Properties, Parameters: resource.Parameters, Type: resourceType, Resource: resource}
+	for key, value := range resource.Properties {
+		m.startPropertyTrace(key, "", "resources", resource.Name, "properties", key)
+		resource.Properties[key] = m.resolvePlaceholders(nil, source, nil, value)
+		m.endPropertyTrace(resource.Properties[key])
+	}
+}
+
+// Messages returns the messages about values that could not be resolved
+func (m *MTAResolver) Messages() []string {
+	return m.messages
+}
+
+// SetOptions sets the optional settings of the resolution
+func (m *MTAResolver) SetOptions(options *ResolveOptions) {
+	if options == nil {
+		m.options = ResolveOptions{}
+	} else {
+		m.options = *options
+	}
+}
+
+// addValueToContext adds a value which was configured externally; the origin and the file are used for tracing
+func (m *MTAResolver) addValueToContext(key, value string, origin string, file string) {
 	//if the key has format of "module/key", or "resource/key" writes the value to the module's context
 	slashPos := strings.Index(key, "/")
 	if slashPos > 0 {
@@ -250,14 +558,20 @@ func (m *MTAResolver) addValueToContext(key, value string) {
 		}
 		if ok {
 			modulesContext[key] = value
+			m.traceOrigin(contextLocation(modName, key), origin, file)
 		}
 	} else {
 		m.context.global[key] = value
+		m.traceOrigin(contextLocation("", key), origin, file)
 	}
 
 }
 
 func (m *MTAResolver) resolve(sourceModule *mta.Module, requires *mta.Requires, valueObj any) any {
+	if !m.enterNested() {
+		return valueObj
+	}
+	defer m.exitNested()
 	switch valueObj := valueObj.(type) {
 	case map[any]any:
 		v := convertToJSONSafe(valueObj)
@@ -302,7 +616,7 @@ func (m *MTAResolver) resolveString(sourceModule *mta.Module, requires *mta.Requ
 		return varValue
 	}
 	for pos >= 0 {
-		varValueStr, _ := convertToString(varValue)
+		varValueStr := m.valueToString(varValue)
 		value = value[:pos] + varValueStr + value[pos+len(variableName)+3:]
 
 		pos, variableName, _ = parseNextVariable(pos+len(varValueStr), value, variablePrefix)
@@ -327,6 +641,15 @@ func convertToString(valueObj any) (string, bool) {
 	return string(valueBytes), true
 }
 
+// valueToString converts a value which is used inside a larger string
+func (m *MTAResolver) valueToString(valueObj any) string {
+	if m.options.DeployerCompatible {
+		return deployerString(valueObj, m.keyOrders)
+	}
+	str, _ := convertToString(valueObj)
+	return str
+}
+
 // return start position, name of variable and if it is a whole value
 func parseNextVariable(pos int, value string, prefix string) (int, string, bool) {
 
@@ -353,6 +676,8 @@ func parseNextVariable(pos int, value string, prefix string) (int, string, bool)
 }
 
 func (m *MTAResolver) getVariableValue(sourceModule *mta.Module, requires *mta.Requires, variableName string) any {
+	m.startReferenceTrace("~{" + variableName + "}")
+	defer m.endReferenceTrace()
 	var providerName string
 	if requires == nil {
 		slashPos := strings.Index(variableName, "/")
@@ -369,30 +694,73 @@ func (m *MTAResolver) getVariableValue(sourceModule *mta.Module, requires *mta.R
 	}
 
 	source := m.findProvider(providerName)
+	if value, ok := m.getProvidedProperty(ProvidedPropertiesScope, source, providerName, variableName); ok {
+		return value
+	}
+
+	if source != nil && source.Type == resourceType && source.Resource.Type == "configuration" {
+		provID, ok := getStringFromMap(source.Resource.Parameters, "provider-id")
+		if ok {
+			// The configuration can be published by this MTA
+			if providesName, found := m.findPublicProvides(provID); found {
+				value, ok := m.getProvidedProperty(ConfigurationScope, m.findProvider(providesName), providesName, variableName)
+				if ok {
+					return value
+				}
+			}
+			m.addMessage(fmt.Sprint("Missing configuration ", provID, "/", variableName))
+		}
+	}
+
+	return "~{" + variableName + "}"
+}
+
+// getProvidedProperty returns the resolved value of the property which is provided by the source
+func (m *MTAResolver) getProvidedProperty(scope string, source *mtaSource, providerName string, variableName string) (any, bool) {
 	if source != nil {
 		for propName, propValue := range source.Properties {
 			if propName == variableName {
+				m.traceStep(scope, true)
+				path := []string{"resources", source.Name, "properties", propName}
+				if source.Type == moduleType {
+					path = []string{"modules", source.Name, "provides", providerName, "properties", propName}
+				}
+				m.traceSource(m.descriptorSource(scope, path...))
 
 				//Do not pass module and requires, because it is a wrong scope
 				//it is either global->module->requires
 				//or           global->resource
 				propValue = m.resolvePlaceholders(nil, source, nil, propValue)
-				return convertToJSONSafe(propValue)
+				return convertToJSONSafe(propValue), true
 			}
 		}
 	}
+	m.traceStep(scope, false)
+	return nil, false
+}
 
-	if source != nil && source.Type == resourceType && source.Resource.Type == "configuration" {
-		provID, ok := getStringFromMap(source.Resource.Parameters, "provider-id")
-		if ok {
-			m.addMessage(fmt.Sprint("Missing configuration ", provID, "/", variableName))
+// findPublicProvides returns the name of the public provides section of this MTA which matches the provider ID
+// of a configuration resource. The provider ID has the format <mta-id>:<provides-name>.
+func (m *MTAResolver) findPublicProvides(providerID string) (string, bool) {
+	parts := strings.SplitN(providerID, ":", 2)
+	if len(parts) != 2 || parts[0] != m.ID {
+		return "", false
+	}
+	for _, module := range m.Modules {
+		for _, provides := range module.Provides {
+			if provides.Name == parts[1] && provides.Public {
+				return provides.Name, true
+			}
 		}
 	}
-
-	return "~{" + variableName + "}"
+	return "", false
 }
 
 func (m *MTAResolver) resolvePlaceholders(sourceModule *mta.Module, source *mtaSource, requires *mta.Requires, valueObj any) any {
+	if !m.enterNested() {
+		return valueObj
+	}
+	defer m.exitNested()
 	switch valueObj := valueObj.(type) {
 	case map[any]any:
 		v := convertToJSONSafe(valueObj)
@@ -423,115 +791,185 @@ func (m *MTAResolver) resolvePlaceholders(sourceModule *mta.Module, source *mtaS
 }
 
 func (m *MTAResolver) resolvePlaceholdersString(sourceModule *mta.Module, source *mtaSource, requires *mta.Requires, value string) any {
-	originalValue := value
 	pos, placeholderName, wholeValue := parseNextVariable(0, value, placeholderPrefix)
 
 	if pos < 0 {
 		return value
 	}
-	placeholderValue := m.getParameter(sourceModule, source, requires, placeholderName)
-
 	if wholeValue {
-		if placeholderValue == originalValue {
-			return placeholderValue
-		} else {
-			return m.resolvePlaceholders(sourceModule, source, requires, placeholderValue)
-		}
+		return m.resolvePlaceholder(sourceModule, source, requires, placeholderName)
 	}
 	for pos >= 0 {
-		phValueStr, _ := convertToString(placeholderValue)
+		phValueStr := m.valueToString(m.resolvePlaceholder(sourceModule, source, requires, placeholderName))
 		value = value[:pos] + phValueStr + value[pos+len(placeholderName)+3:]
 		pos, placeholderName, _ = parseNextVariable(pos+len(phValueStr), value, placeholderPrefix)
-		if pos >= 0 {
-			placeholderValue = m.getParameter(sourceModule, source, requires, placeholderName)
-		}
 	}
+	return value
+}
 
-	if originalValue == value {
-		return value
-	} else {
-		return m.resolvePlaceholdersString(sourceModule, source, requires, value)
+// resolvePlaceholder returns the resolved value of the placeholder. Placeholders which reference themselves, directly or
+// through other placeholders, are not resolved.
+func (m *MTAResolver) resolvePlaceholder(sourceModule *mta.Module, source *mtaSource, requires *mta.Requires, placeholderName string) any {
+	unresolved := "${" + placeholderName + "}"
+	m.startReferenceTrace(unresolved)
+	defer m.endReferenceTrace()
+	placeholderValue, location, found := m.lookupParameter(sourceModule, source, requires, placeholderName)
+	if !found {
+		m.addMissingParameterMessage(source, placeholderName)
+		return unresolved
+	}
+	if m.chain.contains(location) {
+		m.addMessage(fmt.Sprintf(cyclicReferenceMsg, placeholderName, m.chain.describe(location, placeholderName)))
+		return unresolved
 	}
+
+	return m.resolveParameterValue(location, placeholderName, func() any {
+		return m.resolvePlaceholders(sourceModule, source, requires, placeholderValue)
+	})
 }
 
-func (m *MTAResolver) getParameterFromSource(source *mtaSource, paramName string) string {
-	if source != nil {
-		// See if the value was configured externally first (in VCAP_SERVICES, env var etc)
-		// The source can be a module or a resource
-		module, found := m.context.modules[source.Name]
-		if found {
-			paramValStr, ok := module[paramName]
-			if ok {
-				return paramValStr
-			}
+// getParameterFromSourceContext returns the value of the parameter of the source which was configured externally
+// (in VCAP_SERVICES, env var etc) and the location where it was found. The source can be a module or a resource.
+func (m *MTAResolver) getParameterFromSourceContext(source *mtaSource, paramName string) (string, string) {
+	module, found := m.context.modules[source.Name]
+	if found {
+		paramValStr, ok := module[paramName]
+		if ok {
+			return paramValStr, contextLocation(source.Name, paramName)
 		}
+	}
 
-		resource, found := m.context.resources[source.Name]
-		if found {
-			paramValStr, ok := resource[paramName]
-			if ok {
-				return paramValStr
-			}
+	resource, found := m.context.resources[source.Name]
+	if found {
+		paramValStr, ok := resource[paramName]
+		if ok {
+			return paramValStr, contextLocation(source.Name, paramName)
 		}
+	}
+	return "", ""
+}
 
-		// If it was not defined externally, try to get it from the source parameters
-		paramVal, found := getStringFromMap(source.Parameters, paramName)
-		if found {
-			return paramVal
+// getParameterFromSource returns the value of the parameter in the source's parameters
+func (m *MTAResolver) getParameterFromSource(source *mtaSource, paramName string) (any, bool) {
+	paramValStr, found := getStringFromMap(source.Parameters, paramName)
+	if found && paramValStr != "" {
+		return paramValStr, true
+	}
+	// The deployer also uses non-string parameters of the source, as whole values
+	if m.options.DeployerCompatible {
+		paramVal, ok := source.Parameters[paramName]
+		if _, isString := paramVal.(string); ok && paramVal != nil && !isString {
+			return paramVal, true
 		}
-
 	}
-	return ""
+	return nil, false
 }
 
 func (m *MTAResolver) getParameter(sourceModule *mta.Module, source *mtaSource, requires *mta.Requires, paramName string) any {
-	//first on source parameters scope
-	paramValStr := m.getParameterFromSource(source, paramName)
+	paramVal, _, found := m.lookupParameter(sourceModule, source, requires, paramName)
+	if !found {
+		m.addMissingParameterMessage(source, paramName)
+		return "${" + paramName + "}"
+	}
+	return paramVal
+}
 
+// lookupParameter returns the value of the parameter and the location where it was found, which identifies the value
+// for cycle detection
+func (m *MTAResolver) lookupParameter(sourceModule *mta.Module, source *mtaSource, requires *mta.Requires, paramName string) (any, string, bool) {
 	//first on source parameters scope
-	if paramValStr != "" {
-		return paramValStr
+	if source != nil {
+		paramValStr, location := m.getParameterFromSourceContext(source, paramName)
+		m.traceStep(SourceContextScope, paramValStr != "")
+		if paramValStr != "" {
+			m.traceSource(m.contextSource(SourceContextScope, location))
+			return paramValStr, location, true
+		}
+
+		paramVal, ok := m.getParameterFromSource(source, paramName)
+		m.traceStep(SourceParametersScope, ok)
+		if ok {
+			m.traceSource(m.descriptorSource(SourceParametersScope, append(sourcePath(source), "parameters", paramName)...))
+			return paramVal, source.Name + "/" + paramName, true
+		}
 	}
 
 	//then try on requires level
 	if requires != nil {
 		paramVal, ok := requires.Parameters[paramName]
+		m.traceStep(RequiresParametersScope, ok)
 		if ok {
-			return paramVal
+			if sourceModule != nil {
+				m.traceSource(m.descriptorSource(RequiresParametersScope,
+					"modules", sourceModule.Name, "requires", requires.Name, "parameters", paramName))
+			}
+			return paramVal, requiresLocation(sourceModule, requires, paramName), true
 		}
 	}
 
 	if sourceModule != nil {
 		paramVal, ok := sourceModule.Parameters[paramName]
+		m.traceStep(ModuleParametersScope, ok)
 		if ok {
-			return paramVal
+			m.traceSource(m.descriptorSource(ModuleParametersScope, "modules", sourceModule.Name, "parameters", paramName))
+			return paramVal, sourceModule.Name + "/" + paramName, true
 		}
 		//defaults to context's module params:
-		paramValStr, ok = m.context.modules[sourceModule.Name][paramName]
+		paramValStr, ok := m.context.modules[sourceModule.Name][paramName]
+		m.traceStep(ModuleContextScope, ok)
 		if ok {
-			return paramValStr
+			location := contextLocation(sourceModule.Name, paramName)
+			m.traceSource(m.contextSource(ModuleContextScope, location))
+			return paramValStr, location, true
 		}
 	}
 
 	//then on MTA root scope
 	paramVal, ok := m.Parameters[paramName]
+	m.traceStep(MTAParametersScope, ok)
 	if ok {
-		return paramVal
+		m.traceSource(m.descriptorSource(MTAParametersScope, "parameters", paramName))
+		return paramVal, "/" + paramName, true
 	}
 
 	//then global scope
-	paramValStr, ok = m.context.global[paramName]
+	paramValStr, ok := m.context.global[paramName]
+	m.traceStep(GlobalScope, ok)
 	if ok {
-		return paramValStr
+		location := contextLocation("", paramName)
+		m.traceSource(m.contextSource(GlobalScope, location))
+		return paramValStr, location, true
 	}
 
-	if source == nil {
-		m.addMessage(fmt.Sprint("Missing ", paramName))
-	} else {
-		m.addMessage(fmt.Sprint("Missing ", source.Name+"/"+paramName))
+	//then the platform profile
+	paramValStr, ok = m.getProfileValue(sourceModule, paramName)
+	m.traceStep(ProfileScope, ok)
+	if ok {
+		m.traceSource(ValueSource{Scope: ProfileScope})
+		return paramValStr, "profile:/" + paramName, true
+	}
+
+	return nil, "", false
+}
+
+// sourcePath returns the path of the source module or resource in the MTA descriptor
+func sourcePath(source *mtaSource) []string {
+	if source.Type == resourceType {
+		return []string{"resources", source.Name}
 	}
+	return []string{"modules", source.Name}
+}
 
-	return "${" + paramName + "}"
+func (m *MTAResolver) addMissingParameterMessage(source *mtaSource, paramName string) {
+	missingName := paramName
+	if source != nil {
+		missingName = source.Name + "/" + paramName
+	}
+	if deployerPlaceholders[paramName] {
+		m.addMessage(fmt.Sprintf(deployerPlaceholderMsg, missingName))
+	} else {
+		m.addMessage(fmt.Sprint("Missing ", missingName))
+	}
 }
 
 func (m *MTAResolver) findProvider(name string) *mtaSource {
@@ -603,7 +1041,7 @@ func getStringFromMap(params map[string]any, key string) (string, bool) {
 	//   stuct_field: abc
 	// We will get a resolved value like this from the Deployer:
 	// "this is the prop: {stuct_field=abc}"
-	// We do not support this use case currently.
+	// This use case is supported in the deployer compatibility mode (see deployerString).
 	value, ok := params[key]
 	if ok && value != nil {
 		str, isString := value.(string)
@@ -14,7 +14,7 @@ import (
 )
 
 func callResolveAndGetOutput(wd, moduleName, yamlPath string, extensions []string, envFileName string) (ResolveResult, []string) {
-	result, messages, err := Resolve(wd, moduleName, yamlPath, extensions, envFileName)
+	result, messages, err := Resolve(wd, moduleName, yamlPath, extensions, envFileName, nil)
 	Ω(err).Should(Succeed())
 	return result, messages
 }
@@ -255,8 +255,6 @@ var _ = Describe("Resolve", func() {
 		callResolveAndValidateOutput(wd, "eb-java", yamlPath, nil, ".env_with_vcap", expected, BeEmpty())
 	})
 
-	// If this fail you may have forgot to apply a patch
-	// see the `patches` directory in the root of this repo
 	It("resolves vcap_services from large env file ", func() {
 		wd := getTestPath("test-project")
 		yamlPath := getTestPath("test-project", "mtaLargeEnv.yaml")
@@ -319,25 +317,25 @@ var _ = Describe("Resolve", func() {
 		callResolveAndValidateOutput("", "eb-java", yamlPath, nil, "", expected, BeEmpty())
 	})
 	It("returns error when module name is empty", func() {
-		_, _, err := Resolve("", "", getTestPath("test-project", "mta.yaml"), nil, "")
+		_, _, err := Resolve("", "", getTestPath("test-project", "mta.yaml"), nil, "", nil)
 		Ω(err).Should(HaveOccurred())
 		Ω(err.Error()).Should(Equal(emptyModuleNameMsg))
 	})
 	It("returns error when module does not exist", func() {
-		_, _, err := Resolve("", "aaa", getTestPath("test-project", "mta.yaml"), nil, "")
+		_, _, err := Resolve("", "aaa", getTestPath("test-project", "mta.yaml"), nil, "", nil)
 
 		Ω(err).Should(HaveOccurred())
 		Ω(err.Error()).Should(Equal(fmt.Sprintf(moduleNotFoundMsg, "aaa")))
 	})
 	It("returns error when mta yaml path is not found", func() {
 		path := getTestPath("test-project", "mtaNotExist.yaml")
-		_, _, err := Resolve("", "eb-java", path, nil, "")
+		_, _, err := Resolve("", "eb-java", path, nil, "", nil)
 		Ω(err).Should(HaveOccurred())
 		Ω(err.Error()).Should(ContainSubstring(fmt.Sprintf(fs.PathNotFoundMsg, path)))
 	})
 	It("returns error when mta.yaml is invalid", func() {
 		path := getTestPath("test-project", "mtaBad.yaml")
-		_, _, err := Resolve("", "eb-java", path, nil, "")
+		_, _, err := Resolve("", "eb-java", path, nil, "", nil)
 		Ω(err).Should(HaveOccurred())
 		Ω(err.Error()).Should(ContainSubstring(fmt.Sprintf(mta.UnmarshalFailsMsg, path)))
 	})
@@ -414,7 +412,7 @@ var _ = Describe("getPropertiesAsEnvVar", func() {
 				"a": func() {},
 			},
 		}
-		_, err := getPropertiesAsEnvVar(&mod)
+		_, _, err := getPropertiesAsEnvVar(&mod)
 		Ω(err).Should(HaveOccurred())
 		Ω(err.Error()).Should(Equal(fmt.Sprintf(marshalFailsMag, "a")))
 	})
@@ -441,12 +439,118 @@ var _ = Describe("getPropertiesAsEnvVar", func() {
 				},
 			},
 		}
-		props, err := getPropertiesAsEnvVar(&mod)
+		props, messages, err := getPropertiesAsEnvVar(&mod)
 		Ω(err).Should(Succeed())
+		Ω(messages).Should(BeEmpty())
 		Ω(len(props)).Should(Equal(2))
 		Ω(props["group1"]).Should(Equal(`[{"prop1":"value1","prop2":"value2"},{"prop3":"value3"}]`))
 		Ω(props["prop4"]).Should(Equal(`value4`))
 	})
+	It("required lists defined", func() {
+		mod := mta.Module{
+			Requires: []mta.Requires{
+				{
+					Name: "plugin1",
+					List: "plugins",
+					Properties: map[string]interface{}{
+						"name": "plugin1",
+					},
+				},
+				{
+					Name: "plugin2",
+					List: "plugins",
+					Properties: map[string]interface{}{
+						"name": "plugin2",
+					},
+				},
+			},
+		}
+		props, messages, err := getPropertiesAsEnvVar(&mod)
+		Ω(err).Should(Succeed())
+		Ω(messages).Should(BeEmpty())
+		Ω(props).Should(Equal(map[string]string{"plugins": `[{"name":"plugin1"},{"name":"plugin2"}]`}))
+	})
+	It("returns messages for combinations of groups and lists", func() {
+		mod := mta.Module{
+			Name: "mod",
+			Requires: []mta.Requires{
+				{
+					Name:       "req1",
+					Group:      "group1",
+					List:       "list1",
+					Properties: map[string]interface{}{"prop1": "value1"},
+				},
+				{
+					Name:       "req2",
+					Group:      "list1",
+					Properties: map[string]interface{}{"prop2": "value2"},
+				},
+			},
+		}
+		props, messages, err := getPropertiesAsEnvVar(&mod)
+		Ω(err).Should(Succeed())
+		Ω(messages).Should(Equal([]string{
+			fmt.Sprintf(groupAndListMsg, "req1", "mod", "group1"),
+			fmt.Sprintf(groupListNameMsg, "list1", "mod"),
+		}))
+		Ω(props).Should(Equal(map[string]string{"list1": `[{"prop1":"value1"},{"prop2":"value2"}]`}))
+	})
+	It("returns messages for variables which are defined by several sources", func() {
+		mod := mta.Module{
+			Name:       "mod",
+			Properties: map[string]interface{}{"url": "module", "group1": "module"},
+			Requires: []mta.Requires{
+				{
+					Name:       "req1",
+					Properties: map[string]interface{}{"url": "req1"},
+				},
+				{
+					Name:       "req2",
+					Properties: map[string]interface{}{"url": "req2"},
+				},
+				{
+					Name:       "req3",
+					Group:      "group1",
+					Properties: map[string]interface{}{"prop": "req3"},
+				},
+				{
+					Name:       "req4",
+					Group:      "group1",
+					Properties: map[string]interface{}{"prop": "req4"},
+				},
+			},
+		}
+		props, messages, err := getPropertiesAsEnvVar(&mod)
+		Ω(err).Should(Succeed())
+		Ω(props["url"]).Should(Equal("req2"))
+		Ω(props["group1"]).Should(Equal(`[{"prop":"req3"},{"prop":"req4"}]`))
+		Ω(messages).Should(Equal([]string{
+			fmt.Sprintf(envVarCollisionMsg, "group1", "mod", `the module properties, the "group1" group`, `the "group1" group`),
+			fmt.Sprintf(envVarCollisionMsg, "url", "mod", `the module properties, the "req1" required dependency, the "req2" required dependency`, `the "req2" required dependency`),
+		}))
+	})
+	It("uses the group when a required dependency without a group defines the same variable", func() {
+		mod := mta.Module{
+			Name: "mod",
+			Requires: []mta.Requires{
+				{
+					Name:       "audit",
+					Group:      "destinations",
+					Properties: map[string]interface{}{"url": "audit"},
+				},
+				{
+					Name:       "backend",
+					Properties: map[string]interface{}{"destinations": "backend"},
+				},
+			},
+		}
+		props, messages, err := getPropertiesAsEnvVar(&mod)
+		Ω(err).Should(Succeed())
+		Ω(props).Should(Equal(map[string]string{"destinations": `[{"url":"audit"}]`}))
+		Ω(messages).Should(Equal([]string{
+			fmt.Sprintf(envVarCollisionMsg, "destinations", "mod", `the "backend" required dependency, the "destinations" group`, `the "destinations" group`),
+		}))
+	})
 })
 
 var _ = Describe("convertToString", func() {
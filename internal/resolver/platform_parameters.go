@@ -18,7 +18,19 @@ type VcapService struct {
 	Plan         string   `json:"plan"`
 }
 
-const tagResourceNamePrefix = "mta-resource-name:"
+// vcapApplication - the VCAP_APPLICATION structure, which describes the deployed application
+type vcapApplication struct {
+	ApplicationName  string   `json:"application_name"`
+	Name             string   `json:"name"`
+	ApplicationURIs  []string `json:"application_uris"`
+	URIs             []string `json:"uris"`
+	SpaceName        string   `json:"space_name"`
+	OrganizationName string   `json:"organization_name"`
+}
+
+// TagResourceNamePrefix - the prefix of the service tag by which a service instance is matched to the resource
+const TagResourceNamePrefix = "mta-resource-name:"
+const invalidVcapApplicationMsg = "could not parse the VCAP_APPLICATION variable; it was ignored"
 
 // ResolveContext holds context info during resolving of properties
 type ResolveContext struct {
@@ -40,6 +52,7 @@ func (m *MTAResolver) addServiceNames(module *mta.Module) {
 		serviceName := findServiceInvcapServices(vcapServices, resource)
 		if len(serviceName) > 0 {
 			resCtx["service-name"] = serviceName
+			m.traceOrigin(contextLocation(resource.Name, "service-name"), VcapServicesOrigin, "")
 		}
 	}
 }
@@ -62,8 +75,8 @@ func findServiceInvcapServices(vcapServices *vcapServices, resource *mta.Resourc
 	for _, vcapServiceArray := range *vcapServices {
 		for _, vcapService := range vcapServiceArray {
 			for _, tag := range vcapService.Tags {
-				pos := strings.Index(tag, tagResourceNamePrefix)
-				if pos == 0 && tag[len(tagResourceNamePrefix):] == resource.Name {
+				pos := strings.Index(tag, TagResourceNamePrefix)
+				if pos == 0 && tag[len(TagResourceNamePrefix):] == resource.Name {
 					return vcapService.Name
 				}
 			}
@@ -71,3 +84,53 @@ func findServiceInvcapServices(vcapServices *vcapServices, resource *mta.Resourc
 	}
 	return ""
 }
+
+// addApplicationParameters adds the module parameters which the deployer derives from the deployed application,
+// like ${default-url} and ${app-name}, based on the VCAP_APPLICATION variable.
+// Values which were set explicitly for the module are not overwritten.
+func (m *MTAResolver) addApplicationParameters(module *mta.Module) {
+	vcap := m.context.global["VCAP_APPLICATION"]
+	moduleCtx, ok := m.context.modules[module.Name]
+	if len(vcap) == 0 || !ok {
+		return
+	}
+	var app vcapApplication
+	if err := json.Unmarshal([]byte(vcap), &app); err != nil {
+		m.addMessage(invalidVcapApplicationMsg)
+		return
+	}
+
+	params := map[string]string{}
+	appName := app.ApplicationName
+	if len(appName) == 0 {
+		appName = app.Name
+	}
+	params["app-name"] = appName
+	params["org"] = app.OrganizationName
+	params["space"] = app.SpaceName
+
+	uris := app.ApplicationURIs
+	if len(uris) == 0 {
+		uris = app.URIs
+	}
+	if len(uris) > 0 {
+		uri := uris[0]
+		params["default-uri"] = uri
+		params["default-url"] = "https://" + uri
+		host, domain := uri, ""
+		if pos := strings.Index(uri, "."); pos > 0 {
+			host, domain = uri[:pos], uri[pos+1:]
+		}
+		params["default-host"] = host
+		params["host"] = host
+		params["default-domain"] = domain
+		params["domain"] = domain
+	}
+
+	for key, value := range params {
+		if _, ok := moduleCtx[key]; !ok && len(value) > 0 {
+			moduleCtx[key] = value
+			m.traceOrigin(contextLocation(module.Name, key), VcapApplicationOrigin, "")
+		}
+	}
+}
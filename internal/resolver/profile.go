@@ -0,0 +1,184 @@
+package resolver
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/SAP/cloud-mta/internal/fs"
+	"github.com/SAP/cloud-mta/mta"
+)
+
+// The kinds of generated placeholder values
+const (
+	GeneratedPassword = "password"
+	GeneratedUser     = "user"
+	GeneratedUUID     = "uuid"
+)
+
+const (
+	profileUnmarshalFailsMsg  = `the "%s" platform profile is not valid`
+	unknownGeneratedKindMsg   = `the "%s" kind of the "%s" generated value in the "%s" platform profile is not supported; use one of: password, user, uuid`
+	deployerPlaceholderMsg    = `Missing %s; the value is provided by the deployer, define it in the platform profile`
+	invalidGeneratedValuesMsg = `could not parse the "%s" generated values file; new values are generated`
+	saveGeneratedValuesMsg    = `could not save the generated values to the "%s" file`
+
+	// The generated values file, relative to the workspace folder
+	generatedValuesFileName = ".mta/generated-values.json"
+
+	passwordChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_-"
+	userChars     = "abcdefghijklmnopqrstuvwxyz0123456789"
+	passwordLen   = 32
+	userLen       = 16
+)
+
+// deployerPlaceholders - the placeholders which are provided by the deployer during deployment
+var deployerPlaceholders = map[string]bool{
+	"org":                true,
+	"space":              true,
+	"user":               true,
+	"default-domain":     true,
+	"default-host":       true,
+	"default-uri":        true,
+	"default-url":        true,
+	"default-app-name":   true,
+	"app-name":           true,
+	"host":               true,
+	"domain":             true,
+	"protocol":           true,
+	"xs-type":            true,
+	"xs-api-url":         true,
+	"xs-auth-url":        true,
+	"controller-url":     true,
+	"authorization-url":  true,
+	"generated-password": true,
+	"generated-user":     true,
+}
+
+// Profile - the platform profile, which defines the values of placeholders that are provided by the deployer,
+// such as ${org}, ${space} and ${default-domain}, for resolving the MTA locally.
+type Profile struct {
+	// The values of the placeholders, by placeholder name
+	Parameters map[string]string `yaml:"parameters,omitempty"`
+	// The placeholders with generated values and the kind of the generated value (password, user or uuid).
+	// The values are generated once for each module and saved in the workspace folder.
+	Generated map[string]string `yaml:"generated,omitempty"`
+}
+
+// ReadProfile reads the platform profile file. No profile is returned when the path is empty.
+func ReadProfile(path string) (*Profile, error) {
+	if len(path) == 0 {
+		return nil, nil
+	}
+	content, err := fs.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	profile := &Profile{}
+	err = yaml.Unmarshal(content, profile)
+	if err != nil {
+		return nil, errors.Wrapf(err, profileUnmarshalFailsMsg, path)
+	}
+	for name, kind := range profile.Generated {
+		if kind != GeneratedPassword && kind != GeneratedUser && kind != GeneratedUUID {
+			return nil, errors.Errorf(unknownGeneratedKindMsg, kind, name, path)
+		}
+	}
+	return profile, nil
+}
+
+// getProfileValue returns the value of the placeholder from the platform profile
+func (m *MTAResolver) getProfileValue(sourceModule *mta.Module, paramName string) (string, bool) {
+	profile := m.options.Profile
+	if profile == nil {
+		return "", false
+	}
+	if value, ok := profile.Parameters[paramName]; ok {
+		return value, true
+	}
+	if kind, ok := profile.Generated[paramName]; ok {
+		key := paramName
+		if sourceModule != nil {
+			key = sourceModule.Name + "/" + paramName
+		}
+		return m.getGeneratedValue(key, kind), true
+	}
+	return "", false
+}
+
+// getGeneratedValue returns the generated value of the placeholder. The generated values are saved in the workspace,
+// so the same value is used each time the module is resolved.
+func (m *MTAResolver) getGeneratedValue(key string, kind string) string {
+	path := filepath.Join(m.WorkingDir, generatedValuesFileName)
+	if m.generatedValues == nil {
+		m.generatedValues = map[string]string{}
+		content, err := ioutil.ReadFile(path)
+		if err == nil && json.Unmarshal(content, &m.generatedValues) != nil {
+			m.addMessage(fmt.Sprintf(invalidGeneratedValuesMsg, path))
+		}
+	}
+	if value, ok := m.generatedValues[key]; ok {
+		return value
+	}
+
+	value, err := generateValue(kind)
+	if err != nil {
+		m.addMessage(err.Error())
+		return ""
+	}
+	m.generatedValues[key] = value
+	content, err := json.MarshalIndent(m.generatedValues, "", "  ")
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(path), os.ModePerm)
+	}
+	if err == nil {
+		err = ioutil.WriteFile(path, content, 0600)
+	}
+	if err != nil {
+		m.addMessage(fmt.Sprintf(saveGeneratedValuesMsg, path))
+	}
+	return value
+}
+
+// generateValue returns a random value of the kind
+func generateValue(kind string) (string, error) {
+	switch kind {
+	case GeneratedUser:
+		// User names start with a letter
+		first, err := randomString(userChars[:26], 1)
+		if err != nil {
+			return "", err
+		}
+		rest, err := randomString(userChars, userLen-1)
+		return first + rest, err
+	case GeneratedUUID:
+		uuid := make([]byte, 16)
+		if _, err := rand.Read(uuid); err != nil {
+			return "", err
+		}
+		// Set the version (4) and variant bits of a random UUID
+		uuid[6] = (uuid[6] & 0x0f) | 0x40
+		uuid[8] = (uuid[8] & 0x3f) | 0x80
+		return fmt.Sprintf("%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:16]), nil
+	}
+	return randomString(passwordChars, passwordLen)
+}
+
+func randomString(chars string, length int) (string, error) {
+	result := make([]byte, length)
+	for i := range result {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(chars))))
+		if err != nil {
+			return "", err
+		}
+		result[i] = chars[n.Int64()]
+	}
+	return string(result), nil
+}
@@ -0,0 +1,67 @@
+package resolver
+
+import (
+	"fmt"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Profile", func() {
+	yamlPath := getTestPath("profile-project", "mta.yaml")
+
+	BeforeEach(func() {
+		envGetter = func() []string { return nil }
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(getTestPath("profile-project", ".mta"))
+	})
+
+	It("resolves the placeholders provided by the deployer from the profile", func() {
+		profile, err := ReadProfile(getTestPath("profile.yaml"))
+		Ω(err).Should(Succeed())
+		result, _, err := Resolve("", "srv", yamlPath, nil, "", &ResolveOptions{Profile: profile})
+		Ω(err).Should(Succeed())
+		Ω(result.Properties["URL"]).Should(Equal("https://acme-dev-srv.cfapps.example.com"))
+		Ω(result.Properties["PASSWORD"]).Should(MatchRegexp(`^[a-zA-Z0-9_-]{32}$`))
+		Ω(result.Properties["USER"]).Should(MatchRegexp(`^[a-z][a-z0-9]{15}$`))
+		Ω(result.Properties["ID"]).Should(MatchRegexp(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`))
+		Ω(result.Messages).Should(ConsistOf(fmt.Sprintf(deployerPlaceholderMsg, "default-host"), "Missing other"))
+
+		// The generated values are stable in the workspace and different for each module
+		again, _, err := Resolve("", "srv", yamlPath, nil, "", &ResolveOptions{Profile: profile})
+		Ω(err).Should(Succeed())
+		Ω(again.Properties["PASSWORD"]).Should(Equal(result.Properties["PASSWORD"]))
+		Ω(again.Properties["ID"]).Should(Equal(result.Properties["ID"]))
+		db, _, err := Resolve("", "db", yamlPath, nil, "", &ResolveOptions{Profile: profile})
+		Ω(err).Should(Succeed())
+		Ω(db.Properties["PASSWORD"]).ShouldNot(Equal(result.Properties["PASSWORD"]))
+	})
+
+	It("reports the placeholders provided by the deployer when there is no profile", func() {
+		result, _, err := Resolve("", "srv", yamlPath, nil, "", nil)
+		Ω(err).Should(Succeed())
+		Ω(result.Messages).Should(ContainElement(fmt.Sprintf(deployerPlaceholderMsg, "org")))
+		Ω(result.Messages).Should(ContainElement("Missing instance-id"))
+		Ω(getTestPath("profile-project", ".mta")).ShouldNot(BeADirectory())
+	})
+
+	It("returns nil when the path is empty", func() {
+		profile, err := ReadProfile("")
+		Ω(err).Should(Succeed())
+		Ω(profile).Should(BeNil())
+	})
+
+	It("returns error when the kind of a generated value is not supported", func() {
+		path := getTestPath("invalidProfile.yaml")
+		_, err := ReadProfile(path)
+		Ω(err).Should(MatchError(fmt.Sprintf(unknownGeneratedKindMsg, "secret", "generated-password", path)))
+	})
+
+	It("returns error when the profile does not exist", func() {
+		_, err := ReadProfile(getTestPath("notExisting.yaml"))
+		Ω(err).Should(HaveOccurred())
+	})
+})
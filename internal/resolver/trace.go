@@ -0,0 +1,323 @@
+package resolver
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/SAP/cloud-mta/internal/fs"
+)
+
+// The scopes in which the values of properties, placeholders and variables are found.
+// Placeholders are looked up in the scopes in this order.
+const (
+	SourceContextScope      = "source context"
+	SourceParametersScope   = "source parameters"
+	RequiresParametersScope = "requires parameters"
+	ModuleParametersScope   = "module parameters"
+	ModuleContextScope      = "module context"
+	MTAParametersScope      = "MTA parameters"
+	GlobalScope             = "global"
+	ProfileScope            = "profile"
+	ProvidedPropertiesScope = "provided properties"
+	// The properties of a public provides section of the MTA, which matches the provider ID of a configuration resource
+	ConfigurationScope      = "configuration"
+	ModulePropertiesScope   = "module properties"
+	RequiresPropertiesScope = "requires properties"
+)
+
+// The origins of values which are not defined in the MTA descriptors
+const (
+	EnvironmentOrigin     = "environment"
+	DefaultEnvOrigin      = "default-env.json"
+	EnvFileOrigin         = "env file"
+	VcapServicesOrigin    = "VCAP_SERVICES"
+	VcapApplicationOrigin = "VCAP_APPLICATION"
+	// The values which were set in the resolve options
+	OptionsOrigin = "options"
+)
+
+// ValueSource - where a value was found
+type ValueSource struct {
+	Scope string `json:"scope"`
+	// The origin of values from outside the MTA descriptors, e.g. environment variables or VCAP_SERVICES
+	Origin string `json:"origin,omitempty"`
+	// The file which defines the value; for values from the MTA descriptors the line is also set
+	File string `json:"file,omitempty"`
+	Line int    `json:"line,omitempty"`
+}
+
+// LookupStep - a scope in which a placeholder or variable was looked up
+type LookupStep struct {
+	Scope string `json:"scope"`
+	Found bool   `json:"found"`
+}
+
+// ReferenceTrace - how a placeholder or variable was resolved
+type ReferenceTrace struct {
+	// The placeholder or variable, e.g. ${default-url} or ~{url}
+	Reference string       `json:"reference"`
+	Steps     []LookupStep `json:"steps"`
+	// The source of the value; it is empty when the value was not found
+	Source *ValueSource `json:"source,omitempty"`
+	// The placeholders in the value
+	References []*ReferenceTrace `json:"references,omitempty"`
+}
+
+// PropertyTrace - how a property of the module was resolved
+type PropertyTrace struct {
+	Name string `json:"name"`
+	// The name of the required module or resource; it is empty for the module's own properties
+	Requires   string            `json:"requires,omitempty"`
+	Value      any               `json:"value"`
+	Source     ValueSource       `json:"source"`
+	References []*ReferenceTrace `json:"references,omitempty"`
+}
+
+// resolutionTrace collects the property traces while the module is resolved
+type resolutionTrace struct {
+	// The MTA descriptors, for finding the lines of the values; the extensions are first, in reverse order
+	descriptors []descriptorFile
+	properties  []*PropertyTrace
+	property    *PropertyTrace
+	// The placeholders and variables which are being resolved
+	references []*ReferenceTrace
+	// The sources of the values in the resolve context, by location
+	origins map[string]ValueSource
+}
+
+type descriptorFile struct {
+	path string
+	node *yaml.Node
+}
+
+// readDescriptors parses the MTA descriptors again, to find the lines and the order of the values; the extensions are
+// first, in reverse order
+func readDescriptors(path string, extensions []string) []descriptorFile {
+	var descriptors []descriptorFile
+	for _, file := range append([]string{path}, extensions...) {
+		content, err := fs.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		node := &yaml.Node{}
+		if yaml.Unmarshal(content, node) == nil && len(node.Content) > 0 {
+			descriptors = append([]descriptorFile{{path: file, node: node.Content[0]}}, descriptors...)
+		}
+	}
+	return descriptors
+}
+
+// enableTrace starts collecting the property traces, with the lines of the values in the descriptors
+func (m *MTAResolver) enableTrace(descriptors []descriptorFile) {
+	m.trace = &resolutionTrace{origins: map[string]ValueSource{}, descriptors: descriptors}
+}
+
+// Trace returns the property traces, sorted by the requires and property names; the module's own properties are first
+func (m *MTAResolver) Trace() []*PropertyTrace {
+	if m.trace == nil {
+		return nil
+	}
+	properties := m.trace.properties
+	sort.SliceStable(properties, func(i, j int) bool {
+		if properties[i].Requires != properties[j].Requires {
+			return properties[i].Requires < properties[j].Requires
+		}
+		return properties[i].Name < properties[j].Name
+	})
+	return properties
+}
+
+func (m *MTAResolver) startPropertyTrace(name string, requires string, path ...string) {
+	if m.trace == nil {
+		return
+	}
+	scope := ModulePropertiesScope
+	if len(requires) > 0 {
+		scope = RequiresPropertiesScope
+	}
+	m.trace.property = &PropertyTrace{Name: name, Requires: requires, Source: m.descriptorSource(scope, path...)}
+	m.trace.properties = append(m.trace.properties, m.trace.property)
+}
+
+func (m *MTAResolver) endPropertyTrace(value any) {
+	if m.trace == nil || m.trace.property == nil {
+		return
+	}
+	m.trace.property.Value = value
+	m.trace.property = nil
+}
+
+// startReferenceTrace adds the trace of a placeholder or variable to the trace of the value which contains it
+func (m *MTAResolver) startReferenceTrace(reference string) {
+	if m.trace == nil || m.trace.property == nil {
+		return
+	}
+	trace := &ReferenceTrace{Reference: reference, Steps: []LookupStep{}}
+	if count := len(m.trace.references); count > 0 {
+		parent := m.trace.references[count-1]
+		parent.References = append(parent.References, trace)
+	} else {
+		m.trace.property.References = append(m.trace.property.References, trace)
+	}
+	m.trace.references = append(m.trace.references, trace)
+}
+
+func (m *MTAResolver) endReferenceTrace() {
+	if m.currentReference() != nil {
+		m.trace.references = m.trace.references[:len(m.trace.references)-1]
+	}
+}
+
+func (m *MTAResolver) currentReference() *ReferenceTrace {
+	if m.trace == nil || m.trace.property == nil || len(m.trace.references) == 0 {
+		return nil
+	}
+	return m.trace.references[len(m.trace.references)-1]
+}
+
+func (m *MTAResolver) traceStep(scope string, found bool) {
+	if reference := m.currentReference(); reference != nil {
+		reference.Steps = append(reference.Steps, LookupStep{Scope: scope, Found: found})
+	}
+}
+
+func (m *MTAResolver) traceSource(source ValueSource) {
+	if reference := m.currentReference(); reference != nil {
+		reference.Source = &source
+	}
+}
+
+// traceOrigin saves the origin of a value in the resolve context
+func (m *MTAResolver) traceOrigin(location string, origin string, file string) {
+	if m.trace != nil {
+		m.trace.origins[location] = ValueSource{Origin: origin, File: file}
+	}
+}
+
+// contextSource returns the source of a value in the resolve context
+func (m *MTAResolver) contextSource(scope string, location string) ValueSource {
+	var source ValueSource
+	if m.trace != nil {
+		source = m.trace.origins[location]
+	}
+	source.Scope = scope
+	return source
+}
+
+// descriptorSource returns the source of a value in the MTA descriptors. The path contains the names of the fields
+// and of the modules, resources, hooks, requires and provides sections, e.g. modules/srv/parameters/memory.
+// The value is taken from the last extension which defines it.
+func (m *MTAResolver) descriptorSource(scope string, path ...string) ValueSource {
+	source := ValueSource{Scope: scope}
+	if m.trace == nil {
+		return source
+	}
+	for _, descriptor := range m.trace.descriptors {
+		if node := findDescriptorNode(descriptor.node, path); node != nil {
+			source.File = descriptor.path
+			source.Line = node.Line
+			return source
+		}
+	}
+	return source
+}
+
+// findDescriptorNode returns the key node of the path's last field
+func findDescriptorNode(node *yaml.Node, path []string) *yaml.Node {
+	var keyNode *yaml.Node
+	for i := 0; i < len(path); i++ {
+		keyNode, node = findMappingField(node, path[i])
+		if node == nil {
+			return nil
+		}
+		if isNamedList(path[i]) && i+1 < len(path) {
+			i++
+			keyNode, node = findNamedItem(node, path[i])
+			if node == nil {
+				return nil
+			}
+		}
+	}
+	return keyNode
+}
+
+func isNamedList(field string) bool {
+	return field == "modules" || field == "resources" || field == "requires" || field == "provides" || field == "hooks"
+}
+
+func findMappingField(node *yaml.Node, name string) (*yaml.Node, *yaml.Node) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == name {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// findNamedItem returns the name node and the item node of the list item with the name
+func findNamedItem(node *yaml.Node, name string) (*yaml.Node, *yaml.Node) {
+	if node.Kind != yaml.SequenceNode {
+		return nil, nil
+	}
+	for _, item := range node.Content {
+		if _, nameNode := findMappingField(item, "name"); nameNode != nil && nameNode.Value == name {
+			return nameNode, item
+		}
+	}
+	return nil, nil
+}
+
+// FormatTrace returns the property traces as text: each property with its value and source, followed by the
+// placeholders and variables in it, the scopes in which they were looked up and where they were found
+func FormatTrace(traces []*PropertyTrace) string {
+	var buf bytes.Buffer
+	for _, trace := range traces {
+		value, _ := convertToString(trace.Value)
+		buf.WriteString(trace.Name + "=" + value + " [" + describeSource(trace.Source) + "]\n")
+		writeReferenceTraces(&buf, trace.References, 1)
+	}
+	return buf.String()
+}
+
+func writeReferenceTraces(buf *bytes.Buffer, references []*ReferenceTrace, level int) {
+	for _, reference := range references {
+		buf.WriteString(strings.Repeat("  ", level) + reference.Reference)
+		if reference.Source != nil {
+			buf.WriteString(" found in " + describeSource(*reference.Source))
+		} else {
+			buf.WriteString(" not found")
+		}
+		scopes := make([]string, 0, len(reference.Steps))
+		for _, step := range reference.Steps {
+			scopes = append(scopes, step.Scope)
+		}
+		if len(scopes) > 0 {
+			buf.WriteString("; looked up in: " + strings.Join(scopes, ", "))
+		}
+		buf.WriteString("\n")
+		writeReferenceTraces(buf, reference.References, level+1)
+	}
+}
+
+func describeSource(source ValueSource) string {
+	var details []string
+	if len(source.Origin) > 0 {
+		details = append(details, source.Origin)
+	}
+	if len(source.File) > 0 && source.Line > 0 {
+		details = append(details, fmt.Sprintf("%s:%d", source.File, source.Line))
+	} else if len(source.File) > 0 {
+		details = append(details, source.File)
+	}
+	if len(details) == 0 {
+		return source.Scope
+	}
+	return source.Scope + " (" + strings.Join(details, " ") + ")"
+}
@@ -0,0 +1,97 @@
+package resolver
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Trace", func() {
+	yamlPath := getTestPath("trace-project", "mta.yaml")
+	extPath := getTestPath("trace-project", "prod.mtaext")
+
+	BeforeEach(func() {
+		envGetter = func() []string { return nil }
+	})
+
+	resolveTrace := func() map[string]*PropertyTrace {
+		result, _, err := Resolve("", "srv", yamlPath, []string{extPath}, "", &ResolveOptions{Trace: true})
+		Ω(err).Should(Succeed())
+		traces := map[string]*PropertyTrace{}
+		for _, trace := range result.Trace {
+			traces[trace.Name] = trace
+		}
+		return traces
+	}
+
+	It("returns the lookup steps and the source of each placeholder", func() {
+		traces := resolveTrace()
+		url := traces["URL"]
+		Ω(url.Value).Should(Equal("https://srv-us10.example.com"))
+		Ω(url.Source).Should(Equal(ValueSource{Scope: ModulePropertiesScope, File: yamlPath, Line: 15}))
+		Ω(url.References).Should(HaveLen(2))
+
+		host := url.References[0]
+		Ω(host.Reference).Should(Equal("${host}"))
+		Ω(host.Steps).Should(Equal([]LookupStep{{Scope: ModuleParametersScope, Found: true}}))
+		Ω(*host.Source).Should(Equal(ValueSource{Scope: ModuleParametersScope, File: yamlPath, Line: 13}))
+		Ω(host.References).Should(HaveLen(1))
+
+		region := host.References[0]
+		Ω(region.Steps).Should(Equal([]LookupStep{
+			{Scope: ModuleParametersScope, Found: false},
+			{Scope: ModuleContextScope, Found: false},
+			{Scope: MTAParametersScope, Found: true},
+		}))
+		Ω(*region.Source).Should(Equal(ValueSource{Scope: MTAParametersScope, File: extPath, Line: 6}))
+
+		domain := url.References[1]
+		Ω(domain.Reference).Should(Equal("${domain}"))
+		Ω(*domain.Source).Should(Equal(ValueSource{Scope: GlobalScope, Origin: EnvFileOrigin, File: getTestPath("trace-project", "srv", ".env")}))
+	})
+
+	It("returns the lookup steps of placeholders which are not found", func() {
+		level := resolveTrace()["LEVEL"]
+		Ω(level.Value).Should(Equal("${log-level}"))
+		Ω(level.References[0].Source).Should(BeNil())
+		Ω(level.References[0].Steps).Should(HaveLen(5))
+		Ω(level.References[0].Steps[4]).Should(Equal(LookupStep{Scope: ProfileScope, Found: false}))
+	})
+
+	It("returns the source of variables and of the placeholders in the provided properties", func() {
+		dbName := resolveTrace()["DB_NAME"]
+		Ω(dbName.Requires).Should(Equal("db"))
+		Ω(dbName.Value).Should(Equal("my-db"))
+		variable := dbName.References[0]
+		Ω(variable.Reference).Should(Equal("~{db-name}"))
+		Ω(*variable.Source).Should(Equal(ValueSource{Scope: ProvidedPropertiesScope, File: yamlPath, Line: 28}))
+		Ω(variable.References[0].Steps).Should(Equal([]LookupStep{
+			{Scope: SourceContextScope, Found: false},
+			{Scope: SourceParametersScope, Found: true},
+		}))
+		Ω(*variable.References[0].Source).Should(Equal(ValueSource{Scope: SourceParametersScope, File: yamlPath, Line: 26}))
+	})
+
+	It("returns the origin of values from the environment", func() {
+		envGetter = func() []string { return []string{"srv/log-level=debug"} }
+		level := resolveTrace()["LEVEL"]
+		Ω(level.Value).Should(Equal("debug"))
+		Ω(*level.References[0].Source).Should(Equal(ValueSource{Scope: ModuleContextScope, Origin: EnvironmentOrigin}))
+	})
+
+	It("does not return the trace by default", func() {
+		result, _, err := Resolve("", "srv", yamlPath, nil, "", nil)
+		Ω(err).Should(Succeed())
+		Ω(result.Trace).Should(BeNil())
+	})
+
+	It("formats the trace as text", func() {
+		result, _, err := Resolve("", "srv", yamlPath, []string{extPath}, "", &ResolveOptions{Trace: true})
+		Ω(err).Should(Succeed())
+		lines := strings.Split(FormatTrace(result.Trace), "\n")
+		Ω(lines).Should(ContainElement("LEVEL=${log-level} [module properties (" + yamlPath + ":16)]"))
+		Ω(lines).Should(ContainElement("  ${host} found in module parameters (" + yamlPath + ":13); looked up in: module parameters"))
+		Ω(lines).Should(ContainElement("  ${log-level} not found; looked up in: module parameters, module context, MTA parameters, global, profile"))
+	})
+})
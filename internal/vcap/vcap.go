@@ -0,0 +1,259 @@
+package vcap
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/SAP/cloud-mta/internal/export"
+	"github.com/SAP/cloud-mta/internal/resolver"
+	"github.com/SAP/cloud-mta/mta"
+)
+
+const (
+	missingOfferingMsg        = `the "%s" resource does not define the service offering; the "%s" label is used`
+	placeholderCredentialsMsg = `the credentials of the "%s" resource are placeholders; set them in the secrets file`
+	readSecretsFailMsg        = `could not read the "%s" secrets file`
+	invalidSecretsMsg         = `the secrets of the "%s" resource are not valid; they must be an object with the credentials`
+
+	// ServicesVariable - the name of the environment variable which describes the bound service instances
+	ServicesVariable = "VCAP_SERVICES"
+
+	userProvidedServiceType = "org.cloudfoundry.user-provided-service"
+	userProvidedLabel       = "user-provided"
+)
+
+// Service - a service instance binding in the VCAP_SERVICES variable
+type Service struct {
+	Name         string                 `json:"name"`
+	InstanceName string                 `json:"instance_name"`
+	Label        string                 `json:"label"`
+	Plan         string                 `json:"plan"`
+	Tags         []string               `json:"tags"`
+	Credentials  map[string]interface{} `json:"credentials"`
+}
+
+// Services - the VCAP_SERVICES variable: the service instance bindings by service offering (label)
+type Services map[string][]*Service
+
+// offering - the service offering and plan which the deployer uses for a resource type
+type offering struct {
+	label string
+	plan  string
+}
+
+// resourceOfferings - the service offerings of the resource types which imply them
+var resourceOfferings = map[string]offering{
+	"com.sap.xs.hdi-container":    {"hana", "hdi-shared"},
+	"com.sap.xs.hana-schema":      {"hana", "schema"},
+	"com.sap.xs.hana-securestore": {"hana", "securestore"},
+	"com.sap.xs.uaa":              {"xsuaa", "default"},
+	"com.sap.xs.uaa-space":        {"xsuaa", "space"},
+	"com.sap.xs.uaa-devuser":      {"xsuaa", "devuser"},
+	"com.sap.xs.uaa-application":  {"xsuaa", "application"},
+	"com.sap.xs.job-scheduler":    {"jobscheduler", "default"},
+	"com.sap.xs.auditlog":         {"auditlog", "free"},
+}
+
+// labelCredentials - the credential names of the well-known service offerings
+var labelCredentials = map[string][]string{
+	"hana":         {"host", "port", "url", "schema", "user", "password", "hdi_user", "hdi_password", "certificate"},
+	"xsuaa":        {"url", "clientid", "clientsecret", "xsappname", "uaadomain", "identityzone", "tenantid"},
+	"jobscheduler": {"url", "user", "password"},
+	"auditlog":     {"url", "user", "password"},
+	"postgresql":   {"hostname", "port", "dbname", "username", "password", "uri"},
+	"redis-cache":  {"hostname", "port", "password", "uri"},
+	"destination":  {"uri", "clientid", "clientsecret", "url"},
+	"connectivity": {"onpremise_proxy_host", "onpremise_proxy_port", "clientid", "clientsecret", "token_service_url"},
+}
+
+// defaultCredentials - the credential names of the other service offerings
+var defaultCredentials = []string{"url", "user", "password"}
+
+// Generate returns a VCAP_SERVICES skeleton with the services of the resources required by the modules, after merging
+// the extensions and resolving the resources' parameters. When no modules are sent, the resources required by all
+// the modules are used. Each service has the "mta-resource-name:" tag, by which the resolver matches services to
+// resources, and placeholder credentials; the credentials are taken from the secrets file by resource name, if they
+// are defined there. The options of the resolution can be nil. The returned messages describe the values which must
+// be completed.
+func Generate(workspaceDir, path string, extensions []string, moduleNames []string, secretsPath string, options *resolver.ResolveOptions) (Services, []string, error) {
+	mtaObj, messages, err := mta.GetMtaFromFile(path, extensions, false)
+	if err != nil {
+		return nil, messages, err
+	}
+	if len(workspaceDir) == 0 {
+		workspaceDir = filepath.Dir(path)
+	}
+	secrets, err := readSecrets(secretsPath)
+	if err != nil {
+		return nil, messages, err
+	}
+	resources, err := getRequiredServices(mtaObj, moduleNames)
+	if err != nil {
+		return nil, messages, err
+	}
+
+	m := resolver.NewMTAResolver(mtaObj, workspaceDir)
+	m.SetOptions(options)
+	services := Services{}
+	for _, resource := range resources {
+		m.ResolveResource(resource)
+		service, serviceMessages, err := resourceToService(resource, secrets[resource.Name])
+		messages = append(messages, serviceMessages...)
+		if err != nil {
+			return nil, messages, err
+		}
+		services[service.Label] = append(services[service.Label], service)
+	}
+	messages = append(messages, m.Messages()...)
+	return services, messages, nil
+}
+
+// getRequiredServices returns the service resources required by the modules and their hooks, in the order of the
+// resources in the MTA
+func getRequiredServices(mtaObj *mta.MTA, moduleNames []string) ([]*mta.Resource, error) {
+	modules, err := export.GetModules(mtaObj, moduleNames)
+	if err != nil {
+		return nil, err
+	}
+	required := map[string]bool{}
+	for _, module := range modules {
+		for _, requires := range module.Requires {
+			required[requires.Name] = true
+		}
+		for _, hook := range module.Hooks {
+			for _, requires := range hook.Requires {
+				required[requires.Name] = true
+			}
+		}
+	}
+	var resources []*mta.Resource
+	for _, resource := range mtaObj.Resources {
+		if required[resource.Name] && export.IsServiceResource(resource) {
+			resources = append(resources, resource)
+		}
+	}
+	return resources, nil
+}
+
+// resourceToService returns the service binding of the resource, with the credentials from the secrets file, or
+// placeholders for the credentials which are not defined there
+func resourceToService(resource *mta.Resource, secrets interface{}) (*Service, []string, error) {
+	var messages []string
+	serviceName := export.GetStringParameter(resource.Parameters, "service-name")
+	if len(serviceName) == 0 {
+		serviceName = resource.Name
+	}
+	service := &Service{
+		Name:         serviceName,
+		InstanceName: serviceName,
+		Label:        export.GetStringParameter(resource.Parameters, "service"),
+		Plan:         export.GetStringParameter(resource.Parameters, "service-plan"),
+		Tags:         getTags(resource),
+		Credentials:  map[string]interface{}{},
+	}
+
+	credentialNames := defaultCredentials
+	if resource.Type == userProvidedServiceType {
+		service.Label = userProvidedLabel
+		credentialNames = nil
+		for key, value := range export.ToStringMap(resource.Parameters["config"]) {
+			service.Credentials[key] = toJSONSafe(value)
+		}
+	} else if offering, ok := resourceOfferings[resource.Type]; ok && len(service.Label) == 0 {
+		service.Label = offering.label
+		if len(service.Plan) == 0 {
+			service.Plan = offering.plan
+		}
+	}
+	if len(service.Label) == 0 {
+		service.Label = userProvidedLabel
+		messages = append(messages, fmt.Sprintf(missingOfferingMsg, resource.Name, service.Label))
+	}
+	if names, ok := labelCredentials[service.Label]; ok {
+		credentialNames = names
+	}
+
+	if secrets != nil {
+		secretsMap := export.ToStringMap(secrets)
+		if secretsMap == nil {
+			return nil, messages, errors.Errorf(invalidSecretsMsg, resource.Name)
+		}
+		for key, value := range secretsMap {
+			service.Credentials[key] = toJSONSafe(value)
+		}
+	}
+	placeholders := false
+	for _, name := range credentialNames {
+		if _, ok := service.Credentials[name]; !ok {
+			service.Credentials[name] = "<" + name + ">"
+			placeholders = true
+		}
+	}
+	if placeholders {
+		messages = append(messages, fmt.Sprintf(placeholderCredentialsMsg, resource.Name))
+	}
+	return service, messages, nil
+}
+
+// getTags returns the service tags of the resource and the tag with the resource name
+func getTags(resource *mta.Resource) []string {
+	tags := []string{}
+	if serviceTags, ok := resource.Parameters["service-tags"].([]interface{}); ok {
+		for _, tag := range serviceTags {
+			tags = append(tags, fmt.Sprint(tag))
+		}
+	}
+	return append(tags, resolver.TagResourceNamePrefix+resource.Name)
+}
+
+// readSecrets reads the secrets file, in YAML or JSON format, which contains the credentials of the services by
+// resource name; the file is optional
+func readSecrets(path string) (map[string]interface{}, error) {
+	if len(path) == 0 {
+		return nil, nil
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, readSecretsFailMsg, path)
+	}
+	secrets := map[string]interface{}{}
+	if err := yaml.Unmarshal(content, &secrets); err != nil {
+		return nil, errors.Wrapf(err, readSecretsFailMsg, path)
+	}
+	return secrets, nil
+}
+
+// Marshal returns the VCAP_SERVICES variable as compact JSON
+func Marshal(services Services) (string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	// URLs in the credentials must not be escaped
+	enc.SetEscapeHTML(false)
+	err := enc.Encode(services)
+	return strings.TrimSpace(buf.String()), err
+}
+
+// toJSONSafe converts the maps in the value to maps with string keys, which can be serialized to JSON
+func toJSONSafe(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}, map[interface{}]interface{}:
+		result := export.ToStringMap(v)
+		for key, val := range result {
+			result[key] = toJSONSafe(val)
+		}
+		return result
+	case []interface{}:
+		for i, val := range v {
+			v[i] = toJSONSafe(val)
+		}
+		return v
+	}
+	return value
+}
@@ -0,0 +1,90 @@
+package vcap
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/SAP/cloud-mta/internal/resolver"
+)
+
+var _ = Describe("Generate", func() {
+	path := getTestPath("mta.yaml")
+	options := &resolver.ResolveOptions{EnvSources: []resolver.EnvSource{resolver.DotenvFile(".env")}}
+
+	It("generates the services of the resources required by the modules and their hooks", func() {
+		services, messages, err := Generate("", path, nil, nil, getTestPath("secrets.yaml"), options)
+		Ω(err).Should(Succeed())
+		Ω(services).Should(HaveLen(4))
+		Ω(services["hana"]).Should(Equal([]*Service{{
+			Name:         "${org}-db",
+			InstanceName: "${org}-db",
+			Label:        "hana",
+			Plan:         "hdi-shared",
+			Tags:         []string{"database", "mta-resource-name:db"},
+			Credentials: map[string]interface{}{
+				"host":         "localhost",
+				"port":         30015,
+				"url":          "jdbc:sap://localhost:30015",
+				"schema":       "DEV",
+				"user":         "dev",
+				"password":     "secret",
+				"hdi_user":     "dev_hdi",
+				"hdi_password": "hdi_secret",
+				"certificate":  "",
+			},
+		}}))
+		Ω(services["xsuaa"]).Should(HaveLen(1))
+		Ω(services["xsuaa"][0].Plan).Should(Equal("application"))
+		Ω(services["xsuaa"][0].Credentials).Should(HaveKeyWithValue("clientsecret", "<clientsecret>"))
+		Ω(services["enterprise-messaging"][0].Credentials).Should(Equal(map[string]interface{}{
+			"management": []interface{}{map[string]interface{}{"oa2": map[string]interface{}{"clientid": "messaging-client"}}},
+			"url":        "<url>",
+			"user":       "<user>",
+			"password":   "<password>",
+		}))
+		Ω(services["user-provided"][0].Name).Should(Equal("legacy"))
+		Ω(services["user-provided"][0].Credentials).Should(Equal(map[string]interface{}{"url": "https://legacy.example.com"}))
+		Ω(messages).Should(ConsistOf(
+			`the credentials of the "uaa" resource are placeholders; set them in the secrets file`,
+			`the credentials of the "messaging" resource are placeholders; set them in the secrets file`,
+			"Missing org; the value is provided by the deployer, define it in the platform profile",
+			"Missing db/org; the value is provided by the deployer, define it in the platform profile",
+		))
+	})
+
+	It("generates the services of the selected modules", func() {
+		services, _, err := Generate("", path, nil, []string{"ui"}, "", options)
+		Ω(err).Should(Succeed())
+		Ω(services).Should(HaveLen(2))
+		Ω(services).Should(HaveKey("xsuaa"))
+		Ω(services).Should(HaveKey("user-provided"))
+	})
+
+	It("generates a service which the resolver matches to the resource", func() {
+		services, _, err := Generate("", path, nil, []string{"srv"}, "", options)
+		Ω(err).Should(Succeed())
+		services["hana"][0].Name = "dev-db"
+		vcapServices, err := Marshal(services)
+		Ω(err).Should(Succeed())
+		resolveOptions := *options
+		resolveOptions.VcapServices = vcapServices
+		result, _, err := resolver.Resolve("", "srv", path, nil, "", &resolveOptions)
+		Ω(err).Should(Succeed())
+		Ω(result.Properties["DB_SERVICE"]).Should(Equal("dev-db"))
+	})
+
+	It("returns error when the module does not exist", func() {
+		_, _, err := Generate("", path, nil, []string{"unknown"}, "", options)
+		Ω(err).Should(MatchError(`could not find the "unknown" module`))
+	})
+
+	It("returns error when the secrets file does not exist", func() {
+		_, _, err := Generate("", path, nil, nil, getTestPath("unknown.yaml"), options)
+		Ω(err).Should(HaveOccurred())
+	})
+
+	It("returns error when the secrets of a resource are not valid", func() {
+		_, _, err := Generate("", path, nil, nil, getTestPath("invalidSecrets.yaml"), options)
+		Ω(err).Should(MatchError(`the secrets of the "db" resource are not valid; they must be an object with the credentials`))
+	})
+})
@@ -0,0 +1,132 @@
+// Package resolver resolves the properties of the modules in an MTA file: the variables in the form ~{var-name} and
+// the placeholders in the form ${placeholder} are replaced with concrete values, based on the MTA descriptors, the
+// environment variables and the environment files in the modules' folders.
+package resolver
+
+import (
+	"github.com/SAP/cloud-mta/internal/resolver"
+)
+
+// EnvSource - a source of environment variables for the resolution
+type EnvSource = resolver.EnvSource
+
+// EnvSourceContext - the context in which the environment variables are read: the project folder and the module
+type EnvSourceContext = resolver.EnvSourceContext
+
+// EnvValues - the environment variables of a source
+type EnvValues = resolver.EnvValues
+
+// ProcessEnv returns the source of the process environment variables
+func ProcessEnv() EnvSource {
+	return resolver.ProcessEnv()
+}
+
+// EnvMap returns a source of in-memory environment variables
+func EnvMap(values map[string]string) EnvSource {
+	return resolver.EnvMap(values, resolver.OptionsOrigin)
+}
+
+// DotenvFile returns the source of a .env file. A relative path is relative to the module folder. The file is optional.
+func DotenvFile(path string) EnvSource {
+	return resolver.DotenvFile(path)
+}
+
+// JSONFile returns the source of a JSON file in the default-env.json format. A relative path is relative to the
+// module folder. The file is optional.
+func JSONFile(path string) EnvSource {
+	return resolver.JSONFile(path)
+}
+
+// DefaultEnvSources returns the default sources of the environment variables, in order of precedence: the process
+// environment variables, the default-env.json file and the environment files in the module folder, e.g. ".env",
+// ".env.local" and ".env.prod". The default environment file is ".env".
+func DefaultEnvSources(envFiles ...string) []EnvSource {
+	return resolver.DefaultEnvSources(envFiles...)
+}
+
+// DefaultMaxDepth - the default maximum depth of nested values and placeholder references which are resolved
+const DefaultMaxDepth = resolver.DefaultMaxDepth
+
+// Options - the optional settings of the resolution
+type Options struct {
+	// The path to the project folder; the default path is the folder of the mta.yaml file
+	WorkspaceDir string
+	// The paths to the MTA extension descriptors
+	Extensions []string
+	// The environment file path, relative to the module folder; the default file path is ".env"
+	EnvFile string
+	// The sources of the environment variables; the values of the later sources take precedence.
+	// When they are set, the EnvFile option is not used. The default sources are returned by DefaultEnvSources.
+	EnvSources []EnvSource
+	// The VCAP_SERVICES and VCAP_APPLICATION values, as JSON. They take precedence over the environment variables
+	// and the environment files.
+	VcapServices    string
+	VcapApplication string
+	// The path to the platform profile, which defines the values of placeholders provided by the deployer,
+	// like ${org} and ${default-domain}
+	ProfilePath string
+	// Resolve like the deployer: structured values inside strings are rendered as {key=value} instead of JSON,
+	// and non-string parameters are used as whole values
+	DeployerCompatible bool
+	// The maximum depth of nested values and placeholder references; the default is DefaultMaxDepth
+	MaxDepth int
+}
+
+// ModuleResult - the resolved module
+type ModuleResult struct {
+	Name string `json:"name"`
+	// The module path, relative to the project folder
+	Path string `json:"path,omitempty"`
+	// The module's environment variables
+	Properties map[string]string `json:"properties"`
+	// The messages about values that could not be resolved
+	Messages []string `json:"messages"`
+}
+
+// Result - the result of the resolution
+type Result struct {
+	Modules []ModuleResult `json:"modules"`
+	// The messages about the MTA descriptors, e.g. extension descriptors that could not be merged
+	Messages []string `json:"messages"`
+}
+
+// Resolve resolves the modules of the MTA file. When no module names are sent, all the modules are resolved.
+func Resolve(path string, moduleNames []string, options Options) (*Result, error) {
+	profile, err := resolver.ReadProfile(options.ProfilePath)
+	if err != nil {
+		return nil, err
+	}
+	results, messages, err := resolver.ResolveModules(options.WorkspaceDir, moduleNames, path, options.Extensions, options.EnvFile,
+		&resolver.ResolveOptions{
+			Profile:            profile,
+			DeployerCompatible: options.DeployerCompatible,
+			MaxDepth:           options.MaxDepth,
+			EnvSources:         options.EnvSources,
+			VcapServices:       options.VcapServices,
+			VcapApplication:    options.VcapApplication,
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Modules: make([]ModuleResult, 0, len(results)), Messages: messages}
+	for _, module := range results {
+		result.Modules = append(result.Modules, ModuleResult{
+			Name:       module.Name,
+			Path:       module.Path,
+			Properties: module.Properties,
+			Messages:   module.Messages,
+		})
+	}
+	return result, nil
+}
+
+// Module returns the result of the module with the name, or nil if the module was not resolved
+func (r *Result) Module(name string) *ModuleResult {
+	for i := range r.Modules {
+		if r.Modules[i].Name == name {
+			return &r.Modules[i]
+		}
+	}
+	return nil
+}
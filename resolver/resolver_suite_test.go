@@ -0,0 +1,23 @@
+package resolver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/SAP/cloud-mta/internal/logs"
+)
+
+func TestResolver(t *testing.T) {
+	logs.NewLogger()
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Resolver Suite")
+}
+
+func getTestPath(relPath ...string) string {
+	wd, _ := os.Getwd()
+	return filepath.Join(wd, "testdata", filepath.Join(relPath...))
+}
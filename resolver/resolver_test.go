@@ -0,0 +1,63 @@
+package resolver
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Resolve", func() {
+	noEnvironment := []EnvSource{DotenvFile(".env")}
+
+	It("resolves all the modules", func() {
+		result, err := Resolve(getTestPath("mta.yaml"), nil, Options{EnvSources: noEnvironment})
+		Ω(err).Should(Succeed())
+		Ω(result.Modules).Should(HaveLen(2))
+		Ω(result.Module("ui").Properties).Should(Equal(map[string]string{"LEVEL": "${log-level}"}))
+		Ω(result.Module("ui").Messages).Should(Equal([]string{"Missing log-level"}))
+		Ω(result.Module("srv").Path).Should(Equal("srv"))
+		Ω(result.Module("unknown")).Should(BeNil())
+	})
+
+	It("resolves the modules with the environment, the VCAP variables and the profile", func() {
+		result, err := Resolve(getTestPath("mta.yaml"), []string{"srv"}, Options{
+			EnvSources: []EnvSource{
+				EnvMap(map[string]string{"VCAP_APPLICATION": "{}"}),
+				DotenvFile(".env"),
+			},
+			VcapApplication:    `{"application_uris": ["srv.example.com"]}`,
+			VcapServices:       `{"postgresql": [{"name": "my-db", "tags": ["mta-resource-name:db"]}]}`,
+			ProfilePath:        getTestPath("profile.yaml"),
+			DeployerCompatible: true,
+		})
+		Ω(err).Should(Succeed())
+		Ω(result.Modules).Should(HaveLen(1))
+		srv := result.Modules[0]
+		Ω(srv.Properties).Should(Equal(map[string]string{
+			"URL":    "https://srv.example.com",
+			"DB":     "my-db",
+			"CONFIG": "config: {level=debug}",
+			"ORG":    "my-org",
+		}))
+		Ω(srv.Messages).Should(BeEmpty())
+	})
+
+	It("resolves the modules with the environment sources", func() {
+		result, err := Resolve(getTestPath("mta.yaml"), []string{"ui", "srv"}, Options{EnvSources: []EnvSource{
+			DotenvFile(".env"),
+			EnvMap(map[string]string{"ui/log-level": "error"}),
+		}})
+		Ω(err).Should(Succeed())
+		Ω(result.Module("ui").Properties["LEVEL"]).Should(Equal("error"))
+		Ω(result.Module("srv").Properties["CONFIG"]).Should(Equal(`config: {"level":"debug"}`))
+	})
+
+	It("returns error when the profile does not exist", func() {
+		_, err := Resolve(getTestPath("mta.yaml"), nil, Options{ProfilePath: getTestPath("unknown.yaml")})
+		Ω(err).Should(HaveOccurred())
+	})
+
+	It("returns error when the module does not exist", func() {
+		_, err := Resolve(getTestPath("mta.yaml"), []string{"unknown"}, Options{})
+		Ω(err).Should(MatchError(`could not find the "unknown" module`))
+	})
+})
@@ -55,4 +55,31 @@ modules:
 		Ω(issues[2].Line).Should(Equal(24))
 	})
 
+	It("is not run when the builders validation is excluded", func() {
+		mtaContent := []byte(`ID: mta.builders
+_schema-version: '3.2'
+version: 1.0.0
+modules:
+- name: ui
+  type: html5
+  path: ui
+  build-parameters:
+    builder: custom
+    commands: command
+`)
+		errs, _ := validate(mtaContent, getTestPath("buildersConfigProject"), true, false, true, "")
+		Ω(errs).Should(ConsistOf(YamlValidationIssue{
+			Msg:  `the "commands" property is defined incorrectly; the property must be a sequence of strings`,
+			Line: 10, Column: 15, Rule: buildersValidation,
+		}))
+		errs, _ = validate(mtaContent, getTestPath("buildersConfigProject"), true, false, true, buildersValidation)
+		Ω(errs).Should(BeEmpty())
+	})
+
+	It("is not run when the builders rule is disabled in the configuration", func() {
+		mtaPath := getTestPath("buildersConfigProject", "mta.yaml")
+		result, err := ValidateWithOptions(mtaPath, nil, ValidateOptions{})
+		Ω(err).Should(Succeed())
+		Ω(result[mtaPath]).Should(BeEmpty())
+	})
 })
@@ -0,0 +1,253 @@
+package validate
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/SAP/cloud-mta/internal/fs"
+)
+
+// ConfigFileName - the name of the validation configuration file in the project folder
+const ConfigFileName = ".mtalint.yaml"
+
+const (
+	readConfigFailedMsg      = `could not read the "%s" validation configuration file`
+	unknownConfigRuleMsg     = `the "%s" rule in the validation configuration is unknown; expected one of the following: %s`
+	invalidConfigSeverityMsg = `the "%s" severity of the "%s" rule in the validation configuration is incorrect; expected one of the following: error, warning`
+	invalidConfigPathMsg     = `the "%s" module path pattern of the "%s" rule in the validation configuration is incorrect`
+)
+
+// Config - the validation configuration of a project, e.g.
+//
+//	rules:
+//	  paths:
+//	    enabled: false
+//	  deprecatedOpts:
+//	    severity: error
+//	  envVarCollisions:
+//	    module-types: [nodejs, java]
+//	    module-paths: ["srv/*"]
+type Config struct {
+	// Rules - the configuration of the rules by their IDs; rules which are not configured keep their defaults
+	Rules map[string]RuleConfig `yaml:"rules"`
+}
+
+// RuleConfig - the configuration of a validation rule
+type RuleConfig struct {
+	// Enabled - whether the rule reports issues; rules are enabled by default
+	Enabled *bool `yaml:"enabled"`
+	// Severity - overrides the severity of the issues of the rule: "error" or "warning"
+	Severity string `yaml:"severity"`
+	// ModuleTypes - when set, the rule reports issues only in the modules of these types
+	ModuleTypes []string `yaml:"module-types"`
+	// ModulePaths - when set, the rule reports issues only in the modules whose paths match these patterns
+	ModulePaths []string `yaml:"module-paths"`
+}
+
+// LoadConfig reads the validation configuration from the project folder.
+// An empty configuration is returned when the project doesn't have a configuration file.
+func LoadConfig(projectPath string) (*Config, error) {
+	configPath := filepath.Join(projectPath, ConfigFileName)
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		return &Config{}, nil
+	}
+	content, err := fs.ReadFile(configPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, readConfigFailedMsg, configPath)
+	}
+	config, err := parseConfig(content)
+	if err != nil {
+		return nil, errors.Wrapf(err, readConfigFailedMsg, configPath)
+	}
+	return config, nil
+}
+
+// loadValidationConfig reads the validation configuration from the project folder. When the configuration file can't
+// be read or is not valid, the default configuration is returned with the issues of the configuration file.
+func loadValidationConfig(projectPath string) (*Config, YamlValidationIssues) {
+	config, err := LoadConfig(projectPath)
+	if err == nil {
+		return config, nil
+	}
+	msg := fmt.Sprintf(readConfigFailedMsg, filepath.Join(projectPath, ConfigFileName))
+	var issues YamlValidationIssues
+	for _, issue := range convertError(errors.Cause(err)) {
+		issues = appendRuleIssue(issues, configRule, msg+": "+issue.Msg, issue.Line, issue.Column)
+	}
+	return &Config{}, issues
+}
+
+// withConfigIssues adds the issues of the validation configuration file in the project folder to the error of the
+// validation of a descriptor
+func withConfigIssues(projectPath string, err error) error {
+	_, issues := loadValidationConfig(projectPath)
+	if len(issues) == 0 {
+		return err
+	}
+	configErr := errors.Errorf(validationErrorsMsg, filepath.Join(projectPath, ConfigFileName), issues.String())
+	if err == nil {
+		return configErr
+	}
+	return errors.Errorf("%s\n%s", err.Error(), configErr.Error())
+}
+
+func parseConfig(content []byte) (*Config, error) {
+	config := &Config{}
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	dec.KnownFields(true)
+	if err := dec.Decode(config); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	ruleIDs := make(map[string]bool)
+	for _, rule := range Rules() {
+		ruleIDs[rule.ID] = true
+	}
+	for _, id := range config.sortedRuleIDs() {
+		if !ruleIDs[id] {
+			var known []string
+			for _, rule := range Rules() {
+				known = append(known, rule.ID)
+			}
+			return nil, errors.Errorf(unknownConfigRuleMsg, id, strings.Join(known, ", "))
+		}
+		ruleConfig := config.Rules[id]
+		if ruleConfig.Severity != "" && ruleConfig.Severity != SeverityError && ruleConfig.Severity != SeverityWarning {
+			return nil, errors.Errorf(invalidConfigSeverityMsg, ruleConfig.Severity, id)
+		}
+		for _, pattern := range ruleConfig.ModulePaths {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, errors.Errorf(invalidConfigPathMsg, pattern, id)
+			}
+		}
+	}
+	return config, nil
+}
+
+func (config *Config) sortedRuleIDs() []string {
+	ids := make([]string, 0, len(config.Rules))
+	for id := range config.Rules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// excludeDisabled adds the disabled rules to the excluded semantic validations, so they are not run
+func (config *Config) excludeDisabled(exclude string) string {
+	for _, id := range config.sortedRuleIDs() {
+		if enabled := config.Rules[id].Enabled; enabled != nil && !*enabled {
+			exclude += "," + id
+		}
+	}
+	return exclude
+}
+
+// apply removes the issues of the disabled rules and of the rules which are not in the scope of the issue's module,
+// and moves the issues of the rules whose severity is overridden. The root is the root of an MTA extension descriptor
+// when extension is true.
+func (config *Config) apply(root *yaml.Node, extension bool, errIssues, warnIssues YamlValidationIssues) (YamlValidationIssues, YamlValidationIssues) {
+	if len(config.Rules) == 0 {
+		return errIssues, warnIssues
+	}
+	var resultErrIssues, resultWarnIssues YamlValidationIssues
+	add := func(issue YamlValidationIssue, severity string) {
+		ruleConfig, ok := config.Rules[issue.Rule]
+		if ok {
+			if ruleConfig.Enabled != nil && !*ruleConfig.Enabled || !ruleConfig.inScope(root, extension, issue.Line) {
+				return
+			}
+			if ruleConfig.Severity != "" {
+				severity = ruleConfig.Severity
+			}
+		}
+		if severity == SeverityError {
+			resultErrIssues = append(resultErrIssues, issue)
+		} else {
+			resultWarnIssues = append(resultWarnIssues, issue)
+		}
+	}
+	for _, issue := range errIssues {
+		add(issue, SeverityError)
+	}
+	for _, issue := range warnIssues {
+		add(issue, SeverityWarning)
+	}
+	return resultErrIssues, resultWarnIssues
+}
+
+// applyToContent applies the configuration to the issues of the descriptor content, which is the content of an MTA
+// extension descriptor when extension is true
+func (config *Config) applyToContent(yamlContent []byte, extension bool, errIssues, warnIssues YamlValidationIssues) (YamlValidationIssues, YamlValidationIssues) {
+	if len(config.Rules) == 0 {
+		return errIssues, warnIssues
+	}
+	// The issues of content which can't be parsed are not in a module
+	root, _ := getContentNode(yamlContent)
+	return config.apply(root, extension, errIssues, warnIssues)
+}
+
+// inScope checks if the issue on the line is in a module of the rule's scope.
+// Issues outside of modules are not in the scope of rules which are scoped to modules.
+// The modules of the MTA extension descriptors don't define types and paths, so all their issues are in the scope.
+func (ruleConfig RuleConfig) inScope(root *yaml.Node, extension bool, line int) bool {
+	if extension || len(ruleConfig.ModuleTypes) == 0 && len(ruleConfig.ModulePaths) == 0 {
+		return true
+	}
+	module := getModuleNodeByLine(root, line)
+	if module == nil {
+		return false
+	}
+	typeNode := getPropValueByName(module, typeYamlField)
+	pathNode := getPropValueByName(module, pathYamlField)
+	if len(ruleConfig.ModuleTypes) > 0 && (typeNode == nil || !containsString(ruleConfig.ModuleTypes, typeNode.Value)) {
+		return false
+	}
+	if len(ruleConfig.ModulePaths) > 0 {
+		if pathNode == nil {
+			return false
+		}
+		modulePath := path.Clean(filepath.ToSlash(pathNode.Value))
+		for _, pattern := range ruleConfig.ModulePaths {
+			if matched, _ := path.Match(pattern, modulePath); matched {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// getModuleNodeByLine returns the node of the module which is defined on the line, or nil
+func getModuleNodeByLine(root *yaml.Node, line int) *yaml.Node {
+	modules := getPropValueByName(root, modulesYamlField)
+	if modules == nil || modules.Kind != yaml.SequenceNode {
+		return nil
+	}
+	for _, module := range modules.Content {
+		if module.Kind == yaml.MappingNode && line >= module.Line && line <= getLastLine(module) {
+			return module
+		}
+	}
+	return nil
+}
+
+// getLastLine returns the last line on which the node or its content is defined
+func getLastLine(node *yaml.Node) int {
+	last := node.Line
+	for _, child := range node.Content {
+		if childLast := getLastLine(child); childLast > last {
+			last = childLast
+		}
+	}
+	return last
+}
@@ -0,0 +1,109 @@
+package validate
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Validation configuration", func() {
+	content := []byte(`
+ID: mta.config
+modules:
+- name: srv
+  type: nodejs
+  path: srv/app
+- name: ui
+  type: html5
+  path: ui
+resources:
+- name: db
+`)
+	srvIssue := YamlValidationIssue{Msg: fmt.Sprintf(`the "%s" path of the "%s" module does not exist`, "srv/app", "srv"), Line: 6, Column: 9, Rule: pathsValidation}
+	uiIssue := YamlValidationIssue{Msg: fmt.Sprintf(`the "%s" path of the "%s" module does not exist`, "ui", "ui"), Line: 9, Column: 9, Rule: pathsValidation}
+	resourceIssue := YamlValidationIssue{Msg: fmt.Sprintf(`the "%s" %s name is already in use; %s %s was found with the same name on line %d`, "db", "resource", "db", "module", 4), Line: 11, Column: 9, Rule: namesValidation}
+
+	var _ = table.DescribeTable("applies the rules configuration", func(configText string, expectedErrs, expectedWarns YamlValidationIssues) {
+		config, err := parseConfig([]byte(configText))
+		Ω(err).Should(Succeed())
+		errs, warns := config.applyToContent(content, false, YamlValidationIssues{srvIssue, uiIssue, resourceIssue}, nil)
+		Ω(errs).Should(Equal(expectedErrs))
+		Ω(warns).Should(Equal(expectedWarns))
+	},
+		table.Entry("empty configuration", "",
+			YamlValidationIssues{srvIssue, uiIssue, resourceIssue}, nil),
+		table.Entry("disabled rule", "rules: {paths: {enabled: false}}",
+			YamlValidationIssues{resourceIssue}, nil),
+		table.Entry("severity override", "rules: {names: {severity: warning}}",
+			YamlValidationIssues{srvIssue, uiIssue}, YamlValidationIssues{resourceIssue}),
+		table.Entry("module type scope", "rules: {paths: {module-types: [html5]}}",
+			YamlValidationIssues{uiIssue, resourceIssue}, nil),
+		table.Entry("module path scope", "rules: {paths: {module-paths: ['srv/*']}}",
+			YamlValidationIssues{srvIssue, resourceIssue}, nil),
+		table.Entry("issues outside of modules are not in the module scope", "rules: {names: {module-types: [nodejs]}}",
+			YamlValidationIssues{srvIssue, uiIssue}, nil),
+	)
+
+	var _ = table.DescribeTable("fails on an invalid configuration", func(configText string, expectedErr string) {
+		_, err := parseConfig([]byte(configText))
+		Ω(err).Should(HaveOccurred())
+		Ω(err.Error()).Should(ContainSubstring(expectedErr))
+	},
+		table.Entry("unknown rule", "rules: {path: {enabled: false}}", `the "path" rule in the validation configuration is unknown`),
+		table.Entry("invalid severity", "rules: {paths: {severity: info}}", fmt.Sprintf(invalidConfigSeverityMsg, "info", "paths")),
+		table.Entry("invalid module path pattern", "rules: {paths: {module-paths: ['[']}}", fmt.Sprintf(invalidConfigPathMsg, "[", "paths")),
+		table.Entry("unknown field", "rules: {paths: {disabled: true}}", "field disabled not found"),
+	)
+
+	It("scopes the issues of modules without a type and a path, except in the extension descriptors", func() {
+		docsContent := []byte("ID: mta.config\nmodules:\n- name: docs\n")
+		docsIssue := YamlValidationIssue{Msg: fmt.Sprintf(`the path of the "%s" module is not defined`, "docs"), Line: 3, Column: 9, Rule: emptyPathValidation}
+		config, err := parseConfig([]byte("rules: {emptyPath: {module-types: [nodejs]}}"))
+		Ω(err).Should(Succeed())
+		errs, _ := config.applyToContent(docsContent, false, YamlValidationIssues{docsIssue}, nil)
+		Ω(errs).Should(BeEmpty())
+		errs, _ = config.applyToContent(docsContent, true, YamlValidationIssues{docsIssue}, nil)
+		Ω(errs).Should(Equal(YamlValidationIssues{docsIssue}))
+	})
+
+	It("returns an empty configuration when the project doesn't have a configuration file", func() {
+		config, err := LoadConfig(getTestPath("testproject"))
+		Ω(err).Should(Succeed())
+		Ω(config.Rules).Should(BeEmpty())
+	})
+
+	It("honors the configuration of the project in MtaYaml", func() {
+		warn, err := MtaYaml(getTestPath("configProject"), "mta.yaml", true, true, false, "")
+		Ω(err).Should(HaveOccurred())
+		Ω(err.Error()).Should(ContainSubstring(`line 10: the "npm-opts" build configuration parameter is not supported`))
+		Ω(err.Error()).ShouldNot(ContainSubstring("line 16"))
+		Ω(err.Error()).ShouldNot(ContainSubstring(`the "ui" path`))
+		Ω(warn).Should(BeEmpty())
+	})
+
+	It("honors the configuration of the project in Validate", func() {
+		mtaPath := getTestPath("configProject", "mta.yaml")
+		result := Validate(mtaPath, nil)
+		Ω(result[mtaPath]).Should(HaveLen(1))
+		Ω(result[mtaPath][0].Severity).Should(Equal(SeverityError))
+		Ω(result[mtaPath][0].Rule).Should(Equal(deprecatedOptsValidation))
+		Ω(result[mtaPath][0].Line).Should(Equal(10))
+	})
+
+	It("reports an invalid configuration file as an issue of the configuration file", func() {
+		mtaPath := getTestPath("badConfigProject", "mta.yaml")
+		configPath := getTestPath("badConfigProject", ConfigFileName)
+		configMsg := fmt.Sprintf(readConfigFailedMsg, configPath) + ": field disabled not found in type validate.RuleConfig"
+		result, err := ValidateWithOptions(mtaPath, nil, ValidateOptions{})
+		Ω(err).Should(Succeed())
+		Ω(result[mtaPath]).Should(BeEmpty())
+		Ω(result[configPath]).Should(Equal([]FileValidationIssue{
+			{Severity: SeverityError, Message: configMsg, Line: 3, Column: 0, Rule: configRule},
+		}))
+
+		_, err = MtaYaml(getTestPath("badConfigProject"), "mta.yaml", true, true, true, pathsValidation)
+		Ω(err).Should(MatchError(fmt.Sprintf(validationErrorsMsg, configPath, "line 3: "+configMsg)))
+	})
+})
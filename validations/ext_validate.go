@@ -22,9 +22,9 @@ func Mtaext(projectPath, extPath string,
 		return "", e
 	}
 	if len(errIssues) > 0 {
-		return warnIssues.String(), errors.Errorf(validationErrorsMsg, extPath, errIssues.String())
+		return warnIssues.String(), withConfigIssues(projectPath, errors.Errorf(validationErrorsMsg, extPath, errIssues.String()))
 	}
-	return warnIssues.String(), nil
+	return warnIssues.String(), withConfigIssues(projectPath, nil)
 }
 
 func validateMtaext(projectPath, extPath string, validateSchema, validateSemantic, strict bool,
@@ -38,9 +38,15 @@ func validateMtaext(projectPath, extPath string, validateSchema, validateSemanti
 			return nil, nil, errors.Wrapf(e, couldNotValidateErrorMsg, extPath)
 		}
 
+		// The issues of the configuration file are reported on the configuration file
+		config, _ := loadValidationConfig(projectPath)
+
 		// Validates MTA content.
 		contentErrIssues, contentWarnIssues := validateExt(yamlContent, projectPath, extPath,
-			validateSchema, validateSemantic, strict, exclude)
+			validateSchema, validateSemantic, strict, config.excludeDisabled(exclude))
+		contentErrIssues, contentWarnIssues = suppressIssues(yamlContent, config.excludeDisabled(exclude),
+			contentErrIssues, contentWarnIssues)
+		contentErrIssues, contentWarnIssues = config.applyToContent(yamlContent, true, contentErrIssues, contentWarnIssues)
 		errIssues = append(errIssues, contentErrIssues...)
 		errIssues.Sort()
 		warnIssues = append(warnIssues, contentWarnIssues...)
@@ -76,6 +82,7 @@ func validateExt(yamlContent []byte, projectPath string, extFileName string,
 
 	if validateSemantic {
 		errs, warns := runExtSemanticValidations(mtaExt, extNode, projectPath, exclude, strict)
+		errIssues = removeCoveredUniquenessIssues(errIssues, errs, warns)
 		errIssues = append(errIssues, errs...)
 		warnIssues = append(warnIssues, warns...)
 	}
@@ -83,7 +90,8 @@ func validateExt(yamlContent []byte, projectPath string, extFileName string,
 }
 
 func validateExtSchema(mtaExt *mta.EXT, extNode *yaml.Node, strict bool) (errIssues YamlValidationIssues, warnIssues YamlValidationIssues) {
-	validations, schemaValidationLog := buildValidationsFromSchemaText(extSchemaDef)
+	version, errIssues, warnIssues := selectSchemaVersion(extNode)
+	validations, schemaValidationLog := buildValidationsFromSchemaText(getSchemaDef(version, true))
 	if len(schemaValidationLog) > 0 {
 		errIssues = append(errIssues, schemaValidationLog...)
 		return errIssues, warnIssues
@@ -21,7 +21,8 @@ var _ = Describe("MTAEXT validation tests", func() {
 		Entry("validate schema when path to extension is invalid should fail", "ui5app1", "my.mtaext", true, false, false),
 		Entry("validate semantic when path to extension is invalid should fail", "ui5app1", "my.mtaext", false, true, false),
 		Entry("nothing to validate when path to extension is invalid should not fail", "ui5app1", "my.mtaext", false, false, true),
-		Entry("validate schema should succeed when extension schema is valid", "mtahtml5", "my.mtaext", true, false, true),
+		Entry("validate schema should succeed when extension schema is valid", "mtahtml5", "valid.mtaext", true, false, true),
+		Entry("validate schema when module is extended twice should fail", "mtahtml5", "my.mtaext", true, false, false),
 		Entry("validate semantic when module is extended twice should fail", "mtahtml5", "my.mtaext", false, true, false),
 	)
 
@@ -99,11 +100,11 @@ a b`), getTestPath("mtahtml5"), "my.mtaext",
 		When("schema definition is different", func() {
 			var originalSchema []byte
 			BeforeEach(func() {
-				originalSchema = extSchemaDef
+				originalSchema = extSchemaDefV31
 			})
 
 			It("invalid schema definition", func() {
-				extSchemaDef = []byte(`
+				extSchemaDefV31 = []byte(`
 desc: MTA DESCRIPTOR SCHEMA
 # schema version must be extracted from here as there is no "version" element available to version schemas
   name: com.sap.mta.mta-schema_3.2.0 abc
@@ -115,7 +116,7 @@ desc: MTA DESCRIPTOR SCHEMA
 			})
 
 			AfterEach(func() {
-				extSchemaDef = originalSchema
+				extSchemaDefV31 = originalSchema
 			})
 		})
 
@@ -172,14 +173,14 @@ resources:
 			true, false, true, "")
 		Ω(warn).Should(BeNil())
 		Ω(err).Should(ConsistOf(
-			YamlValidationIssue{fmt.Sprintf(propertyExistsErrorMsg, "public", "modules[0].provides[0]"), 10, 5},
-			YamlValidationIssue{fmt.Sprintf(propertyExistsErrorMsg, "list", "modules[0].requires[0]"), 13, 5},
-			YamlValidationIssue{fmt.Sprintf(propertyExistsErrorMsg, "list", "modules[0].hooks[0].requires[0]"), 18, 7},
-			YamlValidationIssue{`field optional not found in type mta.ResourceExt`, 21, 0},
-			YamlValidationIssue{fmt.Sprintf(propertyExistsErrorMsg, "list", "resources[0].requires[0]"), 24, 5},
-			YamlValidationIssue{fmt.Sprintf(propertyExistsErrorMsg, "properties-metadata", "resources[1].requires[0]"), 28, 5},
-			YamlValidationIssue{fmt.Sprintf(propertyExistsErrorMsg, "parameters-metadata", "resources[1].requires[1]"), 30, 5},
-			YamlValidationIssue{fmt.Sprintf(propertyExistsErrorMsg, "properties-metadata", "resources[2].requires[0]"), 34, 5},
+			YamlValidationIssue{Msg: fmt.Sprintf(propertyExistsErrorMsg, "public", "modules[0].provides[0]"), Line: 10, Column: 5, Rule: notAllowedRule},
+			YamlValidationIssue{Msg: fmt.Sprintf(propertyExistsErrorMsg, "list", "modules[0].requires[0]"), Line: 13, Column: 5, Rule: notAllowedRule},
+			YamlValidationIssue{Msg: fmt.Sprintf(propertyExistsErrorMsg, "list", "modules[0].hooks[0].requires[0]"), Line: 18, Column: 7, Rule: notAllowedRule},
+			YamlValidationIssue{Msg: `field optional not found in type mta.ResourceExt`, Line: 21, Column: 0, Rule: parseRule},
+			YamlValidationIssue{Msg: fmt.Sprintf(propertyExistsErrorMsg, "list", "resources[0].requires[0]"), Line: 24, Column: 5, Rule: notAllowedRule},
+			YamlValidationIssue{Msg: fmt.Sprintf(propertyExistsErrorMsg, "properties-metadata", "resources[1].requires[0]"), Line: 28, Column: 5, Rule: notAllowedRule},
+			YamlValidationIssue{Msg: fmt.Sprintf(propertyExistsErrorMsg, "parameters-metadata", "resources[1].requires[1]"), Line: 30, Column: 5, Rule: notAllowedRule},
+			YamlValidationIssue{Msg: fmt.Sprintf(propertyExistsErrorMsg, "properties-metadata", "resources[2].requires[0]"), Line: 34, Column: 5, Rule: notAllowedRule},
 		))
 	})
 })
@@ -0,0 +1,593 @@
+package validate
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	parseJSONSchemaFailedMsg = "validation failed when parsing the JSON schema: %s"
+	invalidJSONSchemaRefMsg  = `validation failed when resolving the "%s" reference of the JSON schema`
+
+	requiredPropertyMsg     = `missing the "%s" required property in the %s .yaml node`
+	propertyTypeMsg         = `the "%s" property must be %s`
+	enumValueMsg            = `the "%s" value of the "%s" enum property is invalid; expected one of the following: %s`
+	constValueMsg           = `the "%s" value of the "%s" property is invalid; expected %s`
+	patternMismatchMsg      = `the "%s" value of the "%s" property does not match the "%s" pattern`
+	minLengthMsg            = `the "%s" value of the "%s" property must be at least %d characters long`
+	maxLengthMsg            = `the "%s" value of the "%s" property must be at most %d characters long`
+	minimumMsg              = `the "%s" value of the "%s" property must be greater than or equal to %v`
+	maximumMsg              = `the "%s" value of the "%s" property must be less than or equal to %v`
+	exclusiveMinimumMsg     = `the "%s" value of the "%s" property must be greater than %v`
+	exclusiveMaximumMsg     = `the "%s" value of the "%s" property must be less than %v`
+	multipleOfMsg           = `the "%s" value of the "%s" property must be a multiple of %v`
+	minItemsMsg             = `the "%s" property must have at least %d items`
+	maxItemsMsg             = `the "%s" property must have at most %d items`
+	uniqueItemsMsg          = `the "%s" property must not have duplicate items`
+	containsMsg             = `the "%s" property must contain an item which matches the schema`
+	minPropertiesMsg        = `the "%s" property must have at least %d properties`
+	maxPropertiesMsg        = `the "%s" property must have at most %d properties`
+	notAllowedMsg           = `the "%s" property is not allowed`
+	anyOfMsg                = `the "%s" property does not match any of the allowed schemas`
+	oneOfNoneMsg            = `the "%s" property does not match exactly one of the allowed schemas; it matches none of them`
+	oneOfManyMsg            = `the "%s" property does not match exactly one of the allowed schemas; it matches %d of them`
+	notMsg                  = `the "%s" property must not match the schema`
+	dependentPropertyMsg    = `missing the "%s" property, which is required by the "%s" property in the %s .yaml node`
+	maxEnumValuesInMessages = 4
+)
+
+// jsonSchema - a JSON schema (draft-07), which validates YAML documents.
+// The schema keywords are interpreted on the yaml.v3 node tree, so the issues have the lines and columns of the nodes.
+// Only local references (e.g. "#/definitions/name") are supported; the "format" keyword is not validated.
+// The paths in the messages start at the root of the document, e.g. root.modules[0].name.
+type jsonSchema struct {
+	root     interface{}
+	patterns map[string]*regexp.Regexp
+}
+
+// mtaJSONSchema - the embedded JSON schema of the MTA descriptor, which is parsed once
+var mtaJSONSchema struct {
+	once   sync.Once
+	schema *jsonSchema
+	err    error
+}
+
+// getMTAJSONSchema returns the parsed JSON schema of the MTA descriptor
+func getMTAJSONSchema() (*jsonSchema, error) {
+	mtaJSONSchema.once.Do(func() {
+		mtaJSONSchema.schema, mtaJSONSchema.err = parseJSONSchema(jsonSchemaDef)
+	})
+	return mtaJSONSchema.schema, mtaJSONSchema.err
+}
+
+// parseJSONSchema parses a JSON schema and compiles its patterns
+func parseJSONSchema(content []byte) (*jsonSchema, error) {
+	var root interface{}
+	if err := json.Unmarshal(content, &root); err != nil {
+		return nil, errors.Errorf(parseJSONSchemaFailedMsg, err.Error())
+	}
+	schema := &jsonSchema{root: root, patterns: map[string]*regexp.Regexp{}}
+	if err := schema.compilePatterns(root); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+func (s *jsonSchema) compilePatterns(schema interface{}) error {
+	switch v := schema.(type) {
+	case map[string]interface{}:
+		if pattern, ok := v["pattern"].(string); ok {
+			if err := s.compilePattern(pattern); err != nil {
+				return err
+			}
+		}
+		if patternProperties, ok := v["patternProperties"].(map[string]interface{}); ok {
+			for pattern := range patternProperties {
+				if err := s.compilePattern(pattern); err != nil {
+					return err
+				}
+			}
+		}
+		for key, value := range v {
+			// enum and const values are data, not schemas
+			if key == "enum" || key == "const" || key == "default" || key == "examples" {
+				continue
+			}
+			if err := s.compilePatterns(value); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, value := range v {
+			if err := s.compilePatterns(value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *jsonSchema) compilePattern(pattern string) error {
+	if _, ok := s.patterns[pattern]; ok {
+		return nil
+	}
+	regExp, err := regexp.Compile(pattern)
+	if err != nil {
+		return errors.Errorf(parseJSONSchemaFailedMsg, err.Error())
+	}
+	s.patterns[pattern] = regExp
+	return nil
+}
+
+// validate validates the document node against the schema
+func (s *jsonSchema) validate(root *yaml.Node) YamlValidationIssues {
+	if root == nil {
+		return nil
+	}
+	return s.validateNode(s.root, root, root, []string{})
+}
+
+// validateNode validates a node against a schema; the parent node is used for the position of missing properties
+func (s *jsonSchema) validateNode(schema interface{}, node *yaml.Node, parent *yaml.Node, path []string) YamlValidationIssues {
+	node = resolveAlias(node)
+	switch v := schema.(type) {
+	case bool:
+		if !v {
+			return YamlValidationIssues{nodeIssue(notAllowedRule, fmt.Sprintf(notAllowedMsg, jsonPathString(path)), node)}
+		}
+		return nil
+	case map[string]interface{}:
+		return s.validateKeywords(v, node, parent, path)
+	}
+	return nil
+}
+
+func (s *jsonSchema) validateKeywords(schema map[string]interface{}, node *yaml.Node, parent *yaml.Node, path []string) YamlValidationIssues {
+	var issues YamlValidationIssues
+
+	// Keywords next to a reference are also validated, as in later drafts
+	if ref, ok := schema["$ref"].(string); ok {
+		target, err := s.resolveRef(ref)
+		if err != nil {
+			return YamlValidationIssues{{Msg: err.Error(), Rule: schemaDefinitionRule}}
+		}
+		issues = append(issues, s.validateNode(target, node, parent, path)...)
+	}
+
+	if types, ok := schema["type"]; ok && !matchesType(types, node) {
+		// The other keywords depend on the type, so they are not validated
+		return append(issues, nodeIssue(propertyTypeRule, fmt.Sprintf(propertyTypeMsg, jsonPathString(path), describeTypes(types)), node))
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && !containsValue(enum, nodeValue(node)) {
+		issues = append(issues, nodeIssue(enumRule, fmt.Sprintf(enumValueMsg, node.Value, jsonPathString(path), describeEnum(enum)), node))
+	}
+	if constValue, ok := schema["const"]; ok && !containsValue([]interface{}{constValue}, nodeValue(node)) {
+		issues = append(issues, nodeIssue(enumRule, fmt.Sprintf(constValueMsg, node.Value, jsonPathString(path), describeValue(constValue)), node))
+	}
+
+	switch node.Kind {
+	case yaml.ScalarNode:
+		issues = append(issues, s.validateScalar(schema, node, path)...)
+	case yaml.SequenceNode:
+		issues = append(issues, s.validateArray(schema, node, path)...)
+	case yaml.MappingNode:
+		issues = append(issues, s.validateObject(schema, node, path)...)
+	}
+	issues = append(issues, s.validateCombinations(schema, node, parent, path)...)
+	return issues
+}
+
+func (s *jsonSchema) validateScalar(schema map[string]interface{}, node *yaml.Node, path []string) YamlValidationIssues {
+	var issues YamlValidationIssues
+	pathStr := jsonPathString(path)
+	if nodeType(node) == "string" {
+		length := len([]rune(node.Value))
+		if min, ok := getInt(schema, "minLength"); ok && length < min {
+			issues = append(issues, nodeIssue(lengthRule, fmt.Sprintf(minLengthMsg, node.Value, pathStr, min), node))
+		}
+		if max, ok := getInt(schema, "maxLength"); ok && length > max {
+			issues = append(issues, nodeIssue(lengthRule, fmt.Sprintf(maxLengthMsg, node.Value, pathStr, max), node))
+		}
+		if pattern, ok := schema["pattern"].(string); ok && !s.patterns[pattern].MatchString(node.Value) {
+			issues = append(issues, nodeIssue(patternRule, fmt.Sprintf(patternMismatchMsg, node.Value, pathStr, pattern), node))
+		}
+	}
+	if number, ok := nodeValue(node).(float64); ok {
+		if min, ok := schema["minimum"].(float64); ok && number < min {
+			issues = append(issues, nodeIssue(rangeRule, fmt.Sprintf(minimumMsg, node.Value, pathStr, min), node))
+		}
+		if max, ok := schema["maximum"].(float64); ok && number > max {
+			issues = append(issues, nodeIssue(rangeRule, fmt.Sprintf(maximumMsg, node.Value, pathStr, max), node))
+		}
+		if min, ok := schema["exclusiveMinimum"].(float64); ok && number <= min {
+			issues = append(issues, nodeIssue(rangeRule, fmt.Sprintf(exclusiveMinimumMsg, node.Value, pathStr, min), node))
+		}
+		if max, ok := schema["exclusiveMaximum"].(float64); ok && number >= max {
+			issues = append(issues, nodeIssue(rangeRule, fmt.Sprintf(exclusiveMaximumMsg, node.Value, pathStr, max), node))
+		}
+		if divisor, ok := schema["multipleOf"].(float64); ok && divisor > 0 {
+			if quotient := number / divisor; math.Abs(quotient-math.Round(quotient)) > 1e-9 {
+				issues = append(issues, nodeIssue(rangeRule, fmt.Sprintf(multipleOfMsg, node.Value, pathStr, divisor), node))
+			}
+		}
+	}
+	return issues
+}
+
+func (s *jsonSchema) validateArray(schema map[string]interface{}, node *yaml.Node, path []string) YamlValidationIssues {
+	var issues YamlValidationIssues
+	pathStr := jsonPathString(path)
+	if min, ok := getInt(schema, "minItems"); ok && len(node.Content) < min {
+		issues = append(issues, nodeIssue(lengthRule, fmt.Sprintf(minItemsMsg, pathStr, min), node))
+	}
+	if max, ok := getInt(schema, "maxItems"); ok && len(node.Content) > max {
+		issues = append(issues, nodeIssue(lengthRule, fmt.Sprintf(maxItemsMsg, pathStr, max), node))
+	}
+	if unique, _ := schema["uniqueItems"].(bool); unique && hasDuplicateItems(node) {
+		issues = append(issues, nodeIssue(uniqueRule, fmt.Sprintf(uniqueItemsMsg, pathStr), node))
+	}
+
+	switch items := schema["items"].(type) {
+	case []interface{}:
+		// Tuple validation: the items after the listed schemas are validated against additionalItems
+		for i, child := range node.Content {
+			itemPath := append(copyPath(path), fmt.Sprintf("[%d]", i))
+			if i < len(items) {
+				issues = append(issues, s.validateNode(items[i], child, node, itemPath)...)
+			} else if additionalItems, ok := schema["additionalItems"]; ok {
+				issues = append(issues, s.validateNode(additionalItems, child, node, itemPath)...)
+			}
+		}
+	case nil:
+	default:
+		for i, child := range node.Content {
+			issues = append(issues, s.validateNode(items, child, node, append(copyPath(path), fmt.Sprintf("[%d]", i)))...)
+		}
+	}
+
+	if contains, ok := schema["contains"]; ok {
+		found := false
+		for i, child := range node.Content {
+			if len(s.validateNode(contains, child, node, append(copyPath(path), fmt.Sprintf("[%d]", i)))) == 0 {
+				found = true
+				break
+			}
+		}
+		if !found {
+			issues = append(issues, nodeIssue(schemaCompositeRule, fmt.Sprintf(containsMsg, pathStr), node))
+		}
+	}
+	return issues
+}
+
+func (s *jsonSchema) validateObject(schema map[string]interface{}, node *yaml.Node, path []string) YamlValidationIssues {
+	var issues YamlValidationIssues
+	pathStr := jsonPathString(path)
+	count := len(node.Content) / 2
+	if min, ok := getInt(schema, "minProperties"); ok && count < min {
+		issues = append(issues, nodeIssue(lengthRule, fmt.Sprintf(minPropertiesMsg, pathStr, min), node))
+	}
+	if max, ok := getInt(schema, "maxProperties"); ok && count > max {
+		issues = append(issues, nodeIssue(lengthRule, fmt.Sprintf(maxPropertiesMsg, pathStr, max), node))
+	}
+
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, name := range required {
+			if nameStr, ok := name.(string); ok && getPropByName(node, nameStr) == nil {
+				issues = append(issues, nodeIssue(requiredPropertyRule, fmt.Sprintf(requiredPropertyMsg, nameStr, pathStr), node))
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	patternProperties, _ := schema["patternProperties"].(map[string]interface{})
+	additionalProperties, hasAdditionalProperties := schema["additionalProperties"]
+	propertyNames, hasPropertyNames := schema["propertyNames"]
+	dependencies, _ := schema["dependencies"].(map[string]interface{})
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+		key := keyNode.Value
+		propPath := append(copyPath(path), key)
+
+		if hasPropertyNames {
+			keyIssues := s.validateNode(propertyNames, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key,
+				Line: keyNode.Line, Column: keyNode.Column}, node, propPath)
+			issues = append(issues, keyIssues...)
+		}
+
+		matched := false
+		if propSchema, ok := properties[key]; ok {
+			matched = true
+			issues = append(issues, s.validateNode(propSchema, valueNode, node, propPath)...)
+		}
+		for pattern, propSchema := range patternProperties {
+			if s.patterns[pattern].MatchString(key) {
+				matched = true
+				issues = append(issues, s.validateNode(propSchema, valueNode, node, propPath)...)
+			}
+		}
+		if !matched && hasAdditionalProperties {
+			if allowed, ok := additionalProperties.(bool); ok {
+				if !allowed {
+					issues = append(issues, nodeIssue(notAllowedRule, fmt.Sprintf(propertyExistsErrorMsg, key, pathStr), keyNode))
+				}
+			} else {
+				issues = append(issues, s.validateNode(additionalProperties, valueNode, node, propPath)...)
+			}
+		}
+
+		switch dependency := dependencies[key].(type) {
+		case []interface{}:
+			for _, name := range dependency {
+				if nameStr, ok := name.(string); ok && getPropByName(node, nameStr) == nil {
+					issues = append(issues, nodeIssue(schemaCompositeRule, fmt.Sprintf(dependentPropertyMsg, nameStr, key, pathStr), keyNode))
+				}
+			}
+		case map[string]interface{}, bool:
+			issues = append(issues, s.validateNode(dependency, node, node, path)...)
+		}
+	}
+	return issues
+}
+
+// validateCombinations validates the allOf, anyOf, oneOf, not and if/then/else keywords
+func (s *jsonSchema) validateCombinations(schema map[string]interface{}, node *yaml.Node, parent *yaml.Node, path []string) YamlValidationIssues {
+	var issues YamlValidationIssues
+	pathStr := jsonPathString(path)
+	if allOf, ok := schema["allOf"].([]interface{}); ok {
+		for _, subSchema := range allOf {
+			issues = append(issues, s.validateNode(subSchema, node, parent, path)...)
+		}
+	}
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok && s.countMatches(anyOf, node, parent, path) == 0 {
+		issues = append(issues, nodeIssue(schemaCompositeRule, fmt.Sprintf(anyOfMsg, pathStr), node))
+	}
+	if oneOf, ok := schema["oneOf"].([]interface{}); ok {
+		if matches := s.countMatches(oneOf, node, parent, path); matches == 0 {
+			issues = append(issues, nodeIssue(schemaCompositeRule, fmt.Sprintf(oneOfNoneMsg, pathStr), node))
+		} else if matches > 1 {
+			issues = append(issues, nodeIssue(schemaCompositeRule, fmt.Sprintf(oneOfManyMsg, pathStr, matches), node))
+		}
+	}
+	if not, ok := schema["not"]; ok && len(s.validateNode(not, node, parent, path)) == 0 {
+		issues = append(issues, nodeIssue(schemaCompositeRule, fmt.Sprintf(notMsg, pathStr), node))
+	}
+	if condition, ok := schema["if"]; ok {
+		if len(s.validateNode(condition, node, parent, path)) == 0 {
+			if then, ok := schema["then"]; ok {
+				issues = append(issues, s.validateNode(then, node, parent, path)...)
+			}
+		} else if otherwise, ok := schema["else"]; ok {
+			issues = append(issues, s.validateNode(otherwise, node, parent, path)...)
+		}
+	}
+	return issues
+}
+
+func (s *jsonSchema) countMatches(schemas []interface{}, node *yaml.Node, parent *yaml.Node, path []string) int {
+	matches := 0
+	for _, subSchema := range schemas {
+		if len(s.validateNode(subSchema, node, parent, path)) == 0 {
+			matches++
+		}
+	}
+	return matches
+}
+
+// resolveRef returns the schema of a local reference, which is a JSON pointer in the schema document
+func (s *jsonSchema) resolveRef(ref string) (interface{}, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, errors.Errorf(invalidJSONSchemaRefMsg, ref)
+	}
+	current := s.root
+	pointer := strings.TrimPrefix(ref, "#")
+	if len(pointer) == 0 {
+		return current, nil
+	}
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+		switch v := current.(type) {
+		case map[string]interface{}:
+			value, ok := v[token]
+			if !ok {
+				return nil, errors.Errorf(invalidJSONSchemaRefMsg, ref)
+			}
+			current = value
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(v) {
+				return nil, errors.Errorf(invalidJSONSchemaRefMsg, ref)
+			}
+			current = v[index]
+		default:
+			return nil, errors.Errorf(invalidJSONSchemaRefMsg, ref)
+		}
+	}
+	return current, nil
+}
+
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node != nil && node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node
+}
+
+// nodeType returns the JSON type of a node: object, array, string, integer, number, boolean or null.
+// Scalars with custom tags, like !sensitive, are strings.
+func nodeType(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	}
+	switch node.Tag {
+	case "!!int":
+		return "integer"
+	case "!!float":
+		return "number"
+	case "!!bool":
+		return "boolean"
+	case "!!null":
+		return "null"
+	}
+	return "string"
+}
+
+func matchesType(types interface{}, node *yaml.Node) bool {
+	actual := nodeType(node)
+	matches := func(expected interface{}) bool {
+		switch expected {
+		case actual:
+			return true
+		case "number":
+			return actual == "integer"
+		case "integer":
+			// Floats without a fraction are integers in JSON schema
+			value, ok := nodeValue(node).(float64)
+			return ok && value == math.Trunc(value)
+		}
+		return false
+	}
+	if list, ok := types.([]interface{}); ok {
+		for _, expected := range list {
+			if matches(expected) {
+				return true
+			}
+		}
+		return false
+	}
+	return matches(types)
+}
+
+func describeTypes(types interface{}) string {
+	descriptions := map[string]string{
+		"object":  "a map",
+		"array":   "an array",
+		"string":  "a string",
+		"integer": "an integer",
+		"number":  "a number",
+		"boolean": "a boolean",
+		"null":    "null",
+	}
+	list, ok := types.([]interface{})
+	if !ok {
+		list = []interface{}{types}
+	}
+	var result []string
+	for _, t := range list {
+		result = append(result, descriptions[fmt.Sprint(t)])
+	}
+	return strings.Join(result, " or ")
+}
+
+// nodeValue returns the value of a node in the JSON representation: maps, slices, strings, float64 numbers, booleans or nil
+func nodeValue(node *yaml.Node) interface{} {
+	node = resolveAlias(node)
+	switch node.Kind {
+	case yaml.MappingNode:
+		result := map[string]interface{}{}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			result[node.Content[i].Value] = nodeValue(node.Content[i+1])
+		}
+		return result
+	case yaml.SequenceNode:
+		result := make([]interface{}, 0, len(node.Content))
+		for _, child := range node.Content {
+			result = append(result, nodeValue(child))
+		}
+		return result
+	}
+	switch nodeType(node) {
+	case "integer", "number":
+		var value float64
+		if err := node.Decode(&value); err == nil {
+			return value
+		}
+	case "boolean":
+		var value bool
+		if err := node.Decode(&value); err == nil {
+			return value
+		}
+	case "null":
+		return nil
+	}
+	return node.Value
+}
+
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if reflect.DeepEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasDuplicateItems(node *yaml.Node) bool {
+	var values []interface{}
+	for _, child := range node.Content {
+		value := nodeValue(child)
+		if containsValue(values, value) {
+			return true
+		}
+		values = append(values, value)
+	}
+	return false
+}
+
+// describeEnum returns the first enum values, as in the kwalify schema validation
+func describeEnum(enum []interface{}) string {
+	var values []string
+	for i, value := range enum {
+		if i == maxEnumValuesInMessages {
+			break
+		}
+		values = append(values, fmt.Sprint(value))
+	}
+	return strings.Join(values, ",")
+}
+
+func describeValue(value interface{}) string {
+	if str, ok := value.(string); ok {
+		return `"` + str + `"`
+	}
+	content, _ := json.Marshal(value)
+	return string(content)
+}
+
+func getInt(schema map[string]interface{}, keyword string) (int, bool) {
+	value, ok := schema[keyword].(float64)
+	return int(value), ok
+}
+
+// jsonPathString returns the path of a node from the root of the document, e.g. root.modules[0].name
+func jsonPathString(path []string) string {
+	var sb strings.Builder
+	sb.WriteString("root")
+	for _, segment := range path {
+		if !strings.HasPrefix(segment, "[") {
+			sb.WriteString(".")
+		}
+		sb.WriteString(segment)
+	}
+	return sb.String()
+}
+
+func copyPath(path []string) []string {
+	return append([]string{}, path...)
+}
+
+func nodeIssue(rule string, msg string, node *yaml.Node) YamlValidationIssue {
+	return YamlValidationIssue{Msg: msg, Line: node.Line, Column: node.Column, Rule: rule}
+}
@@ -0,0 +1,136 @@
+package validate
+
+import (
+	"fmt"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JSON schema", func() {
+	validateContent := func(schemaText string, content string) YamlValidationIssues {
+		schema, err := parseJSONSchema([]byte(schemaText))
+		Ω(err).Should(Succeed())
+		root, err := getContentNode([]byte(content))
+		Ω(err).Should(Succeed())
+		return schema.validate(root)
+	}
+
+	var _ = table.DescribeTable("keywords", func(schemaText string, content string, expected ...YamlValidationIssue) {
+		issues := validateContent(schemaText, content)
+		if len(expected) == 0 {
+			Ω(issues).Should(BeEmpty())
+		} else {
+			Ω(issues).Should(ConsistOf(expected))
+		}
+	},
+		table.Entry("valid type", `{"properties": {"a": {"type": "string"}}}`, "a: text"),
+		table.Entry("invalid type", `{"properties": {"a": {"type": "string"}}}`, "a: [1]",
+			YamlValidationIssue{Msg: fmt.Sprintf(propertyTypeMsg, "root.a", "a string"), Line: 1, Column: 4, Rule: propertyTypeRule}),
+		table.Entry("one of several types", `{"properties": {"a": {"type": ["boolean", "integer"]}}}`, "a: 1"),
+		table.Entry("integer as a number", `{"properties": {"a": {"type": "number"}}}`, "a: 1"),
+		table.Entry("float without a fraction as an integer", `{"properties": {"a": {"type": "integer"}}}`, "a: 1.0"),
+		table.Entry("float as an integer", `{"properties": {"a": {"type": "integer"}}}`, "a: 1.5",
+			YamlValidationIssue{Msg: fmt.Sprintf(propertyTypeMsg, "root.a", "an integer"), Line: 1, Column: 4, Rule: propertyTypeRule}),
+		table.Entry("tagged scalar as a string", `{"properties": {"a": {"type": "string"}}}`, "a: !sensitive secret"),
+		table.Entry("required property", `{"required": ["a", "b"]}`, "a: 1",
+			YamlValidationIssue{Msg: fmt.Sprintf(requiredPropertyMsg, "b", "root"), Line: 1, Column: 1, Rule: requiredPropertyRule}),
+		table.Entry("enum value", `{"properties": {"a": {"enum": ["x", "y", 1]}}}`, "a: z",
+			YamlValidationIssue{Msg: fmt.Sprintf(enumValueMsg, "z", "root.a", "x,y,1"), Line: 1, Column: 4, Rule: enumRule}),
+		table.Entry("numeric enum value", `{"properties": {"a": {"enum": ["x", 1]}}}`, "a: 1"),
+		table.Entry("const value", `{"properties": {"a": {"const": "x"}}}`, "a: y",
+			YamlValidationIssue{Msg: fmt.Sprintf(constValueMsg, "y", "root.a", `"x"`), Line: 1, Column: 4, Rule: enumRule}),
+		table.Entry("pattern", `{"properties": {"a": {"pattern": "^[a-z]+$"}}}`, "a: A1",
+			YamlValidationIssue{Msg: fmt.Sprintf(patternMismatchMsg, "A1", "root.a", "^[a-z]+$"), Line: 1, Column: 4, Rule: patternRule}),
+		table.Entry("string length", `{"properties": {"a": {"minLength": 2}, "b": {"maxLength": 2}}}`, "a: x\nb: xyz",
+			YamlValidationIssue{Msg: fmt.Sprintf(minLengthMsg, "x", "root.a", 2), Line: 1, Column: 4, Rule: lengthRule},
+			YamlValidationIssue{Msg: fmt.Sprintf(maxLengthMsg, "xyz", "root.b", 2), Line: 2, Column: 4, Rule: lengthRule}),
+		table.Entry("number range", `{"properties": {"a": {"minimum": 2}, "b": {"exclusiveMaximum": 2}}}`, "a: 1\nb: 2",
+			YamlValidationIssue{Msg: fmt.Sprintf(minimumMsg, "1", "root.a", 2.0), Line: 1, Column: 4, Rule: rangeRule},
+			YamlValidationIssue{Msg: fmt.Sprintf(exclusiveMaximumMsg, "2", "root.b", 2.0), Line: 2, Column: 4, Rule: rangeRule}),
+		table.Entry("array items", `{"properties": {"a": {"items": {"type": "string"}, "minItems": 3}}}`, "a:\n- x\n- {}",
+			YamlValidationIssue{Msg: fmt.Sprintf(minItemsMsg, "root.a", 3), Line: 2, Column: 1, Rule: lengthRule},
+			YamlValidationIssue{Msg: fmt.Sprintf(propertyTypeMsg, "root.a[1]", "a string"), Line: 3, Column: 3, Rule: propertyTypeRule}),
+		table.Entry("unique items", `{"properties": {"a": {"uniqueItems": true}}}`, "a: [x, y, x]",
+			YamlValidationIssue{Msg: fmt.Sprintf(uniqueItemsMsg, "root.a"), Line: 1, Column: 4, Rule: uniqueRule}),
+		table.Entry("additional properties", `{"properties": {"a": {}}, "additionalProperties": false}`, "a: 1\nb: 2",
+			YamlValidationIssue{Msg: fmt.Sprintf(propertyExistsErrorMsg, "b", "root"), Line: 2, Column: 1, Rule: notAllowedRule}),
+		table.Entry("pattern properties", `{"patternProperties": {"^x-": {"type": "string"}}, "additionalProperties": false}`,
+			"x-a: 1",
+			YamlValidationIssue{Msg: fmt.Sprintf(propertyTypeMsg, "root.x-a", "a string"), Line: 1, Column: 6, Rule: propertyTypeRule}),
+		table.Entry("property names", `{"propertyNames": {"pattern": "^[a-z]+$"}}`, "aB: 1",
+			YamlValidationIssue{Msg: fmt.Sprintf(patternMismatchMsg, "aB", "root.aB", "^[a-z]+$"), Line: 1, Column: 1, Rule: patternRule}),
+		table.Entry("local reference", `{"properties": {"a": {"$ref": "#/definitions/b"}}, "definitions": {"b": {"type": "string"}}}`,
+			"a: [1]",
+			YamlValidationIssue{Msg: fmt.Sprintf(propertyTypeMsg, "root.a", "a string"), Line: 1, Column: 4, Rule: propertyTypeRule}),
+		table.Entry("keywords next to a reference", `{"properties": {"a": {"$ref": "#/definitions/b", "required": ["c"]}}, "definitions": {"b": {"type": "object"}}}`,
+			"a: {}",
+			YamlValidationIssue{Msg: fmt.Sprintf(requiredPropertyMsg, "c", "root.a"), Line: 1, Column: 4, Rule: requiredPropertyRule}),
+		table.Entry("any of", `{"properties": {"a": {"anyOf": [{"type": "string"}, {"type": "boolean"}]}}}`, "a: 1",
+			YamlValidationIssue{Msg: fmt.Sprintf(anyOfMsg, "root.a"), Line: 1, Column: 4, Rule: schemaCompositeRule}),
+		table.Entry("one of", `{"properties": {"a": {"oneOf": [{"type": "number"}, {"type": "integer"}]}}}`, "a: 1",
+			YamlValidationIssue{Msg: fmt.Sprintf(oneOfManyMsg, "root.a", 2), Line: 1, Column: 4, Rule: schemaCompositeRule}),
+		table.Entry("not", `{"properties": {"a": {"not": {"type": "string"}}}}`, "a: x",
+			YamlValidationIssue{Msg: fmt.Sprintf(notMsg, "root.a"), Line: 1, Column: 4, Rule: schemaCompositeRule}),
+		table.Entry("if then else", `{"if": {"properties": {"a": {"const": "x"}}}, "then": {"required": ["b"]}, "else": {"required": ["c"]}}`,
+			"a: x",
+			YamlValidationIssue{Msg: fmt.Sprintf(requiredPropertyMsg, "b", "root"), Line: 1, Column: 1, Rule: requiredPropertyRule}),
+		table.Entry("false schema", `{"properties": {"a": false}}`, "a: 1",
+			YamlValidationIssue{Msg: fmt.Sprintf(notAllowedMsg, "root.a"), Line: 1, Column: 4, Rule: notAllowedRule}),
+		table.Entry("anchors and aliases", `{"properties": {"b": {"type": "string"}}}`, "a: &x [1]\nb: *x",
+			YamlValidationIssue{Msg: fmt.Sprintf(propertyTypeMsg, "root.b", "a string"), Line: 1, Column: 4, Rule: propertyTypeRule}),
+	)
+
+	It("fails to parse a schema with an invalid pattern", func() {
+		_, err := parseJSONSchema([]byte(`{"properties": {"a": {"pattern": "("}}}`))
+		Ω(err).Should(HaveOccurred())
+	})
+
+	It("reports an invalid reference", func() {
+		issues := validateContent(`{"properties": {"a": {"$ref": "#/definitions/missing"}}}`, "a: 1")
+		Ω(issues).Should(Equal(YamlValidationIssues{{Msg: fmt.Sprintf(invalidJSONSchemaRefMsg, "#/definitions/missing"), Line: 0, Column: 0, Rule: schemaDefinitionRule}}))
+	})
+
+	It("parses the embedded JSON schema of the IDE once", func() {
+		schema, err := getMTAJSONSchema()
+		Ω(err).Should(Succeed())
+		Ω(getMTAJSONSchema()).Should(BeIdenticalTo(schema))
+	})
+
+	It("validates the MTA descriptor against the JSON schema of the IDE", func() {
+		content := `
+ID: mta.json-schema
+_schema-version: '3.3'
+
+modules:
+- name: srv
+  type: nodejs
+  path: srv
+  hooks:
+  - name: hook
+    type: script
+`
+		errIssues, _ := validateWithSchemaEngine([]byte(content), getTestPath("testproject"), true, false, true, "", JSONSchemaEngine)
+		Ω(errIssues).Should(ConsistOf(
+			YamlValidationIssue{Msg: fmt.Sprintf(requiredPropertyMsg, "version", "root"), Line: 2, Column: 1, Rule: requiredPropertyRule},
+			YamlValidationIssue{Msg: fmt.Sprintf(enumValueMsg, "script", "root.modules[0].hooks[0].type", "task"), Line: 11, Column: 11, Rule: enumRule},
+		))
+	})
+
+	It("validates with the JSON schema engine in ValidateWithOptions", func() {
+		mtaPath := getTestPath("testproject", "mta.yaml")
+		kwalifyResult, err := ValidateWithOptions(mtaPath, nil, ValidateOptions{SchemaEngine: KwalifySchemaEngine})
+		Ω(err).Should(Succeed())
+		jsonResult, err := ValidateWithOptions(mtaPath, nil, ValidateOptions{SchemaEngine: JSONSchemaEngine})
+		Ω(err).Should(Succeed())
+		Ω(jsonResult[mtaPath]).Should(Equal(kwalifyResult[mtaPath]))
+		Ω(Validate(mtaPath, nil)).Should(Equal(kwalifyResult))
+	})
+
+	It("fails when the schema engine is unknown", func() {
+		_, err := ValidateWithOptions(filepath.Join("testdata", "testproject", "mta.yaml"), nil, ValidateOptions{SchemaEngine: "xsd"})
+		Ω(err).Should(MatchError(`the "xsd" schema engine is incorrect; expected one of the following: kwalify, json-schema`))
+	})
+})
@@ -94,13 +94,13 @@ resources:
 
 		datatypeNotAllowedForParametersMetadata := fmt.Sprintf(propertyExistsErrorMsg, datatypeYamlField, parametersMetadataField)
 		Ω(issues).Should(ConsistOf(
-			YamlValidationIssue{datatypeNotAllowedForParametersMetadata, 10, 5},
-			YamlValidationIssue{datatypeNotAllowedForParametersMetadata, 22, 7},
-			YamlValidationIssue{datatypeNotAllowedForParametersMetadata, 33, 9},
-			YamlValidationIssue{datatypeNotAllowedForParametersMetadata, 40, 11},
-			YamlValidationIssue{datatypeNotAllowedForParametersMetadata, 54, 9},
-			YamlValidationIssue{datatypeNotAllowedForParametersMetadata, 63, 7},
-			YamlValidationIssue{datatypeNotAllowedForParametersMetadata, 75, 9},
+			YamlValidationIssue{Msg: datatypeNotAllowedForParametersMetadata, Line: 10, Column: 5, Rule: notAllowedRule},
+			YamlValidationIssue{Msg: datatypeNotAllowedForParametersMetadata, Line: 22, Column: 7, Rule: notAllowedRule},
+			YamlValidationIssue{Msg: datatypeNotAllowedForParametersMetadata, Line: 33, Column: 9, Rule: notAllowedRule},
+			YamlValidationIssue{Msg: datatypeNotAllowedForParametersMetadata, Line: 40, Column: 11, Rule: notAllowedRule},
+			YamlValidationIssue{Msg: datatypeNotAllowedForParametersMetadata, Line: 54, Column: 9, Rule: notAllowedRule},
+			YamlValidationIssue{Msg: datatypeNotAllowedForParametersMetadata, Line: 63, Column: 7, Rule: notAllowedRule},
+			YamlValidationIssue{Msg: datatypeNotAllowedForParametersMetadata, Line: 75, Column: 9, Rule: notAllowedRule},
 		))
 	})
 })
@@ -19,6 +19,21 @@ const (
 	SeverityWarning = "warning"
 )
 
+// The engines of the MTA descriptor schema validation
+const (
+	// KwalifySchemaEngine - validates the MTA descriptor against the kwalify schema of its "_schema-version"
+	KwalifySchemaEngine = "kwalify"
+	// JSONSchemaEngine - validates the MTA descriptor against the JSON schema which is used by the IDE (lsp/schema.json)
+	JSONSchemaEngine = "json-schema"
+)
+
+// ValidateOptions - the options of the validation
+type ValidateOptions struct {
+	// SchemaEngine - the engine of the MTA descriptor schema validation; the default is KwalifySchemaEngine.
+	// The MTA extension descriptors are always validated against the kwalify schemas.
+	SchemaEngine string
+}
+
 type ValidationResult map[string][]FileValidationIssue
 type FileValidationIssue struct {
 	// Severity - the severity of the message. Possible values: "error", "warning".
@@ -29,25 +44,42 @@ type FileValidationIssue struct {
 	Line int `json:"line"`
 	// Column - the column number of the issue
 	Column int `json:"column"`
+	// Rule - the ID of the rule which reported the issue
+	Rule string `json:"rule"`
 }
 
 // Validate validates an mta.yaml file and a list of mta extension files, and returns the issues for each file.
 func Validate(mtaPath string, extensions []string) ValidationResult {
+	result, _ := ValidateWithOptions(mtaPath, extensions, ValidateOptions{})
+	return result
+}
+
+// ValidateWithOptions validates an mta.yaml file and a list of mta extension files with the options, and returns the
+// issues for each file. An error is returned when the options are not valid.
+func ValidateWithOptions(mtaPath string, extensions []string, options ValidateOptions) (ValidationResult, error) {
+	schemaEngine, err := getSchemaEngine(options.SchemaEngine)
+	if err != nil {
+		return nil, err
+	}
 	allIssues := make(ValidationResult)
 	// Assuming the project path is the folder of the mta.yaml
 	projectPath := filepath.Dir(mtaPath)
 	mtaYamlFileName := filepath.Base(mtaPath)
 	// Paths validation is excluded because it doesn't prevent building the MTA (the paths can be created during the build)
-	errorIssues, warningIssues, e := validateMtaYaml(projectPath, mtaYamlFileName, true, true, true, pathsValidation)
+	errorIssues, warningIssues, e := validateMtaYaml(projectPath, mtaYamlFileName, true, true, true, pathsValidation, schemaEngine)
 	if e != nil {
-		errorIssues = appendIssue(errorIssues, e.Error(), 0, 0)
+		errorIssues = appendRuleIssue(errorIssues, parseRule, e.Error(), 0, 0)
 	}
 	allIssues[mtaPath] = createFileIssues(warningIssues, errorIssues)
+	config, configIssues := loadValidationConfig(projectPath)
+	if len(configIssues) > 0 {
+		allIssues[filepath.Join(projectPath, ConfigFileName)] = createFileIssues(nil, configIssues)
+	}
 
 	for _, extPath := range extensions {
 		errorIssues, warningIssues, e = validateMtaext(projectPath, extPath, true, true, true, "")
 		if e != nil {
-			errorIssues = appendIssue(errorIssues, e.Error(), 0, 0)
+			errorIssues = appendRuleIssue(errorIssues, parseRule, e.Error(), 0, 0)
 		}
 		allIssues[extPath] = createFileIssues(warningIssues, errorIssues)
 	}
@@ -57,20 +89,37 @@ func Validate(mtaPath string, extensions []string) ValidationResult {
 		// Ignore errors which are not on a specific extension (if they are on the mta.yaml we already got them earlier)
 		// and parse errors from extensions (we already got them earlier too)
 		if extErr, ok := e.(*mta.ExtensionError); ok && !extErr.IsParseError {
-			allIssues[extErr.FileName] = append(allIssues[extErr.FileName], FileValidationIssue{SeverityError, e.Error(), 0, 0})
+			allIssues[extErr.FileName] = append(allIssues[extErr.FileName], FileValidationIssue{SeverityError, e.Error(), 0, 0, mergeRule})
+		}
+	} else if len(extensions) > 0 {
+		// The policies with extensions are checked against the merged descriptors
+		for path, issues := range checkExtensionPolicies(projectPath, mtaPath, extensions, config) {
+			allIssues[path] = append(allIssues[path], createFileIssues(issues.warnings, issues.errors)...)
 		}
 	}
 
-	return allIssues
+	return allIssues, nil
+}
+
+// getSchemaEngine returns the schema engine; the kwalify engine is the default
+func getSchemaEngine(schemaEngine string) (string, error) {
+	switch schemaEngine {
+	case "":
+		return KwalifySchemaEngine, nil
+	case KwalifySchemaEngine, JSONSchemaEngine:
+		return schemaEngine, nil
+	}
+	return "", fmt.Errorf(`the "%s" schema engine is incorrect; expected one of the following: %s, %s`,
+		schemaEngine, KwalifySchemaEngine, JSONSchemaEngine)
 }
 
 func createFileIssues(warningIssues YamlValidationIssues, errorIssues YamlValidationIssues) []FileValidationIssue {
 	allIssues := make([]FileValidationIssue, 0)
 	for _, issue := range errorIssues {
-		allIssues = append(allIssues, FileValidationIssue{SeverityError, issue.Msg, issue.Line, issue.Column})
+		allIssues = append(allIssues, FileValidationIssue{SeverityError, issue.Msg, issue.Line, issue.Column, issue.Rule})
 	}
 	for _, issue := range warningIssues {
-		allIssues = append(allIssues, FileValidationIssue{SeverityWarning, issue.Msg, issue.Line, issue.Column})
+		allIssues = append(allIssues, FileValidationIssue{SeverityWarning, issue.Msg, issue.Line, issue.Column, issue.Rule})
 	}
 
 	return allIssues
@@ -94,19 +143,19 @@ func GetValidationMode(validationFlag string) (bool, bool, error) {
 // MtaYaml validates an MTA.yaml file.
 func MtaYaml(projectPath, mtaFilename string,
 	validateSchema, validateSemantic, strict bool, exclude string) (warning string, err error) {
-	errIssues, warnIssues, err := validateMtaYaml(projectPath, mtaFilename, validateSchema, validateSemantic, strict, exclude)
+	errIssues, warnIssues, err := validateMtaYaml(projectPath, mtaFilename, validateSchema, validateSemantic, strict, exclude, KwalifySchemaEngine)
 	if err != nil {
 		return "", err
 	}
 	if len(errIssues) > 0 {
-		return warnIssues.String(), errors.Errorf(`the %q file is not valid: `+"\n%v",
-			filepath.Join(projectPath, mtaFilename), errIssues.String())
+		return warnIssues.String(), withConfigIssues(projectPath, errors.Errorf(`the %q file is not valid: `+"\n%v",
+			filepath.Join(projectPath, mtaFilename), errIssues.String()))
 	}
-	return warnIssues.String(), nil
+	return warnIssues.String(), withConfigIssues(projectPath, nil)
 }
 
 func validateMtaYaml(projectPath, mtaFilename string, validateSchema, validateSemantic, strict bool,
-	exclude string) (errIssues YamlValidationIssues, warnIssues YamlValidationIssues, err error) {
+	exclude string, schemaEngine string) (errIssues YamlValidationIssues, warnIssues YamlValidationIssues, err error) {
 	if validateSemantic || validateSchema {
 		mtaPath := filepath.Join(projectPath, mtaFilename)
 		// ParseFile contains MTA yaml content.
@@ -115,8 +164,14 @@ func validateMtaYaml(projectPath, mtaFilename string, validateSchema, validateSe
 			return nil, nil, errors.Wrapf(e, `could not read the %q file; the validation failed`, mtaPath)
 		}
 
+		// The issues of the configuration file are reported on the configuration file
+		config, _ := loadValidationConfig(projectPath)
+
 		// Validates MTA content.
-		errIssues, warnIssues := validate(yamlContent, projectPath, validateSchema, validateSemantic, strict, exclude)
+		errIssues, warnIssues := validateWithSchemaEngine(yamlContent, projectPath, validateSchema, validateSemantic, strict,
+			config.excludeDisabled(exclude), schemaEngine)
+		errIssues, warnIssues = suppressIssues(yamlContent, config.excludeDisabled(exclude), errIssues, warnIssues)
+		errIssues, warnIssues = config.applyToContent(yamlContent, false, errIssues, warnIssues)
 		errIssues.Sort()
 		warnIssues.Sort()
 		return errIssues, warnIssues, nil
@@ -125,9 +180,15 @@ func validateMtaYaml(projectPath, mtaFilename string, validateSchema, validateSe
 	return nil, nil, nil
 }
 
-// validate - validates the MTA descriptor
+// validate - validates the MTA descriptor against the kwalify schemas
 func validate(yamlContent []byte, projectPath string,
 	validateSchema, validateSemantic, strict bool, exclude string) (errIssues YamlValidationIssues, warnIssues YamlValidationIssues) {
+	return validateWithSchemaEngine(yamlContent, projectPath, validateSchema, validateSemantic, strict, exclude, KwalifySchemaEngine)
+}
+
+// validateWithSchemaEngine - validates the MTA descriptor with the schema engine
+func validateWithSchemaEngine(yamlContent []byte, projectPath string, validateSchema, validateSemantic, strict bool,
+	exclude string, schemaEngine string) (errIssues YamlValidationIssues, warnIssues YamlValidationIssues) {
 
 	mtaStr, err := mta.Unmarshal(yamlContent)
 
@@ -143,21 +204,36 @@ func validate(yamlContent []byte, projectPath string,
 	}
 
 	if validateSchema {
-		validations, schemaValidationLog := buildValidationsFromSchemaText(schemaDef)
-		if len(schemaValidationLog) > 0 {
-			errIssues = append(errIssues, schemaValidationLog...)
-			return errIssues, warnIssues
+		if schemaEngine == JSONSchemaEngine {
+			schema, err := getMTAJSONSchema()
+			if err != nil {
+				errIssues = appendRuleIssue(errIssues, schemaDefinitionRule, err.Error(), 0, 0)
+				return errIssues, warnIssues
+			}
+			errIssues = append(errIssues, schema.validate(mtaNode)...)
+		} else {
+			version, versionErrors, versionWarnings := selectSchemaVersion(mtaNode)
+			errIssues = append(errIssues, versionErrors...)
+			warnIssues = append(warnIssues, versionWarnings...)
+			validations, schemaValidationLog := buildValidationsFromSchemaText(getSchemaDef(version, false))
+			if len(schemaValidationLog) > 0 {
+				errIssues = append(errIssues, schemaValidationLog...)
+				return errIssues, warnIssues
+			}
+			errIssues = append(errIssues, runSchemaValidations(mtaNode, validations...)...)
 		}
-		errIssues = append(errIssues, runSchemaValidations(mtaNode, validations...)...)
 
-		issues := checkBuilderSchema(mtaStr, mtaNode, "")
-		if strict {
-			errIssues = append(errIssues, issues...)
-		} else {
-			warnIssues = append(warnIssues, issues...)
+		// The builder issues have the rule of the builders semantic validation, so they are excluded with it
+		if !getExcludedValidations(exclude)[buildersValidation] {
+			issues := checkBuilderSchema(mtaStr, mtaNode, "")
+			if strict {
+				errIssues = append(errIssues, issues...)
+			} else {
+				warnIssues = append(warnIssues, issues...)
+			}
 		}
 
-		issues = checkMetadataSchema(mtaStr, mtaNode, "")
+		issues := checkMetadataSchema(mtaStr, mtaNode, "")
 		if strict {
 			errIssues = append(errIssues, issues...)
 		} else {
@@ -167,6 +243,7 @@ func validate(yamlContent []byte, projectPath string,
 
 	if validateSemantic {
 		errs, warns := runSemanticValidations(mtaStr, mtaNode, projectPath, exclude, strict)
+		errIssues = removeCoveredUniquenessIssues(errIssues, errs, warns)
 		errIssues = append(errIssues, errs...)
 		warnIssues = append(warnIssues, warns...)
 	}
@@ -204,7 +281,7 @@ func convertError(err error) []YamlValidationIssue {
 			}
 
 			// Add converted issue to the issues list. We only have the line number here.
-			issues = appendIssue(issues, e, line, 0)
+			issues = appendRuleIssue(issues, parseRule, e, line, 0)
 		}
 	}
 	return issues
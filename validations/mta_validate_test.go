@@ -106,6 +106,7 @@ var _ = Describe("MTA tests", func() {
 						"Message":  ContainSubstring(fs.PathNotFoundMsg, mtaYamlPath),
 						"Line":     Equal(0),
 						"Column":   Equal(0),
+						"Rule":     Equal("parse"),
 					})),
 				}))
 			})
@@ -121,6 +122,7 @@ var _ = Describe("MTA tests", func() {
 						"Message":  ContainSubstring(fs.PathNotFoundMsg, mtaExtPath),
 						"Line":     Equal(0),
 						"Column":   Equal(0),
+						"Rule":     Equal("parse"),
 					})),
 				}))
 			})
@@ -136,18 +138,21 @@ var _ = Describe("MTA tests", func() {
 							"Message":  ContainSubstring("path"),
 							"Line":     Equal(17),
 							"Column":   Equal(4),
+							"Rule":     Equal("emptyPath"),
 						}),
 						MatchAllFields(Fields{
 							"Severity": Equal("error"),
 							"Message":  ContainSubstring("type1"),
 							"Line":     Equal(30),
 							"Column":   Equal(0), // Unmarhshal errors return without a column
+							"Rule":     Equal("parse"),
 						}),
 						MatchAllFields(Fields{
 							"Severity": Equal("error"),
 							"Message":  ContainSubstring("abc"),
 							"Line":     Equal(28),
 							"Column":   Equal(0), // Unmarhshal errors return without a column
+							"Rule":     Equal("parse"),
 						}),
 					),
 					mtaExtPath: ConsistOf(MatchAllFields(Fields{
@@ -155,6 +160,7 @@ var _ = Describe("MTA tests", func() {
 						"Message":  ContainSubstring("type"),
 						"Line":     Equal(16),
 						"Column":   Equal(0),
+						"Rule":     Equal("parse"),
 					})),
 				}))
 			})
@@ -170,6 +176,7 @@ var _ = Describe("MTA tests", func() {
 						"Message":  ContainSubstring("ui5app3"),
 						"Line":     Equal(0), // We don't return the location for merge errors
 						"Column":   Equal(0),
+						"Rule":     Equal("merge"),
 					})),
 				}))
 			})
@@ -188,6 +195,7 @@ var _ = Describe("MTA tests", func() {
 							"Message":  ContainSubstring("dest_mtahtml5"),
 							"Line":     Equal(14),
 							"Column":   Equal(13),
+							"Rule":     Equal("required"),
 						}),
 						// Duplicated resource name
 						MatchAllFields(Fields{
@@ -195,6 +203,7 @@ var _ = Describe("MTA tests", func() {
 							"Message":  ContainSubstring("uaa_mtahtml5"),
 							"Line":     Equal(18),
 							"Column":   Equal(10),
+							"Rule":     Equal("names"),
 						}),
 					),
 					mtaExtPath1: ConsistOf(
@@ -205,6 +214,7 @@ var _ = Describe("MTA tests", func() {
 							"Message":  ContainSubstring("ui5app"),
 							"Line":     Equal(12),
 							"Column":   Equal(10),
+							"Rule":     Equal("names"),
 						}),
 					),
 					mtaExtPath2: ConsistOf(
@@ -213,6 +223,7 @@ var _ = Describe("MTA tests", func() {
 							"Message":  ContainSubstring("mtahtml5_unknown"),
 							"Line":     Equal(0),
 							"Column":   Equal(0),
+							"Rule":     Equal("merge"),
 						}),
 					),
 				}))
@@ -288,8 +299,8 @@ var _ = Describe("MTA tests", func() {
 			})
 
 			It("invalid schema", func() {
-				originalSchema := schemaDef
-				schemaDef = []byte(`
+				originalSchema := schemaDefV31
+				schemaDefV31 = []byte(`
 desc: MTA DESCRIPTOR SCHEMA
 # schema version must be extracted from here as there is no "version" element available to version schemas
   name: com.sap.mta.mta-schema_3.2.0 abc
@@ -297,7 +308,7 @@ desc: MTA DESCRIPTOR SCHEMA
 				_, err := MtaYaml(getTestPath("testproject"), "mta.yaml",
 					true, false, true, "")
 				Ω(err).Should(HaveOccurred())
-				schemaDef = originalSchema
+				schemaDefV31 = originalSchema
 			})
 		})
 
@@ -335,12 +346,12 @@ modules:
 					true, false, true, "")
 				Ω(warn).Should(BeNil())
 				Ω(err).Should(ConsistOf(
-					YamlValidationIssue{"cannot unmarshal !!str `abc` into bool", 10, 0},
-					YamlValidationIssue{`the "parameters-metadata.param1.overwritable" property must be a boolean`, 10, 19},
-					YamlValidationIssue{"cannot unmarshal !!int `12` into bool", 19, 0},
-					YamlValidationIssue{`the "modules[0].parameters-metadata.memory.optional" property must be a boolean`, 19, 17},
-					YamlValidationIssue{"cannot unmarshal !!str `is it?` into bool", 25, 0},
-					YamlValidationIssue{`the "some_type" value of the "modules[0].properties-metadata.a.datatype" enum property is invalid; expected one of the following: str,int,float,bool`, 26, 17},
+					YamlValidationIssue{Msg: "cannot unmarshal !!str `abc` into bool", Line: 10, Column: 0, Rule: parseRule},
+					YamlValidationIssue{Msg: `the "parameters-metadata.param1.overwritable" property must be a boolean`, Line: 10, Column: 19, Rule: propertyTypeRule},
+					YamlValidationIssue{Msg: "cannot unmarshal !!int `12` into bool", Line: 19, Column: 0, Rule: parseRule},
+					YamlValidationIssue{Msg: `the "modules[0].parameters-metadata.memory.optional" property must be a boolean`, Line: 19, Column: 17, Rule: propertyTypeRule},
+					YamlValidationIssue{Msg: "cannot unmarshal !!str `is it?` into bool", Line: 25, Column: 0, Rule: parseRule},
+					YamlValidationIssue{Msg: `the "some_type" value of the "modules[0].properties-metadata.a.datatype" enum property is invalid; expected one of the following: str,int,float,bool`, Line: 26, Column: 17, Rule: enumRule},
 				))
 			})
 
@@ -414,16 +425,58 @@ resources:
 
 				datatypeNotAllowedForParametersMetadata := fmt.Sprintf(propertyExistsErrorMsg, datatypeYamlField, parametersMetadataField)
 				Ω(err).Should(ConsistOf(
-					YamlValidationIssue{datatypeNotAllowedForParametersMetadata, 10, 5},
-					YamlValidationIssue{datatypeNotAllowedForParametersMetadata, 22, 7},
-					YamlValidationIssue{datatypeNotAllowedForParametersMetadata, 33, 7},
+					YamlValidationIssue{Msg: datatypeNotAllowedForParametersMetadata, Line: 10, Column: 5, Rule: notAllowedRule},
+					YamlValidationIssue{Msg: datatypeNotAllowedForParametersMetadata, Line: 22, Column: 7, Rule: notAllowedRule},
+					YamlValidationIssue{Msg: datatypeNotAllowedForParametersMetadata, Line: 33, Column: 7, Rule: notAllowedRule},
 				))
 			})
 
 		})
+
+		Context("uniqueness validations", func() {
+			content := []byte(`
+ID: mta
+_schema-version: '3.3'
+version: 0.0.1
+
+modules:
+- name: srv
+  type: nodejs
+  requires:
+  - name: db
+  - name: db
+- name: srv
+  type: nodejs
+resources:
+- name: db
+`)
+			It("reports the duplicate names of the schema validation", func() {
+				err, _ := validate(content, getTestPath("mtahtml5"), true, false, true, "")
+				Ω(err).Should(ConsistOf(
+					YamlValidationIssue{Msg: fmt.Sprintf(notUniqueValueMsg, "db", "modules[0].requires[1].name", 10), Line: 11, Column: 11, Rule: uniqueRule},
+					YamlValidationIssue{Msg: fmt.Sprintf(notUniqueValueMsg, "srv", "modules[1].name", 7), Line: 12, Column: 9, Rule: uniqueRule},
+				))
+			})
+
+			It("reports the duplicate names of the semantic validation instead of the same schema issues", func() {
+				err, _ := validate(content, getTestPath("mtahtml5"), true, true, true, pathsValidation+","+emptyPathValidation+","+requiredValidation)
+				Ω(err).Should(ConsistOf(
+					YamlValidationIssue{Msg: fmt.Sprintf(notUniqueValueMsg, "db", "modules[0].requires[1].name", 10), Line: 11, Column: 11, Rule: uniqueRule},
+					YamlValidationIssue{Msg: `the "srv" module name is already in use; another module was found with the same name on line 7`, Line: 12, Column: 9, Rule: namesValidation},
+				))
+			})
+
+			It("reports the duplicate names of the schema validation when the names validation is excluded", func() {
+				err, _ := validate(content, getTestPath("mtahtml5"), true, true, true, pathsValidation+","+emptyPathValidation+","+requiredValidation+","+namesValidation)
+				Ω(err).Should(ConsistOf(
+					YamlValidationIssue{Msg: fmt.Sprintf(notUniqueValueMsg, "db", "modules[0].requires[1].name", 10), Line: 11, Column: 11, Rule: uniqueRule},
+					YamlValidationIssue{Msg: fmt.Sprintf(notUniqueValueMsg, "srv", "modules[1].name", 7), Line: 12, Column: 9, Rule: uniqueRule},
+				))
+			})
+		})
 	})
 
 	It("convertError", func() {
-		Ω(convertError(fmt.Errorf("line 999999999999999999999999999: aaa"))).Should(BeEquivalentTo([]YamlValidationIssue{{Msg: "aaa", Line: 1, Column: 0}}))
+		Ω(convertError(fmt.Errorf("line 999999999999999999999999999: aaa"))).Should(BeEquivalentTo([]YamlValidationIssue{{Msg: "aaa", Line: 1, Column: 0, Rule: parseRule}}))
 	})
 })
@@ -0,0 +1,313 @@
+package validate
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/SAP/cloud-mta/internal/version"
+)
+
+// The output formats of the validation result
+const (
+	// JSONFormat - the validation result as JSON
+	JSONFormat = "json"
+	// SARIFFormat - a SARIF 2.1.0 log, e.g. for code scanning tools
+	SARIFFormat = "sarif"
+	// JUnitFormat - a JUnit XML report, in which each file is a test suite and each error is a failed test case
+	JUnitFormat = "junit"
+	// CheckstyleFormat - a checkstyle XML report
+	CheckstyleFormat = "checkstyle"
+	// GitHubFormat - GitHub Actions workflow commands, which annotate the files
+	GitHubFormat = "github"
+)
+
+const (
+	toolName           = "cloud-mta"
+	toolInformationURI = "https://github.com/SAP/cloud-mta"
+	sarifSchema        = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion       = "2.1.0"
+	checkstyleVersion  = "4.3"
+)
+
+// FormatResult returns the validation result in the output format
+func FormatResult(result ValidationResult, format string) (string, error) {
+	switch format {
+	case JSONFormat:
+		return marshalJSON(result)
+	case SARIFFormat:
+		return formatSARIF(result)
+	case JUnitFormat:
+		return formatJUnit(result)
+	case CheckstyleFormat:
+		return formatCheckstyle(result)
+	case GitHubFormat:
+		return formatGitHub(result), nil
+	}
+	return "", fmt.Errorf(`the "%s" output format is incorrect; expected one of the following: %s`, format,
+		strings.Join([]string{JSONFormat, SARIFFormat, JUnitFormat, CheckstyleFormat, GitHubFormat}, ", "))
+}
+
+// sortedFiles returns the files of the result in alphabetical order, so the output is stable
+func sortedFiles(result ValidationResult) []string {
+	files := make([]string, 0, len(result))
+	for file := range result {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// relativePath returns the path relative to the working directory, with slashes, when the file is in it
+func relativePath(path string) string {
+	if wd, err := os.Getwd(); err == nil && filepath.IsAbs(path) {
+		if rel, err := filepath.Rel(wd, path); err == nil && !strings.HasPrefix(rel, "..") {
+			path = rel
+		}
+	}
+	return filepath.ToSlash(path)
+}
+
+func marshalJSON(v interface{}) (string, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Version        string      `json:"version,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId,omitempty"`
+	RuleIndex *int            `json:"ruleIndex,omitempty"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+func formatSARIF(result ValidationResult) (string, error) {
+	driver := sarifDriver{Name: toolName, InformationURI: toolInformationURI, Rules: []sarifRule{}}
+	if v, err := version.GetVersion(); err == nil {
+		driver.Version = v.CliVersion
+	}
+	ruleIndexes := make(map[string]int)
+	for i, rule := range Rules() {
+		driver.Rules = append(driver.Rules, sarifRule{ID: rule.ID, ShortDescription: sarifMessage{rule.Description}})
+		ruleIndexes[rule.ID] = i
+	}
+
+	run := sarifRun{Tool: sarifTool{Driver: driver}, Results: []sarifResult{}}
+	for _, file := range sortedFiles(result) {
+		for _, issue := range result[file] {
+			location := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: relativePath(file)}}
+			// SARIF lines and columns start from 1; issues without a position are reported on the file
+			if issue.Line > 0 {
+				location.Region = &sarifRegion{StartLine: issue.Line}
+				if issue.Column > 0 {
+					location.Region.StartColumn = issue.Column
+				}
+			}
+			sarifIssue := sarifResult{
+				RuleID:    issue.Rule,
+				Level:     issue.Severity,
+				Message:   sarifMessage{issue.Message},
+				Locations: []sarifLocation{{location}},
+			}
+			// The index is omitted for issues of unknown rules, since it must reference a rule of the driver
+			if index, ok := ruleIndexes[issue.Rule]; ok {
+				sarifIssue.RuleIndex = &index
+			}
+			run.Results = append(run.Results, sarifIssue)
+		}
+	}
+	return marshalJSON(sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{run}})
+}
+
+type junitTestSuites struct {
+	XMLName    xml.Name         `xml:"testsuites"`
+	Name       string           `xml:"name,attr"`
+	Tests      int              `xml:"tests,attr"`
+	Failures   int              `xml:"failures,attr"`
+	TestSuites []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// formatJUnit reports each issue as a test case of the file's test suite. Errors are failures; warnings are passed
+// test cases with the issue in their output. Files without issues have a single passed test case.
+func formatJUnit(result ValidationResult) (string, error) {
+	suites := junitTestSuites{Name: toolName}
+	for _, file := range sortedFiles(result) {
+		path := relativePath(file)
+		suite := junitTestSuite{Name: path}
+		for _, issue := range result[file] {
+			location := fmt.Sprintf("%s:%d:%d", path, issue.Line, issue.Column)
+			testCase := junitTestCase{Name: fmt.Sprintf("%s (%s)", issue.Rule, location), ClassName: path}
+			text := fmt.Sprintf("%s: %s: %s", location, issue.Severity, issue.Message)
+			if issue.Severity == SeverityError {
+				testCase.Failure = &junitFailure{Message: issue.Message, Type: issue.Rule, Text: text}
+				suite.Failures++
+			} else {
+				testCase.SystemOut = text
+			}
+			suite.TestCases = append(suite.TestCases, testCase)
+		}
+		if len(suite.TestCases) == 0 {
+			suite.TestCases = append(suite.TestCases, junitTestCase{Name: "validate", ClassName: path})
+		}
+		suite.Tests = len(suite.TestCases)
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.TestSuites = append(suites.TestSuites, suite)
+	}
+	return marshalXML(suites)
+}
+
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+func formatCheckstyle(result ValidationResult) (string, error) {
+	report := checkstyleReport{Version: checkstyleVersion}
+	for _, file := range sortedFiles(result) {
+		checkstyleFile := checkstyleFile{Name: relativePath(file)}
+		for _, issue := range result[file] {
+			checkstyleFile.Errors = append(checkstyleFile.Errors, checkstyleError{
+				Line:     issue.Line,
+				Column:   issue.Column,
+				Severity: issue.Severity,
+				Message:  issue.Message,
+				Source:   toolName + "." + issue.Rule,
+			})
+		}
+		report.Files = append(report.Files, checkstyleFile)
+	}
+	return marshalXML(report)
+}
+
+func marshalXML(v interface{}) (string, error) {
+	content, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(content) + "\n", nil
+}
+
+// formatGitHub returns a workflow command for each issue, e.g.
+//
+//	::error file=mta.yaml,line=3,col=5,title=pattern::the "my app" value of the "ID" property does not match ...
+func formatGitHub(result ValidationResult) string {
+	var buf bytes.Buffer
+	for _, file := range sortedFiles(result) {
+		for _, issue := range result[file] {
+			properties := []string{"file=" + escapeGitHubProperty(relativePath(file))}
+			if issue.Line > 0 {
+				properties = append(properties, fmt.Sprintf("line=%d", issue.Line))
+				if issue.Column > 0 {
+					properties = append(properties, fmt.Sprintf("col=%d", issue.Column))
+				}
+			}
+			properties = append(properties, "title="+escapeGitHubProperty(issue.Rule))
+			buf.WriteString(fmt.Sprintf("::%s %s::%s\n", issue.Severity, strings.Join(properties, ","),
+				escapeGitHubData(issue.Message)))
+		}
+	}
+	return buf.String()
+}
+
+func escapeGitHubData(value string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(value)
+}
+
+func escapeGitHubProperty(value string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(value)
+}
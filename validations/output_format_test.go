@@ -0,0 +1,142 @@
+package validate
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Output formats", func() {
+	mtaPath := filepath.Join("testdata", "mta.yaml")
+	extPath := filepath.Join("testdata", "my.mtaext")
+	result := ValidationResult{
+		mtaPath: {
+			{Severity: SeverityError, Rule: namesValidation, Message: `the "a" module name is not unique`, Line: 3, Column: 5},
+			{Severity: SeverityWarning, Rule: deprecatedOptsValidation, Message: "deprecated, use 100%,\nnot 50%", Line: 8, Column: 7},
+		},
+		extPath: {},
+	}
+
+	It("reports the rules of the issues", func() {
+		errIssues, _ := validate([]byte(`
+modules:
+  - name: a
+    type: html5
+    path: a
+  - name: a
+    type: html5
+    path: a
+`), getTestPath("testproject"), true, true, true, "")
+		var rules []string
+		for _, issue := range errIssues {
+			rules = append(rules, issue.Rule)
+		}
+		Ω(rules).Should(ContainElement(requiredPropertyRule))
+		Ω(rules).Should(ContainElement(namesValidation))
+		Ω(convertError(errors.New("yaml: line 3: did not find expected key"))[0].Rule).Should(Equal(parseRule))
+	})
+
+	It("lists the rules of the semantic validations", func() {
+		ids := make(map[string]bool)
+		for _, rule := range Rules() {
+			Ω(ids).ShouldNot(HaveKey(rule.ID))
+			ids[rule.ID] = true
+		}
+		for _, name := range []string{pathsValidation, namesValidation, requiredValidation, envVarCollisionsValidation} {
+			Ω(ids).Should(HaveKey(name))
+		}
+	})
+
+	It("formats as JSON", func() {
+		output, err := FormatResult(result, JSONFormat)
+		Ω(err).Should(Succeed())
+		var parsed ValidationResult
+		Ω(json.Unmarshal([]byte(output), &parsed)).Should(Succeed())
+		Ω(parsed[mtaPath]).Should(Equal(result[mtaPath]))
+	})
+
+	It("formats as SARIF", func() {
+		output, err := FormatResult(result, SARIFFormat)
+		Ω(err).Should(Succeed())
+		var log sarifLog
+		Ω(json.Unmarshal([]byte(output), &log)).Should(Succeed())
+		Ω(log.Version).Should(Equal("2.1.0"))
+		Ω(log.Runs).Should(HaveLen(1))
+		Ω(log.Runs[0].Tool.Driver.Name).Should(Equal("cloud-mta"))
+		Ω(log.Runs[0].Tool.Driver.Rules).Should(HaveLen(len(Rules())))
+		results := log.Runs[0].Results
+		Ω(results).Should(HaveLen(2))
+		Ω(results[0].RuleID).Should(Equal(namesValidation))
+		Ω(log.Runs[0].Tool.Driver.Rules[*results[0].RuleIndex].ID).Should(Equal(namesValidation))
+		Ω(results[0].Level).Should(Equal("error"))
+		Ω(results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI).Should(Equal("testdata/mta.yaml"))
+		Ω(*results[0].Locations[0].PhysicalLocation.Region).Should(Equal(sarifRegion{StartLine: 3, StartColumn: 5}))
+		Ω(results[1].Level).Should(Equal("warning"))
+	})
+
+	It("formats SARIF issues without a position on the file", func() {
+		output, err := FormatResult(ValidationResult{mtaPath: {{Severity: SeverityError, Rule: parseRule, Message: "x"}}}, SARIFFormat)
+		Ω(err).Should(Succeed())
+		var log sarifLog
+		Ω(json.Unmarshal([]byte(output), &log)).Should(Succeed())
+		Ω(log.Runs[0].Results[0].Locations[0].PhysicalLocation.Region).Should(BeNil())
+	})
+
+	It("omits the rule index of SARIF issues of unknown rules", func() {
+		output, err := FormatResult(ValidationResult{mtaPath: {{Severity: SeverityError, Rule: "unknown", Message: "x"}}}, SARIFFormat)
+		Ω(err).Should(Succeed())
+		Ω(output).ShouldNot(ContainSubstring("ruleIndex"))
+		var log sarifLog
+		Ω(json.Unmarshal([]byte(output), &log)).Should(Succeed())
+		Ω(log.Runs[0].Results[0].RuleID).Should(Equal("unknown"))
+		Ω(log.Runs[0].Results[0].RuleIndex).Should(BeNil())
+	})
+
+	It("formats as JUnit", func() {
+		output, err := FormatResult(result, JUnitFormat)
+		Ω(err).Should(Succeed())
+		var suites junitTestSuites
+		Ω(xml.Unmarshal([]byte(output), &suites)).Should(Succeed())
+		Ω(suites.Tests).Should(Equal(3))
+		Ω(suites.Failures).Should(Equal(1))
+		Ω(suites.TestSuites).Should(HaveLen(2))
+		Ω(suites.TestSuites[0].Name).Should(Equal("testdata/mta.yaml"))
+		Ω(suites.TestSuites[0].TestCases[0].Failure.Type).Should(Equal(namesValidation))
+		Ω(suites.TestSuites[0].TestCases[1].Failure).Should(BeNil())
+		Ω(suites.TestSuites[0].TestCases[1].SystemOut).Should(ContainSubstring("testdata/mta.yaml:8:7: warning"))
+		Ω(suites.TestSuites[1].Name).Should(Equal("testdata/my.mtaext"))
+		Ω(suites.TestSuites[1].TestCases).Should(HaveLen(1))
+		Ω(suites.TestSuites[1].Failures).Should(Equal(0))
+	})
+
+	It("formats as checkstyle", func() {
+		output, err := FormatResult(result, CheckstyleFormat)
+		Ω(err).Should(Succeed())
+		var report checkstyleReport
+		Ω(xml.Unmarshal([]byte(output), &report)).Should(Succeed())
+		Ω(report.Files).Should(HaveLen(2))
+		Ω(report.Files[0].Errors).Should(Equal([]checkstyleError{
+			{Line: 3, Column: 5, Severity: "error", Message: `the "a" module name is not unique`, Source: "cloud-mta.names"},
+			{Line: 8, Column: 7, Severity: "warning", Message: "deprecated, use 100%,\nnot 50%", Source: "cloud-mta.deprecatedOpts"},
+		}))
+		Ω(report.Files[1].Errors).Should(BeEmpty())
+	})
+
+	It("formats as GitHub annotations", func() {
+		output, err := FormatResult(result, GitHubFormat)
+		Ω(err).Should(Succeed())
+		Ω(output).Should(Equal(
+			"::error file=testdata/mta.yaml,line=3,col=5,title=names::the \"a\" module name is not unique\n" +
+				"::warning file=testdata/mta.yaml,line=8,col=7,title=deprecatedOpts::deprecated, use 100%25,%0Anot 50%25\n"))
+	})
+
+	It("fails when the format is unknown", func() {
+		_, err := FormatResult(result, "xml")
+		Ω(err).Should(MatchError(`the "xml" output format is incorrect; expected one of the following: json, sarif, junit, checkstyle, github`))
+	})
+})
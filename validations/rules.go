@@ -0,0 +1,66 @@
+package validate
+
+// The rule IDs of the validation issues which are not reported by semantic validations.
+// The semantic validations use their names, which can also be excluded, as rule IDs (e.g. "paths").
+const (
+	parseRule            = "parse"
+	mergeRule            = "merge"
+	schemaDefinitionRule = "schemaDefinition"
+	schemaVersionRule    = "schemaVersion"
+	requiredPropertyRule = "requiredProperty"
+	propertyTypeRule     = "propertyType"
+	patternRule          = "pattern"
+	enumRule             = "enum"
+	uniqueRule           = "unique"
+	rangeRule            = "range"
+	lengthRule           = "length"
+	notAllowedRule       = "notAllowed"
+	schemaCompositeRule  = "schemaComposite"
+	suppressionRule      = "suppression"
+	configRule           = "config"
+)
+
+// Rule - a validation rule
+type Rule struct {
+	// ID - the stable identifier of the rule, which is reported with its issues
+	ID string `json:"id"`
+	// Description - what the rule validates
+	Description string `json:"description"`
+}
+
+// rules - the validation rules, in the order in which they are listed in reports
+var rules = []Rule{
+	{parseRule, "The MTA descriptor can be read and parsed"},
+	{mergeRule, "The MTA extension descriptors can be merged into the MTA descriptor"},
+	{schemaDefinitionRule, "The schema against which the descriptor is validated is valid"},
+	{schemaVersionRule, "The schema version of the descriptor is supported"},
+	{requiredPropertyRule, "The required properties are defined"},
+	{propertyTypeRule, "The properties have the types of the schema"},
+	{patternRule, "The values match the patterns of the schema"},
+	{enumRule, "The values are among the allowed values of the schema"},
+	{uniqueRule, "The values which must be unique in a list are unique"},
+	{rangeRule, "The numeric values are in the ranges of the schema"},
+	{lengthRule, "The values and lists have the lengths of the schema"},
+	{notAllowedRule, "Only the allowed properties are defined"},
+	{schemaCompositeRule, "The values match the combined schemas"},
+	{pathsValidation, "The paths of the modules exist"},
+	{emptyPathValidation, "The paths of the modules are defined"},
+	{namesValidation, "The names of the modules, provided property sets and resources are unique"},
+	{requiredValidation, "The required property sets and properties are provided"},
+	{buildersValidation, "The builders are configured correctly"},
+	{deprecatedOptsValidation, "Deprecated build options are not used"},
+	{deployerConstrValidation, "The modules meet the constraints of the deployer"},
+	{metadataValidation, "The parameters and properties metadata is consistent"},
+	{ifNoSourceParamBoolValidation, `The "no-source" build parameter is a boolean`},
+	{envVarCollisionsValidation, "The environment variables of the modules are defined only once"},
+	{envVarNamesValidation, "The names which are used as environment variable names are safe and unique in upper case"},
+	{deploymentOrderValidation, `The "deployed-after" and "processed-after" lists reference other defined modules and resources without cycles`},
+	{policyValidation, "The modules and resources comply with the policies of the project's policy file"},
+	{suppressionRule, "The suppression comments suppress issues of known rules"},
+	{configRule, "The validation configuration file of the project can be read and is valid"},
+}
+
+// Rules returns the validation rules, including the registered semantic rules
+func Rules() []Rule {
+	return append(append([]Rule{}, rules...), getCustomRules()...)
+}
@@ -13,6 +13,7 @@ import (
 
 const (
 	propertyExistsErrorMsg = `the "%s" key is not allowed inside the "%s"`
+	notUniqueValueMsg      = `the "%s" value of the "%s" property is not unique; it is already used on line %d`
 )
 
 // YamlValidationIssue - specific issue
@@ -23,6 +24,8 @@ type YamlValidationIssue struct {
 	Line int
 	// Column - column number of the issue
 	Column int
+	// Rule - the ID of the rule which reported the issue
+	Rule string
 }
 
 // YamlValidationIssues - list of issue's
@@ -238,6 +241,105 @@ func forEachProperty(checks ...YamlCheck) YamlCheck {
 	}
 }
 
+// DSL method to iterate over the values of the YAML map properties which are not in the names list.
+// Used for the default rule ("=") of a mapping which also defines rules for specific properties.
+func forEachOtherProperty(names []string, checks ...YamlCheck) YamlCheck {
+	validation := forEachProperty(checks...)
+
+	return func(yPropNode, yParentNode *yaml.Node, path []string) YamlValidationIssues {
+		if yPropNode == nil {
+			return YamlValidationIssues{}
+		}
+
+		otherProps := *yPropNode
+		otherProps.Content = nil
+		for i := 0; i+1 < len(yPropNode.Content); i += 2 {
+			if !containsString(names, yPropNode.Content[i].Value) {
+				otherProps.Content = append(otherProps.Content, yPropNode.Content[i], yPropNode.Content[i+1])
+			}
+		}
+		return validation(&otherProps, yParentNode, path)
+	}
+}
+
+// DSL method to ensure that the values of a property are unique in the items of a YAML array.
+// The issues are reported on the duplicate values.
+func uniqueProperty(propName string) YamlCheck {
+	return func(yNode, yParentNode *yaml.Node, path []string) YamlValidationIssues {
+		var issues YamlValidationIssues
+		if yNode == nil {
+			return issues
+		}
+
+		lines := make(map[string]int)
+		for i, item := range yNode.Content {
+			valueNode := getPropValueByName(item, propName)
+			if valueNode == nil || valueNode.Kind != yaml.ScalarNode || valueNode.Tag == "!!null" {
+				continue
+			}
+			issues = append(issues, checkUniqueValue(lines, valueNode, append(path, fmt.Sprintf("[%d]", i), propName))...)
+		}
+		return issues
+	}
+}
+
+// DSL method to ensure that the scalar items of a YAML array are unique.
+// The issues are reported on the duplicate items.
+func uniqueItems() YamlCheck {
+	return func(yNode, yParentNode *yaml.Node, path []string) YamlValidationIssues {
+		var issues YamlValidationIssues
+		if yNode == nil {
+			return issues
+		}
+
+		lines := make(map[string]int)
+		for i, item := range yNode.Content {
+			if item.Kind != yaml.ScalarNode || item.Tag == "!!null" {
+				continue
+			}
+			issues = append(issues, checkUniqueValue(lines, item, append(path, fmt.Sprintf("[%d]", i)))...)
+		}
+		return issues
+	}
+}
+
+// removeCoveredUniquenessIssues removes the issues of the "unique" constraint on the nodes which have issues of the
+// names validation. The names validation also checks the uniqueness of the names, with more details (e.g. the kind
+// of the duplicate).
+func removeCoveredUniquenessIssues(issues YamlValidationIssues, semanticIssues ...YamlValidationIssues) YamlValidationIssues {
+	positions := make(map[[2]int]bool)
+	for _, list := range semanticIssues {
+		for _, issue := range list {
+			if issue.Rule == namesValidation {
+				positions[[2]int{issue.Line, issue.Column}] = true
+			}
+		}
+	}
+
+	var result YamlValidationIssues
+	for _, issue := range issues {
+		if issue.Rule != uniqueRule || !positions[[2]int{issue.Line, issue.Column}] {
+			result = append(result, issue)
+		}
+	}
+	return result
+}
+
+func checkUniqueValue(lines map[string]int, valueNode *yaml.Node, path []string) YamlValidationIssues {
+	if line, ok := lines[valueNode.Value]; ok {
+		return []YamlValidationIssue{
+			{
+				Msg:    fmt.Sprintf(notUniqueValueMsg, valueNode.Value, buildPathString(path), line),
+				Line:   valueNode.Line,
+				Column: valueNode.Column,
+				Rule:   uniqueRule,
+			},
+		}
+	}
+	lines[valueNode.Value] = valueNode.Line
+	return []YamlValidationIssue{}
+}
+
 // DSL method to ensure a property exists.
 // Note that this has no context, the property being checked is provided externally
 // via the "property" DSL method.
@@ -250,6 +352,7 @@ func required() YamlCheck {
 						last(path), buildPathString(dropRight(path))),
 					Line:   yParentNode.Line,
 					Column: yParentNode.Column,
+					Rule:   requiredPropertyRule,
 				},
 			}
 		}
@@ -272,6 +375,7 @@ func doesNotExist() YamlCheck {
 						last(path), buildPathString(dropRight(path))),
 					Line:   yNode.Line,
 					Column: yNode.Column,
+					Rule:   notAllowedRule,
 				},
 			}
 		}
@@ -313,6 +417,7 @@ func typeIsNotMapArray() YamlCheck {
 					Msg:    fmt.Sprintf(`the "%s" property must be a string`, buildPathString(path)),
 					Line:   yNode.Line,
 					Column: yNode.Column,
+					Rule:   propertyTypeRule,
 				},
 			}
 		}
@@ -331,6 +436,7 @@ func typeIsArray() YamlCheck {
 						Msg:    fmt.Sprintf(`the "%s" property must be an array`, buildPathString(path)),
 						Line:   yNode.Line,
 						Column: yNode.Column,
+						Rule:   propertyTypeRule,
 					},
 				}
 			}
@@ -349,6 +455,7 @@ func typeIsMap() YamlCheck {
 						Msg:    fmt.Sprintf(`the "%s" property must be a map`, buildPathString(path)),
 						Line:   yNode.Line,
 						Column: yNode.Column,
+						Rule:   propertyTypeRule,
 					},
 				}
 			}
@@ -367,6 +474,7 @@ func typeIsBoolean() YamlCheck {
 					{Msg: fmt.Sprintf(`the "%s" property must be a boolean`, buildPathString(path)),
 						Line:   yNode.Line,
 						Column: yNode.Column,
+						Rule:   propertyTypeRule,
 					},
 				}
 			}
@@ -376,6 +484,124 @@ func typeIsBoolean() YamlCheck {
 	}
 }
 
+// kwalifyScalarTypes - the kwalify scalar types, with the descriptions which are used in the issues
+var kwalifyScalarTypes = map[string]string{
+	"str":       "a string",
+	"int":       "an integer",
+	"float":     "a float",
+	"number":    "a number",
+	"text":      "a string or a number",
+	"bool":      "a boolean",
+	"scalar":    "a scalar",
+	"timestamp": "a timestamp",
+	"date":      "a date",
+}
+
+var dateRegExp = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+
+// Validates that the value has the kwalify scalar type.
+// Strings are scalars with the !!str tag or with custom tags, like !sensitive.
+func typeIsScalar(typeName string) YamlCheck {
+	return func(yNode, yParentNode *yaml.Node, path []string) YamlValidationIssues {
+		if yNode == nil || matchesScalarType(yNode, typeName) {
+			return []YamlValidationIssue{}
+		}
+
+		return []YamlValidationIssue{
+			{
+				Msg:    fmt.Sprintf(`the "%s" property must be %s`, buildPathString(path), kwalifyScalarTypes[typeName]),
+				Line:   yNode.Line,
+				Column: yNode.Column,
+				Rule:   propertyTypeRule,
+			},
+		}
+	}
+}
+
+func matchesScalarType(yNode *yaml.Node, typeName string) bool {
+	if yNode.Kind != yaml.ScalarNode {
+		return false
+	}
+	isString := yNode.Tag == "!!str" || !strings.HasPrefix(yNode.Tag, "!!")
+	isNumber := yNode.Tag == "!!int" || yNode.Tag == "!!float"
+	switch typeName {
+	case "str":
+		return isString
+	case "int":
+		return yNode.Tag == "!!int"
+	case "float":
+		return yNode.Tag == "!!float"
+	case "number":
+		return isNumber
+	case "text":
+		return isString || isNumber
+	case "bool":
+		return yNode.Tag == "!!bool"
+	case "timestamp":
+		return yNode.Tag == "!!timestamp"
+	case "date":
+		return yNode.Tag == "!!timestamp" && dateRegExp.MatchString(yNode.Value)
+	}
+	// scalar
+	return yNode.Tag != "!!null"
+}
+
+// kwalifyBounds - the bounds of the kwalify "range" and "length" constraints
+type kwalifyBounds struct {
+	min, max, minEx, maxEx *float64
+}
+
+// Validates that the numeric value is in the range
+func valueInRange(bounds kwalifyBounds) YamlCheck {
+	return func(yNode, yParentNode *yaml.Node, path []string) YamlValidationIssues {
+		if yNode == nil || !matchesScalarType(yNode, "number") {
+			return []YamlValidationIssue{}
+		}
+		var value float64
+		if err := yNode.Decode(&value); err != nil {
+			return []YamlValidationIssue{}
+		}
+
+		pathStr := buildPathString(path)
+		var msg string
+		switch {
+		case bounds.min != nil && value < *bounds.min:
+			msg = fmt.Sprintf(minimumMsg, yNode.Value, pathStr, *bounds.min)
+		case bounds.max != nil && value > *bounds.max:
+			msg = fmt.Sprintf(maximumMsg, yNode.Value, pathStr, *bounds.max)
+		case bounds.minEx != nil && value <= *bounds.minEx:
+			msg = fmt.Sprintf(exclusiveMinimumMsg, yNode.Value, pathStr, *bounds.minEx)
+		case bounds.maxEx != nil && value >= *bounds.maxEx:
+			msg = fmt.Sprintf(exclusiveMaximumMsg, yNode.Value, pathStr, *bounds.maxEx)
+		}
+		return appendRuleIssue([]YamlValidationIssue{}, rangeRule, msg, yNode.Line, yNode.Column)
+	}
+}
+
+// Validates that the length of the scalar value is in the range
+func lengthInRange(bounds kwalifyBounds) YamlCheck {
+	return func(yNode, yParentNode *yaml.Node, path []string) YamlValidationIssues {
+		if yNode == nil || !matchesScalarType(yNode, "scalar") {
+			return []YamlValidationIssue{}
+		}
+
+		length := float64(len([]rune(yNode.Value)))
+		pathStr := buildPathString(path)
+		var msg string
+		switch {
+		case bounds.min != nil && length < *bounds.min:
+			msg = fmt.Sprintf(minLengthMsg, yNode.Value, pathStr, int(*bounds.min))
+		case bounds.max != nil && length > *bounds.max:
+			msg = fmt.Sprintf(maxLengthMsg, yNode.Value, pathStr, int(*bounds.max))
+		case bounds.minEx != nil && length <= *bounds.minEx:
+			msg = fmt.Sprintf(minLengthMsg, yNode.Value, pathStr, int(*bounds.minEx)+1)
+		case bounds.maxEx != nil && length >= *bounds.maxEx:
+			msg = fmt.Sprintf(maxLengthMsg, yNode.Value, pathStr, int(*bounds.maxEx)-1)
+		}
+		return appendRuleIssue([]YamlValidationIssue{}, lengthRule, msg, yNode.Line, yNode.Column)
+	}
+}
+
 func matchesRegExp(pattern string) YamlCheck {
 	regExp, _ := regexp.Compile(pattern)
 
@@ -393,6 +619,7 @@ func matchesRegExp(pattern string) YamlCheck {
 						strValue, buildPathString(path), pattern),
 					Line:   yNode.Line,
 					Column: yNode.Column,
+					Rule:   patternRule,
 				},
 			}
 		}
@@ -437,6 +664,7 @@ func matchesEnumValues(enumValues []string) YamlCheck {
 						value, buildPathString(path), expectedSubset),
 					Line:   yNode.Line,
 					Column: yNode.Column,
+					Rule:   enumRule,
 				},
 			}
 		}
@@ -466,6 +694,15 @@ func buildPathString(path []string) string {
 	return prettyPathStr
 }
 
+func containsString(sl []string, value string) bool {
+	for _, item := range sl {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
 func last(sl []string) string {
 	return sl[len(sl)-1]
 }
@@ -249,9 +249,9 @@ optionalClasses:
 		validateIssues := runSchemaValidations(node, validations)
 
 		Ω(validateIssues).Should(ConsistOf(
-			YamlValidationIssue{`the "oops" value of the "classes[0].room" property does not match the "^[0-9]+$" pattern`, 6, 10},
-			YamlValidationIssue{`missing the "name" required property in the classes[1] .yaml node`, 8, 4},
-			YamlValidationIssue{`the "optionalClasses.english" property must be a boolean`, 13, 12},
+			YamlValidationIssue{Msg: `the "oops" value of the "classes[0].room" property does not match the "^[0-9]+$" pattern`, Line: 6, Column: 10, Rule: patternRule},
+			YamlValidationIssue{Msg: `missing the "name" required property in the classes[1] .yaml node`, Line: 8, Column: 4, Rule: requiredPropertyRule},
+			YamlValidationIssue{Msg: `the "optionalClasses.english" property must be a boolean`, Line: 13, Column: 12, Rule: propertyTypeRule},
 		))
 	})
 })
@@ -20,11 +20,12 @@ func buildValidationsFromSchemaText(yaml []byte) ([]YamlCheck, []YamlValidationI
 
 	y, parseError := simpleyaml.NewYaml(yaml)
 	if parseError != nil {
-		schemaIssues = appendIssue(schemaIssues, "validation failed when parsing the MTA schema file: "+parseError.Error(), 0, 0)
+		schemaIssues = appendRuleIssue(schemaIssues, schemaDefinitionRule, "validation failed when parsing the MTA schema file: "+parseError.Error(), 0, 0)
 		return validations, schemaIssues
 	}
 
-	return buildValidationsFromSchema(y)
+	validations, schemaIssues = buildValidationsFromSchema(y)
+	return validations, setIssuesRule(schemaIssues, schemaDefinitionRule)
 }
 
 // Internal YAML validation builder
@@ -92,7 +93,8 @@ func buildValidationsForMapping(mappingNode *simpleyaml.Yaml) ([]YamlCheck, []Ya
 	var schemaIssues []YamlValidationIssue
 
 	// Default rule for unknown keys - normally unmarshalled to Go map
-	if keys, _ := mappingNode.GetMapKeys(); len(keys) == 1 && keys[0] == "=" {
+	keys, _ := mappingNode.GetMapKeys()
+	if len(keys) == 1 && keys[0] == "=" {
 		// type: map
 		// mapping:
 		//   =:
@@ -116,6 +118,24 @@ func buildValidationsForMapping(mappingNode *simpleyaml.Yaml) ([]YamlCheck, []Ya
 	return validations, schemaIssues
 }
 
+// Create the validations of the default rule for the keys which are not listed in the mapping, e.g.
+//
+//	type: map
+//	mapping:
+//	  firstName:  {required: true}
+//	  =:
+//	    type: ...
+func buildValidationsForOtherKeys(y *simpleyaml.Yaml, keys []string) ([]YamlCheck, []YamlValidationIssue) {
+	var otherKeys []string
+	for _, key := range keys {
+		if key != "=" {
+			otherKeys = append(otherKeys, key)
+		}
+	}
+	propInnerValidations, schemaIssues := buildValidationsFromSchema(y.Get("="))
+	return []YamlCheck{forEachOtherProperty(otherKeys, propInnerValidations...)}, schemaIssues
+}
+
 // Create Validations for a mapping
 // each key's inner validations will be wrapping in a "property" validation
 func buildValidationsFromMap(y *simpleyaml.Yaml) ([]YamlCheck, []YamlValidationIssue) {
@@ -126,6 +146,12 @@ func buildValidationsFromMap(y *simpleyaml.Yaml) ([]YamlCheck, []YamlValidationI
 
 	keys, _ := y.GetMapKeys()
 	for _, key := range keys {
+		if key == "=" {
+			newValidations, newSchemaIssues := buildValidationsForOtherKeys(y, keys)
+			schemaIssues = append(schemaIssues, newSchemaIssues...)
+			validations = append(validations, newValidations...)
+			continue
+		}
 		value := y.Get(key)
 		propInnerValidations, newSchemaIssues := buildValidationsFromSchema(value)
 		schemaIssues = append(schemaIssues, newSchemaIssues...)
@@ -137,17 +163,49 @@ func buildValidationsFromMap(y *simpleyaml.Yaml) ([]YamlCheck, []YamlValidationI
 
 // Creates validations for a sequence
 // Will wrap the nested checks with a "typeIsArray" check and iterate over the elements
-// using "forEach"
+// using "forEach". The uniqueness of the items, or of the mapping keys of the items, is validated on the whole sequence.
 func buildValidationsFromSequence(y *simpleyaml.Yaml) ([]YamlCheck, []YamlValidationIssue) {
 	var validations []YamlCheck
 
 	sequenceInnerValidations, newIssues := buildValidationsFromSchema(y)
-	seqChecksWrapper := sequenceFailFast(typeIsArray(), forEach(sequenceInnerValidations...))
+	itemsValidations := append([]YamlCheck{forEach(sequenceInnerValidations...)}, buildUniqueValidations(y)...)
+	seqChecksWrapper := sequenceFailFast(typeIsArray(), sequence(itemsValidations...))
 	validations = append(validations, seqChecksWrapper)
 
 	return validations, newIssues
 }
 
+// Creates the validations of the "unique" and "ident" constraints of a sequence item, e.g.
+//
+//	sequence:
+//	- type: map
+//	  mapping:
+//	    name: {required: true, unique: true}
+//
+// or
+//
+//	sequence:
+//	- {type: str, unique: true}
+func buildUniqueValidations(y *simpleyaml.Yaml) []YamlCheck {
+	var validations []YamlCheck
+
+	if y.Get("mapping").IsMap() {
+		keys, _ := y.Get("mapping").GetMapKeys()
+		for _, key := range keys {
+			if isUnique(y.Get("mapping").Get(key)) {
+				validations = append(validations, uniqueProperty(key))
+			}
+		}
+	} else if isUnique(y) {
+		validations = append(validations, uniqueItems())
+	}
+	return validations
+}
+
+func isUnique(y *simpleyaml.Yaml) bool {
+	return getLiteralStringValue(y.Get("unique")) == "true" || getLiteralStringValue(y.Get("ident")) == "true"
+}
+
 // Will create the "edge" nodes validations, these are specific checks
 // for a specific path at the end of the YAML Schema
 // e.g: {required: true, pattern: /^[a-zA-Z]$/}
@@ -159,6 +217,10 @@ func buildLeafValidations(y *simpleyaml.Yaml) ([]YamlCheck, []YamlValidationIssu
 
 	validations, schemaIssues = invokeLeafValidation(y, validations, schemaIssues, buildPatternValidation)
 
+	validations, schemaIssues = invokeLeafValidation(y, validations, schemaIssues, buildRangeValidation)
+
+	validations, schemaIssues = invokeLeafValidation(y, validations, schemaIssues, buildLengthValidation)
+
 	// Special handling is needed for "optional" and "required" Validations, must be invoked last
 	// and receive all previous built validations as extra wrapping will be done here.
 	return buildOptionalOrRequiredValidation(y, validations, schemaIssues)
@@ -166,7 +228,11 @@ func buildLeafValidations(y *simpleyaml.Yaml) ([]YamlCheck, []YamlValidationIssu
 }
 
 func buildOptionalOrRequiredValidation(y *simpleyaml.Yaml, validations []YamlCheck, schemaIssues []YamlValidationIssue) ([]YamlCheck, []YamlValidationIssue) {
+	// "ident" implies "required" and "unique"
 	requiredNode := y.Get("required")
+	if !requiredNode.IsFound() && getLiteralStringValue(y.Get("ident")) == "true" {
+		requiredNode = y.Get("ident")
+	}
 	if requiredNode.IsFound() {
 		requiredValue := getLiteralStringValue(requiredNode)
 		if requiredValue != "true" && requiredValue != "false" {
@@ -202,8 +268,17 @@ func buildTypeValidation(y *simpleyaml.Yaml) ([]YamlCheck, []YamlValidationIssue
 			schemaIssues = appendIssue(schemaIssues, "invalid .yaml file schema: the type node must be a string", 0, 0)
 			return validations, schemaIssues
 		}
-		if typeValue == "bool" {
+		switch typeValue {
+		case "bool":
 			validations = append(validations, typeIsBoolean())
+		case "any":
+		default:
+			if _, ok := kwalifyScalarTypes[typeValue]; !ok {
+				schemaIssues = appendIssue(schemaIssues,
+					fmt.Sprintf(`invalid .yaml file schema: the "%s" type is not supported`, typeValue), 0, 0)
+				return validations, schemaIssues
+			}
+			validations = append(validations, typeIsScalar(typeValue))
 		}
 	}
 
@@ -218,7 +293,7 @@ func buildTypeValidation(y *simpleyaml.Yaml) ([]YamlCheck, []YamlValidationIssue
 
 func buildEnumValidation(enumNode *simpleyaml.Yaml) ([]YamlCheck, []YamlValidationIssue) {
 	if !enumNode.IsArray() {
-		return []YamlCheck{}, []YamlValidationIssue{{"invalid .yaml file schema: enums values must be listed as an array", 0, 0}}
+		return []YamlCheck{}, []YamlValidationIssue{{Msg: "invalid .yaml file schema: enums values must be listed as an array", Line: 0, Column: 0}}
 	}
 
 	enumsNumber, _ := enumNode.GetArraySize()
@@ -227,7 +302,7 @@ func buildEnumValidation(enumNode *simpleyaml.Yaml) ([]YamlCheck, []YamlValidati
 	for i := 0; i < enumsNumber; i++ {
 		enumValueNode := enumNode.GetIndex(i)
 		if enumValueNode.IsArray() || enumValueNode.IsMap() {
-			return []YamlCheck{}, []YamlValidationIssue{{"invalid .yaml file schema: enum values must be simple", 0, 0}}
+			return []YamlCheck{}, []YamlValidationIssue{{Msg: "invalid .yaml file schema: enum values must be simple", Line: 0, Column: 0}}
 		}
 		enumValue := getLiteralStringValue(enumValueNode)
 		enumValues = append(enumValues, enumValue)
@@ -264,6 +339,61 @@ func buildPatternValidation(y *simpleyaml.Yaml) ([]YamlCheck, []YamlValidationIs
 	return validations, schemaIssues
 }
 
+// range: {min: 1, max-ex: 10}
+func buildRangeValidation(y *simpleyaml.Yaml) ([]YamlCheck, []YamlValidationIssue) {
+	rangeNode := y.Get("range")
+	if !rangeNode.IsFound() {
+		return nil, nil
+	}
+	bounds, schemaIssues := buildBounds(rangeNode, "range")
+	if len(schemaIssues) > 0 {
+		return nil, schemaIssues
+	}
+	return []YamlCheck{valueInRange(bounds)}, nil
+}
+
+// length: {min: 1, max: 64}
+func buildLengthValidation(y *simpleyaml.Yaml) ([]YamlCheck, []YamlValidationIssue) {
+	lengthNode := y.Get("length")
+	if !lengthNode.IsFound() {
+		return nil, nil
+	}
+	bounds, schemaIssues := buildBounds(lengthNode, "length")
+	if len(schemaIssues) > 0 {
+		return nil, schemaIssues
+	}
+	return []YamlCheck{lengthInRange(bounds)}, nil
+}
+
+// Reads the "min", "max", "min-ex" and "max-ex" bounds of a "range" or "length" node
+func buildBounds(y *simpleyaml.Yaml, nodeName string) (kwalifyBounds, []YamlValidationIssue) {
+	var bounds kwalifyBounds
+	if !y.IsMap() {
+		return bounds, appendIssue(nil, fmt.Sprintf("invalid .yaml file schema: the %s node must be a map", nodeName), 0, 0)
+	}
+
+	targets := map[string]**float64{"min": &bounds.min, "max": &bounds.max, "min-ex": &bounds.minEx, "max-ex": &bounds.maxEx}
+	keys, _ := y.GetMapKeys()
+	for _, key := range keys {
+		target, ok := targets[key]
+		if !ok {
+			return bounds, appendIssue(nil,
+				fmt.Sprintf(`invalid .yaml file schema: the "%s" key is not supported in the %s node`, key, nodeName), 0, 0)
+		}
+		value, err := y.Get(key).Float()
+		if err != nil {
+			intValue, intErr := y.Get(key).Int()
+			if intErr != nil {
+				return bounds, appendIssue(nil,
+					fmt.Sprintf(`invalid .yaml file schema: the "%s" value of the %s node must be a number`, key, nodeName), 0, 0)
+			}
+			value = float64(intValue)
+		}
+		*target = &value
+	}
+	return bounds, nil
+}
+
 // Utility to reduce verbosity
 func invokeLeafValidation(y *simpleyaml.Yaml, validations []YamlCheck, schemaIsssues []YamlValidationIssue,
 	leafBuilder func(y *simpleyaml.Yaml) ([]YamlCheck, []YamlValidationIssue)) ([]YamlCheck, []YamlValidationIssue) {
@@ -280,3 +410,19 @@ func appendIssue(issues []YamlValidationIssue, issue string, line int, column in
 	}
 	return append(issues, []YamlValidationIssue{{Msg: issue, Line: line, Column: column}}...)
 }
+
+// appendRuleIssue appends the issue which is reported by the rule
+func appendRuleIssue(issues []YamlValidationIssue, rule string, issue string, line int, column int) []YamlValidationIssue {
+	if issue == "" {
+		return issues
+	}
+	return append(issues, YamlValidationIssue{Msg: issue, Line: line, Column: column, Rule: rule})
+}
+
+// setIssuesRule sets the rule which reported the issues
+func setIssuesRule(issues []YamlValidationIssue, rule string) []YamlValidationIssue {
+	for i := range issues {
+		issues[i].Rule = rule
+	}
+	return issues
+}
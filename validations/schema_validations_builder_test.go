@@ -80,6 +80,30 @@ type: str
 enum:
   [duck, [dog, cat]]
 `, `invalid .yaml file schema: enum values must be simple`),
+
+		Entry("unsupported type", `
+type: map
+mapping:
+  firstName:  {type: string}
+`, `invalid .yaml file schema: the "string" type is not supported`),
+
+		Entry("range NotMap", `
+type: map
+mapping:
+  age:  {type: int, range: 10}
+`, `invalid .yaml file schema: the range node must be a map`),
+
+		Entry("range unsupported key", `
+type: map
+mapping:
+  age:  {type: int, range: {minimum: 10}}
+`, `invalid .yaml file schema: the "minimum" key is not supported in the range node`),
+
+		Entry("length value NotNumber", `
+type: map
+mapping:
+  name:  {type: str, length: {max: long}}
+`, `invalid .yaml file schema: the "max" value of the length node must be a number`),
 	)
 
 	var _ = DescribeTable("Valid input",
@@ -198,6 +222,47 @@ mapping:
    firstName:  {required: false, pattern: '/^[a-zA-Z]+$/'}
 `, `
 lastName: duck
+`),
+		Entry("scalar types", `
+type: map
+mapping:
+   name:  {type: str}
+   secret:  {type: str}
+   age:  {type: int, range: {min: 0, max-ex: 150}}
+   weight:  {type: float}
+   size:  {type: number}
+   title:  {type: text}
+   code:  {type: scalar, length: {min: 2, max: 4}}
+   birthday:  {type: date}
+   created:  {type: timestamp}
+   anything:  {type: any}
+`, `
+name: Donald
+secret: !sensitive duck
+age: 86
+weight: 1.5
+size: 3
+title: 42
+code: 123
+birthday: 1934-06-09
+created: 2001-12-14t21:59:43.10-05:00
+anything: [1, a]
+`),
+		Entry("unique mapping keys and items", `
+type: seq
+sequence:
+- type: map
+  mapping:
+    name: {required: true, unique: true}
+    tags:
+      type: seq
+      sequence:
+      - {type: str, unique: true}
+`, `
+- name: a
+  tags: [x, y]
+- name: b
+  tags: [x, y]
 `),
 		Entry("Type Is Bool", `
 type: map
@@ -233,7 +298,7 @@ mapping:
     type: map
     required: true
     mapping:
-      firstName: {type: string}
+      firstName: {type: str}
 `, `{}`, `missing the "inner" required property in the root .yaml node`, 1, 1),
 
 		Entry("required mapping field with inner required field", `
@@ -328,5 +393,150 @@ mapping:
 firstName: John
 isHappy: 123
 `, `the "root.isHappy" property must be a boolean`, 3, 10),
+
+		Entry("Type Is Str", `
+type: map
+mapping:
+   name:  {type: str}
+`, `
+name: 123
+`, `the "root.name" property must be a string`, 2, 7),
+
+		Entry("Type Is Int", `
+type: map
+mapping:
+   age:  {type: int}
+`, `
+age: 1.5
+`, `the "root.age" property must be an integer`, 2, 6),
+
+		Entry("Type Is Float", `
+type: map
+mapping:
+   weight:  {type: float}
+`, `
+weight: 1
+`, `the "root.weight" property must be a float`, 2, 9),
+
+		Entry("Type Is Number", `
+type: map
+mapping:
+   age:  {type: number}
+`, `
+age: old
+`, `the "root.age" property must be a number`, 2, 6),
+
+		Entry("Type Is Text", `
+type: map
+mapping:
+   title:  {type: text}
+`, `
+title: true
+`, `the "root.title" property must be a string or a number`, 2, 8),
+
+		Entry("Type Is Scalar", `
+type: map
+mapping:
+   title:  {type: scalar}
+`, `
+title: [a]
+`, `the "root.title" property must be a scalar`, 2, 8),
+
+		Entry("Type Is Date", `
+type: map
+mapping:
+   birthday:  {type: date}
+`, `
+birthday: 2001-12-14t21:59:43.10-05:00
+`, `the "root.birthday" property must be a date`, 2, 11),
+
+		Entry("range min", `
+type: map
+mapping:
+   age:  {type: int, range: {min: 18, max: 120}}
+`, `
+age: 17
+`, `the "17" value of the "root.age" property must be greater than or equal to 18`, 2, 6),
+
+		Entry("range max-ex", `
+type: map
+mapping:
+   ratio:  {type: number, range: {max-ex: 1}}
+`, `
+ratio: 1.0
+`, `the "1.0" value of the "root.ratio" property must be less than 1`, 2, 8),
+
+		Entry("length max", `
+type: map
+mapping:
+   code:  {type: str, length: {max: 3}}
+`, `
+code: abcd
+`, `the "abcd" value of the "root.code" property must be at most 3 characters long`, 2, 7),
+
+		Entry("length min-ex", `
+type: map
+mapping:
+   code:  {type: str, length: {min-ex: 2}}
+`, `
+code: ab
+`, `the "ab" value of the "root.code" property must be at least 3 characters long`, 2, 7),
+
+		Entry("unique mapping key in sequence", `
+type: seq
+sequence:
+- type: map
+  mapping:
+    name: {required: true, unique: true}
+`, `
+- name: a
+- name: b
+- name: a
+`, `the "a" value of the "[2].name" property is not unique; it is already used on line 2`, 4, 9),
+
+		Entry("ident mapping key in sequence", `
+type: seq
+sequence:
+- type: map
+  mapping:
+    name: {ident: true}
+`, `
+- name: a
+- other: b
+`, `missing the "name" required property in the root[1] .yaml node`, 3, 3),
+
+		Entry("unique scalar items", `
+type: map
+mapping:
+  tags:
+    type: seq
+    sequence:
+    - {type: str, unique: true}
+`, `
+tags: [x, y, x]
+`, `the "x" value of the "tags[2]" property is not unique; it is already used on line 2`, 2, 14),
+
+		Entry("sequence items with another type", `
+type: map
+mapping:
+  tags:
+    type: seq
+    sequence:
+    - type: str
+`, `
+tags: [x, 1]
+`, `the "tags[1]" property must be a string`, 2, 11),
+
+		Entry("mapping with default value and named keys", `
+type: map
+mapping:
+  name: {type: str}
+  =:
+    type: bool
+`, `
+name: Donald
+isHappy: true
+isDuck: yes please
+`, `the "root.isDuck" property must be a boolean`, 4, 9),
 	)
 })
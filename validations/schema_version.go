@@ -0,0 +1,100 @@
+package validate
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	schemaVersionYamlField = "_schema-version"
+
+	invalidSchemaVersionMsg     = `the "%s" schema version is not valid; the descriptor was validated against the %s schema`
+	unsupportedSchemaVersionMsg = `the "%s" schema version is not supported; the supported versions are %s; the descriptor was validated against the %s schema`
+	oldSchemaVersionMsg         = `the "%s" schema version is not supported by the validation; the descriptor was validated against the %s schema`
+)
+
+// supportedSchemaVersions - the versions of the embedded schemas, from the oldest to the latest
+var supportedSchemaVersions = []string{"3.1", "3.2", "3.3"}
+
+// legacySchemaMajorVersion - the major version of the descriptors which are validated against the oldest schema
+// without a warning
+const legacySchemaMajorVersion = 2
+
+// schemaVersionRegex - matches semantic versions in which the minor and patch numbers can be omitted
+var schemaVersionRegex = regexp.MustCompile(`^(\d+)(?:\.(\d+)(?:\.(\d+)(?:[-+].*)?)?)?$`)
+
+// getSchemaDef returns the embedded schema of the MTA descriptor or the MTA extension descriptor in the version
+func getSchemaDef(version string, ext bool) []byte {
+	if ext {
+		return map[string][]byte{"3.1": extSchemaDefV31, "3.2": extSchemaDefV32, "3.3": extSchemaDefV33}[version]
+	}
+	return map[string][]byte{"3.1": schemaDefV31, "3.2": schemaDefV32, "3.3": schemaDefV33}[version]
+}
+
+// selectSchemaVersion returns the version of the schema against which the descriptor is validated, according to its
+// "_schema-version" element. Omitted minor and patch numbers are replaced with the highest supported numbers, as the
+// deployer does. Descriptors with an invalid version or a version newer than the supported versions are validated
+// against the latest schema, with an error; descriptors with an older version are validated against the oldest
+// schema, with a warning. The 2.x descriptors are still accepted by the deployer and were always validated against
+// the 3.x schemas, so they are validated against the oldest schema without a warning.
+// When the version is missing, the latest schema is used and its validation reports it.
+func selectSchemaVersion(root *yaml.Node) (version string, errIssues []YamlValidationIssue, warnIssues []YamlValidationIssue) {
+	oldest := supportedSchemaVersions[0]
+	latest := supportedSchemaVersions[len(supportedSchemaVersions)-1]
+	versionNode := getPropValueByName(root, schemaVersionYamlField)
+	if versionNode == nil || versionNode.Kind != yaml.ScalarNode || len(versionNode.Value) == 0 {
+		return latest, nil, nil
+	}
+	value := strings.TrimSpace(versionNode.Value)
+
+	match := schemaVersionRegex.FindStringSubmatch(value)
+	if match == nil {
+		return latest, appendRuleIssue(nil, schemaVersionRule, fmt.Sprintf(invalidSchemaVersionMsg, value, latest),
+			versionNode.Line, versionNode.Column), nil
+	}
+	major, _ := strconv.Atoi(match[1])
+	if len(match[2]) == 0 {
+		// The highest supported minor version of the major version is used
+		for i := len(supportedSchemaVersions) - 1; i >= 0; i-- {
+			if strings.HasPrefix(supportedSchemaVersions[i], match[1]+".") {
+				return supportedSchemaVersions[i], nil, nil
+			}
+		}
+	} else {
+		minor, _ := strconv.Atoi(match[2])
+		candidate := fmt.Sprintf("%d.%d", major, minor)
+		for _, supported := range supportedSchemaVersions {
+			if supported == candidate {
+				return supported, nil, nil
+			}
+		}
+	}
+
+	if major == legacySchemaMajorVersion {
+		return oldest, nil, nil
+	}
+	if compareSchemaVersions(value, oldest) < 0 {
+		return oldest, nil, appendRuleIssue(nil, schemaVersionRule, fmt.Sprintf(oldSchemaVersionMsg, value, oldest),
+			versionNode.Line, versionNode.Column)
+	}
+	return latest, appendRuleIssue(nil, schemaVersionRule, fmt.Sprintf(unsupportedSchemaVersionMsg, value, strings.Join(supportedSchemaVersions, ", "), latest),
+		versionNode.Line, versionNode.Column), nil
+}
+
+// compareSchemaVersions compares the major and minor numbers of two versions which match the version regex
+func compareSchemaVersions(version1, version2 string) int {
+	match1 := schemaVersionRegex.FindStringSubmatch(version1)
+	match2 := schemaVersionRegex.FindStringSubmatch(version2)
+	for i := 1; i <= 2; i++ {
+		number1, _ := strconv.Atoi(match1[i])
+		number2, _ := strconv.Atoi(match2[i])
+		if number1 != number2 {
+			return number1 - number2
+		}
+	}
+	return 0
+}
@@ -0,0 +1,99 @@
+package validate
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Schema version", func() {
+	var _ = table.DescribeTable("selectSchemaVersion", func(version string, expectedVersion string, expectedError string, expectedWarning string) {
+		root, err := getContentNode([]byte("ID: test\n_schema-version: " + version + "\n"))
+		Ω(err).Should(Succeed())
+		selected, errIssues, warnIssues := selectSchemaVersion(root)
+		Ω(selected).Should(Equal(expectedVersion))
+		if len(expectedError) > 0 {
+			Ω(errIssues).Should(Equal([]YamlValidationIssue{{Msg: expectedError, Line: 2, Column: 18, Rule: schemaVersionRule}}))
+		} else {
+			Ω(errIssues).Should(BeEmpty())
+		}
+		if len(expectedWarning) > 0 {
+			Ω(warnIssues).Should(Equal([]YamlValidationIssue{{Msg: expectedWarning, Line: 2, Column: 18, Rule: schemaVersionRule}}))
+		} else {
+			Ω(warnIssues).Should(BeEmpty())
+		}
+	},
+		table.Entry("minor version", "'3.1'", "3.1", "", ""),
+		table.Entry("patch version", "3.2.5", "3.2", "", ""),
+		table.Entry("pre-release version", "3.3.0-beta", "3.3", "", ""),
+		table.Entry("omitted minor version", "'3'", "3.3", "", ""),
+		table.Entry("2.x version", "'2.1'", "3.1", "", ""),
+		table.Entry("omitted minor 2.x version", "'2'", "3.1", "", ""),
+		table.Entry("older major version", "'1.0'", "3.1",
+			"", fmt.Sprintf(oldSchemaVersionMsg, "1.0", "3.1")),
+		table.Entry("older minor version", "'3.0'", "3.1",
+			"", fmt.Sprintf(oldSchemaVersionMsg, "3.0", "3.1")),
+		table.Entry("newer minor version", "'3.4'", "3.3",
+			fmt.Sprintf(unsupportedSchemaVersionMsg, "3.4", "3.1, 3.2, 3.3", "3.3"), ""),
+		table.Entry("newer major version", "'4'", "3.3",
+			fmt.Sprintf(unsupportedSchemaVersionMsg, "4", "3.1, 3.2, 3.3", "3.3"), ""),
+		table.Entry("invalid version", "3.x", "3.3",
+			fmt.Sprintf(invalidSchemaVersionMsg, "3.x", "3.3"), ""),
+	)
+
+	It("selects the latest version when the version is missing", func() {
+		root, err := getContentNode([]byte("ID: test\n"))
+		Ω(err).Should(Succeed())
+		selected, errIssues, warnIssues := selectSchemaVersion(root)
+		Ω(selected).Should(Equal("3.3"))
+		Ω(errIssues).Should(BeEmpty())
+		Ω(warnIssues).Should(BeEmpty())
+	})
+
+	It("validates the descriptor against the schema of its version", func() {
+		content := `
+ID: mtahooks
+_schema-version: '%s'
+version: 0.0.1
+
+modules:
+- name: srv
+  type: nodejs
+  hooks:
+  - type: task
+    phases: [deploy.application.before-start]
+  - name: notify
+    type: script
+`
+		errIssues, _ := validate([]byte(fmt.Sprintf(content, "3.2")), getTestPath("mtahtml5"), true, false, true, "")
+		Ω(errIssues).Should(BeEmpty())
+
+		errIssues, _ = validate([]byte(fmt.Sprintf(content, "3.3")), getTestPath("mtahtml5"), true, false, true, "")
+		Ω(errIssues).Should(ConsistOf(
+			YamlValidationIssue{Msg: `missing the "name" required property in the modules[0].hooks[0] .yaml node`, Line: 10, Column: 5, Rule: requiredPropertyRule},
+			YamlValidationIssue{Msg: `the "script" value of the "modules[0].hooks[1].type" enum property is invalid; expected one of the following: task`, Line: 13, Column: 11, Rule: enumRule},
+		))
+	})
+
+	It("validates the extension descriptor against the schema of its version", func() {
+		content := `
+_schema-version: '%s'
+ID: mtahooks.ext
+extends: mtahooks
+
+modules:
+- name: srv
+  hooks:
+  - parameters:
+      memory: 128M
+`
+		errIssues, _ := validateExt([]byte(fmt.Sprintf(content, "3.2")), getTestPath("mtahtml5"), "my.mtaext", true, false, true, "")
+		Ω(errIssues).Should(BeEmpty())
+
+		errIssues, _ = validateExt([]byte(fmt.Sprintf(content, "3.3")), getTestPath("mtahtml5"), "my.mtaext", true, false, true, "")
+		Ω(errIssues).Should(HaveLen(1))
+		Ω(errIssues[0].Msg).Should(ContainSubstring(`"name" required property`))
+	})
+})
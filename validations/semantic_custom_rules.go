@@ -0,0 +1,100 @@
+package validate
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/SAP/cloud-mta/mta"
+)
+
+const (
+	emptyRuleIDMsg     = `the ID of the semantic rule is empty`
+	incorrectRuleIDMsg = `the "%s" ID of the semantic rule is incorrect; it cannot contain commas or spaces`
+	ruleIDInUseMsg     = `the "%s" ID of the semantic rule is already in use`
+)
+
+// SemanticRule - a custom semantic validation of the MTA descriptor, e.g. a naming convention of the modules.
+// The registered rules run with the semantic validations of the MTA descriptor.
+type SemanticRule interface {
+	// Rule returns the ID and the description of the rule. The ID is reported with the issues of the rule, and it's used
+	// to exclude, configure and suppress the rule, so it must be stable.
+	Rule() Rule
+	// Validate validates the MTA descriptor, which is parsed to the MTA and to its root node, in the project folder.
+	// Issues which don't prevent building or deploying the MTA are usually errors only when strict is true.
+	// The issues are reported with the ID of the rule.
+	Validate(mta *mta.MTA, root *yaml.Node, projectPath string, strict bool) (errors []YamlValidationIssue, warnings []YamlValidationIssue)
+}
+
+// customRules - the registered semantic rules
+var customRules = struct {
+	sync.RWMutex
+	rules []SemanticRule
+}{}
+
+// RegisterSemanticRule registers the semantic rule, e.g. in the init function of the package which defines it.
+// An error is returned when the ID of the rule is already used by another rule.
+func RegisterSemanticRule(rule SemanticRule) error {
+	id := rule.Rule().ID
+	if id == "" {
+		return errors.New(emptyRuleIDMsg)
+	}
+	if strings.IndexFunc(id, func(r rune) bool { return r == ',' || unicode.IsSpace(r) }) >= 0 {
+		return errors.Errorf(incorrectRuleIDMsg, id)
+	}
+
+	customRules.Lock()
+	defer customRules.Unlock()
+	for _, existing := range rules {
+		if existing.ID == id {
+			return errors.Errorf(ruleIDInUseMsg, id)
+		}
+	}
+	for _, existing := range customRules.rules {
+		if existing.Rule().ID == id {
+			return errors.Errorf(ruleIDInUseMsg, id)
+		}
+	}
+	customRules.rules = append(customRules.rules, rule)
+	return nil
+}
+
+// UnregisterSemanticRule removes the registered semantic rule with the ID
+func UnregisterSemanticRule(id string) {
+	customRules.Lock()
+	defer customRules.Unlock()
+	var remaining []SemanticRule
+	for _, rule := range customRules.rules {
+		if rule.Rule().ID != id {
+			remaining = append(remaining, rule)
+		}
+	}
+	customRules.rules = remaining
+}
+
+// getCustomSemanticValidations - gets the validations of the registered semantic rules minus the excluded rules
+func getCustomSemanticValidations(excluded map[string]bool) []checkSemantic {
+	customRules.RLock()
+	defer customRules.RUnlock()
+	var validations []checkSemantic
+	for _, rule := range customRules.rules {
+		if !excluded[rule.Rule().ID] {
+			validations = append(validations, semanticRule(rule.Rule().ID, rule.Validate))
+		}
+	}
+	return validations
+}
+
+// getCustomRules - gets the registered semantic rules
+func getCustomRules() []Rule {
+	customRules.RLock()
+	defer customRules.RUnlock()
+	var result []Rule
+	for _, rule := range customRules.rules {
+		result = append(result, rule.Rule())
+	}
+	return result
+}
@@ -0,0 +1,133 @@
+package validate
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v3"
+
+	"github.com/SAP/cloud-mta/mta"
+)
+
+const lowerCaseModuleNameMsg = `the "%s" module name is not in lower case`
+
+// lowerCaseModuleNames - a semantic rule which checks that the module names are in lower case
+type lowerCaseModuleNames struct{}
+
+func (lowerCaseModuleNames) Rule() Rule {
+	return Rule{ID: "lowerCaseModuleNames", Description: "The module names are in lower case"}
+}
+
+func (lowerCaseModuleNames) Validate(mta *mta.MTA, root *yaml.Node, projectPath string, strict bool) ([]YamlValidationIssue, []YamlValidationIssue) {
+	var issues []YamlValidationIssue
+	for index, module := range mta.Modules {
+		if module.Name != strings.ToLower(module.Name) {
+			line, column := GetNamedObjectPositionByIndex(root, modulesYamlField, index)
+			issues = append(issues, YamlValidationIssue{Msg: fmt.Sprintf(lowerCaseModuleNameMsg, module.Name), Line: line, Column: column})
+		}
+	}
+	if strict {
+		return issues, nil
+	}
+	return nil, issues
+}
+
+type namedRule string
+
+func (r namedRule) Rule() Rule {
+	return Rule{ID: string(r)}
+}
+
+func (namedRule) Validate(*mta.MTA, *yaml.Node, string, bool) ([]YamlValidationIssue, []YamlValidationIssue) {
+	return nil, nil
+}
+
+var _ = Describe("Custom semantic rules", func() {
+	rule := lowerCaseModuleNames{}
+	content := []byte(`
+ID: mta.custom
+modules:
+- name: Srv
+  type: nodejs
+- name: ui
+  type: html5
+`)
+	issue := YamlValidationIssue{Msg: fmt.Sprintf(lowerCaseModuleNameMsg, "Srv"), Line: 4, Column: 9, Rule: "lowerCaseModuleNames"}
+
+	BeforeEach(func() {
+		Ω(RegisterSemanticRule(rule)).Should(Succeed())
+	})
+
+	AfterEach(func() {
+		UnregisterSemanticRule(rule.Rule().ID)
+	})
+
+	It("runs the registered rules with the semantic validations", func() {
+		mtaStr, err := mta.Unmarshal(content)
+		Ω(err).Should(Succeed())
+		root, err := getContentNode(content)
+		Ω(err).Should(Succeed())
+		errs, _ := runSemanticValidations(mtaStr, root, getTestPath("testproject"), "paths,emptyPath", true)
+		Ω(errs).Should(Equal([]YamlValidationIssue{issue}))
+		_, warns := runSemanticValidations(mtaStr, root, getTestPath("testproject"), "paths,emptyPath", false)
+		Ω(warns).Should(Equal([]YamlValidationIssue{issue}))
+		errs, _ = runSemanticValidations(mtaStr, root, getTestPath("testproject"), "paths,emptyPath,lowerCaseModuleNames", true)
+		Ω(errs).Should(BeEmpty())
+	})
+
+	It("lists the registered rules", func() {
+		Ω(Rules()).Should(ContainElement(rule.Rule()))
+		_, err := parseConfig([]byte("rules: {lowerCaseModuleNames: {severity: warning}}"))
+		Ω(err).Should(Succeed())
+	})
+
+	It("doesn't run the rules after they are unregistered", func() {
+		validationsCount := len(getSemanticValidations(""))
+		UnregisterSemanticRule(rule.Rule().ID)
+		Ω(Rules()).ShouldNot(ContainElement(rule.Rule()))
+		Ω(getSemanticValidations("")).Should(HaveLen(validationsCount - 1))
+	})
+
+	It("fails to register a rule with an ID which is already in use", func() {
+		Ω(RegisterSemanticRule(rule)).Should(MatchError(fmt.Sprintf(ruleIDInUseMsg, "lowerCaseModuleNames")))
+		Ω(RegisterSemanticRule(namedRule(pathsValidation))).Should(MatchError(fmt.Sprintf(ruleIDInUseMsg, pathsValidation)))
+	})
+
+	It("registers a rule only once when it's registered concurrently", func() {
+		defer UnregisterSemanticRule("concurrentRule")
+		results := make(chan error)
+		for i := 0; i < 10; i++ {
+			go func() {
+				results <- RegisterSemanticRule(namedRule("concurrentRule"))
+			}()
+		}
+		registered := 0
+		for i := 0; i < 10; i++ {
+			if <-results == nil {
+				registered++
+			}
+		}
+		Ω(registered).Should(Equal(1))
+		Ω(getCustomRules()).Should(ContainElement(Rule{ID: "concurrentRule"}))
+	})
+
+	It("reports the positions of named objects which don't exist as 0, 0", func() {
+		root, err := getContentNode(content)
+		Ω(err).Should(Succeed())
+		Ω(GetNamedObjectNodeByIndex(root, modulesYamlField, 2)).Should(BeNil())
+		Ω(GetNamedObjectNodeByIndex(root, resourcesYamlField, 0)).Should(BeNil())
+		Ω(GetNamedObjectNodeByIndex(nil, modulesYamlField, 0)).Should(BeNil())
+		line, column := GetNamedObjectPositionByIndex(root, modulesYamlField, -1)
+		Ω([]int{line, column}).Should(Equal([]int{0, 0}))
+		line, column = GetNamedObjectPositionByIndex(root, resourcesYamlField, 0)
+		Ω([]int{line, column}).Should(Equal([]int{0, 0}))
+	})
+
+	It("fails to register a rule with an incorrect ID", func() {
+		Ω(RegisterSemanticRule(namedRule(""))).Should(MatchError(emptyRuleIDMsg))
+		Ω(RegisterSemanticRule(namedRule("a,b"))).Should(MatchError(fmt.Sprintf(incorrectRuleIDMsg, "a,b")))
+		Ω(RegisterSemanticRule(namedRule("a b"))).Should(MatchError(fmt.Sprintf(incorrectRuleIDMsg, "a b")))
+	})
+})
@@ -0,0 +1,146 @@
+package validate
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/SAP/cloud-mta/mta"
+)
+
+const (
+	deployedAfterYamlField  = "deployed-after"
+	processedAfterYamlField = "processed-after"
+
+	afterEntryNotDefinedMsg = `the "%s" %s in the "%s" list of the "%s" %s is not defined`
+	afterSelfReferenceMsg   = `the "%s" %s cannot be in its own "%s" list`
+	afterCycleMsg           = `the "%s" lists of the %ss contain a cycle: %s`
+	afterCycleEntryMsg      = `"%s" (line %d)`
+)
+
+// orderedEntity - a module or resource which is deployed or processed after the entities of its list
+type orderedEntity struct {
+	name string
+	// after - the names of the entities in the list
+	after []string
+	// node - the node of the entity
+	node *yaml.Node
+	// afterNode - the node of the list, or nil
+	afterNode *yaml.Node
+}
+
+// checkDeploymentOrder - checks that the "deployed-after" lists of the modules and the "processed-after" lists of the
+// resources contain only other modules or resources which are defined, and that the lists don't contain cycles,
+// which the deployer can't resolve
+func checkDeploymentOrder(mta *mta.MTA, mtaNode *yaml.Node, source string, strict bool) ([]YamlValidationIssue, []YamlValidationIssue) {
+	modulesNode := getPropContent(mtaNode, modulesYamlField)
+	var modules []orderedEntity
+	for i, module := range mta.Modules {
+		if i < len(modulesNode) {
+			modules = append(modules, newOrderedEntity(module.Name, module.DeployedAfter, modulesNode[i], deployedAfterYamlField))
+		}
+	}
+	resourcesNode := getPropContent(mtaNode, resourcesYamlField)
+	var resources []orderedEntity
+	for i, resource := range mta.Resources {
+		if i < len(resourcesNode) {
+			resources = append(resources, newOrderedEntity(resource.Name, resource.ProcessedAfter, resourcesNode[i], processedAfterYamlField))
+		}
+	}
+
+	issues := checkOrderedEntities(modules, moduleEntityKind, deployedAfterYamlField)
+	issues = append(issues, checkOrderedEntities(resources, resourceEntityKind, processedAfterYamlField)...)
+	return issues, nil
+}
+
+func newOrderedEntity(name string, after []string, node *yaml.Node, listField string) orderedEntity {
+	afterNode := getPropValueByName(node, listField)
+	if afterNode != nil && afterNode.Kind != yaml.SequenceNode {
+		afterNode = nil
+	}
+	return orderedEntity{name: name, after: after, node: node, afterNode: afterNode}
+}
+
+// entryPosition returns the position of the entry of the list with the index, or the position of the entity's name
+// when the list can't be found
+func (entity orderedEntity) entryPosition(index int) (line int, column int) {
+	if entity.afterNode != nil && index < len(entity.afterNode.Content) {
+		return entity.afterNode.Content[index].Line, entity.afterNode.Content[index].Column
+	}
+	if nameNode := getPropValueByName(entity.node, nameYamlField); nameNode != nil {
+		return nameNode.Line, nameNode.Column
+	}
+	return entity.node.Line, entity.node.Column
+}
+
+func checkOrderedEntities(entities []orderedEntity, entityKind string, listField string) []YamlValidationIssue {
+	var issues []YamlValidationIssue
+
+	// The edges are the entries of the lists which reference other defined entities
+	indexes := make(map[string]int)
+	for i := len(entities) - 1; i >= 0; i-- {
+		indexes[entities[i].name] = i
+	}
+	edges := make([][]int, len(entities))
+	for i, entity := range entities {
+		for j, name := range entity.after {
+			line, column := entity.entryPosition(j)
+			_, ok := indexes[name]
+			switch {
+			case !ok:
+				issues = appendIssue(issues, fmt.Sprintf(afterEntryNotDefinedMsg, name, entityKind, listField, entity.name, entityKind), line, column)
+			case name == entity.name:
+				issues = appendIssue(issues, fmt.Sprintf(afterSelfReferenceMsg, name, entityKind, listField), line, column)
+			default:
+				edges[i] = append(edges[i], j)
+			}
+		}
+	}
+
+	// Each back edge of the depth-first search closes a cycle of the entities on the search path
+	const (
+		notVisited = iota
+		visiting
+		visited
+	)
+	states := make([]int, len(entities))
+	type pathEntry struct {
+		entity int
+		entry  int
+	}
+	var path []pathEntry
+	var visit func(i int)
+	visit = func(i int) {
+		states[i] = visiting
+		for _, j := range edges[i] {
+			target := indexes[entities[i].after[j]]
+			path = append(path, pathEntry{i, j})
+			switch states[target] {
+			case notVisited:
+				visit(target)
+			case visiting:
+				start := len(path) - 1
+				for path[start].entity != target {
+					start--
+				}
+				var cycle []string
+				for _, e := range path[start:] {
+					line, _ := entities[e.entity].entryPosition(e.entry)
+					cycle = append(cycle, fmt.Sprintf(afterCycleEntryMsg, entities[e.entity].name, line))
+				}
+				cycle = append(cycle, fmt.Sprintf(`"%s"`, entities[target].name))
+				line, column := entities[path[start].entity].entryPosition(path[start].entry)
+				issues = appendIssue(issues, fmt.Sprintf(afterCycleMsg, listField, entityKind, strings.Join(cycle, " -> ")), line, column)
+			}
+			path = path[:len(path)-1]
+		}
+		states[i] = visited
+	}
+	for i := range entities {
+		if states[i] == notVisited {
+			visit(i)
+		}
+	}
+	return issues
+}
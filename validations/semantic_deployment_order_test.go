@@ -0,0 +1,102 @@
+package validate
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/SAP/cloud-mta/mta"
+)
+
+var _ = Describe("SemanticDeploymentOrder", func() {
+	check := func(content []byte) []YamlValidationIssue {
+		mtaStr, err := mta.Unmarshal(content)
+		Ω(err).Should(Succeed())
+		node, err := getContentNode(content)
+		Ω(err).Should(Succeed())
+		errs, warns := checkDeploymentOrder(mtaStr, node, "", true)
+		Ω(warns).Should(BeEmpty())
+		return errs
+	}
+
+	It("Sanity", func() {
+		errs := check([]byte(`
+ID: mta.order
+_schema-version: '3.3'
+version: 0.0.1
+
+modules:
+- name: srv
+  type: nodejs
+  deployed-after: [db-deployer]
+- name: db-deployer
+  type: hdb
+- name: ui
+  type: html5
+  deployed-after: [srv, db-deployer]
+
+resources:
+- name: db
+  type: com.sap.xs.hdi-container
+- name: uaa
+  type: org.cloudfoundry.managed-service
+  processed-after: [db]
+`))
+		Ω(errs).Should(BeEmpty())
+	})
+
+	It("reports entries which are not defined and self-references", func() {
+		errs := check([]byte(`
+ID: mta.order
+_schema-version: '3.3'
+version: 0.0.1
+
+modules:
+- name: srv
+  type: nodejs
+  deployed-after:
+  - srv
+  - db
+resources:
+- name: db
+  type: com.sap.xs.hdi-container
+  processed-after: [srv]
+`))
+		Ω(errs).Should(Equal([]YamlValidationIssue{
+			{Msg: fmt.Sprintf(afterSelfReferenceMsg, "srv", moduleEntityKind, deployedAfterYamlField), Line: 10, Column: 5},
+			{Msg: fmt.Sprintf(afterEntryNotDefinedMsg, "db", moduleEntityKind, deployedAfterYamlField, "srv", moduleEntityKind), Line: 11, Column: 5},
+			{Msg: fmt.Sprintf(afterEntryNotDefinedMsg, "srv", resourceEntityKind, processedAfterYamlField, "db", resourceEntityKind), Line: 15, Column: 21},
+		}))
+	})
+
+	It("reports the cycles with the lines of their entries", func() {
+		errs := check([]byte(`
+ID: mta.order
+_schema-version: '3.3'
+version: 0.0.1
+
+modules:
+- name: a
+  type: nodejs
+  deployed-after: [b]
+- name: b
+  type: nodejs
+  deployed-after: [c]
+- name: c
+  type: nodejs
+  deployed-after: [a]
+resources:
+- name: x
+  type: org.cloudfoundry.managed-service
+  processed-after: [y]
+- name: y
+  type: org.cloudfoundry.managed-service
+  processed-after: [x]
+`))
+		Ω(errs).Should(Equal([]YamlValidationIssue{
+			{Msg: fmt.Sprintf(afterCycleMsg, deployedAfterYamlField, moduleEntityKind, `"a" (line 9) -> "b" (line 12) -> "c" (line 15) -> "a"`), Line: 9, Column: 20},
+			{Msg: fmt.Sprintf(afterCycleMsg, processedAfterYamlField, resourceEntityKind, `"x" (line 19) -> "y" (line 22) -> "x"`), Line: 19, Column: 21},
+		}))
+	})
+})
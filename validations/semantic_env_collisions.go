@@ -0,0 +1,58 @@
+package validate
+
+import (
+	"gopkg.in/yaml.v3"
+
+	"github.com/SAP/cloud-mta/internal/resolver"
+	"github.com/SAP/cloud-mta/mta"
+)
+
+// checkEnvVarCollisions - checks that each environment variable of a module is defined once, by the module's properties,
+// the properties of a required dependency or a group or list name. When a variable is defined several times,
+// the deployer uses the value of the last definition.
+func checkEnvVarCollisions(mta *mta.MTA, mtaNode *yaml.Node, source string, strict bool) ([]YamlValidationIssue, []YamlValidationIssue) {
+	var warnings []YamlValidationIssue
+
+	modulesNode := getPropContent(mtaNode, modulesYamlField)
+	for i, module := range mta.Modules {
+		if i >= len(modulesNode) {
+			break
+		}
+		for _, collision := range resolver.GetEnvVarCollisions(module) {
+			// The issue is reported on the definition which is used
+			node := getEnvVarDefinitionNode(module, modulesNode[i], collision.Name, collision.Definitions[len(collision.Definitions)-1])
+			warnings = appendIssue(warnings, collision.Message(module.Name), node.Line, node.Column)
+		}
+	}
+	return nil, warnings
+}
+
+// getEnvVarDefinitionNode - gets the node of the property or the group or list name which defines the variable,
+// or the node of the module or the requires section when it's not found
+func getEnvVarDefinitionNode(module *mta.Module, moduleNode *yaml.Node, name string, definition resolver.EnvVarDefinition) *yaml.Node {
+	node := moduleNode
+	if definition.RequiresIndex >= 0 {
+		requiresNodes := getPropContent(moduleNode, requiresYamlField)
+		if definition.RequiresIndex >= len(requiresNodes) {
+			return moduleNode
+		}
+		node = requiresNodes[definition.RequiresIndex]
+		requires := module.Requires[definition.RequiresIndex]
+		field := ""
+		if len(requires.List) > 0 {
+			field = listYamlField
+		} else if len(requires.Group) > 0 {
+			field = groupYamlField
+		}
+		if len(field) > 0 {
+			if nameNode := getPropValueByName(node, field); nameNode != nil {
+				return nameNode
+			}
+			return node
+		}
+	}
+	if keyNode := getPropByName(getPropValueByName(node, propertiesYamlField), name); keyNode != nil {
+		return keyNode
+	}
+	return node
+}
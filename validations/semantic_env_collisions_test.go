@@ -0,0 +1,59 @@
+package validate
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/SAP/cloud-mta/mta"
+)
+
+var _ = Describe("SemanticEnvVarCollisions", func() {
+	It("Sanity", func() {
+		mtaContent := []byte(`
+ID: mtacollisions
+_schema-version: '3.2'
+version: 0.0.1
+
+modules:
+ - name: srv
+   type: nodejs
+   properties:
+     url: module
+     plugins: module
+     port: 8080
+   requires:
+   - name: db
+     properties:
+       url: ~{url}
+   - name: audit
+     list: plugins
+     properties:
+       name: audit
+   - name: metrics
+     list: plugins
+     properties:
+       name: metrics
+
+ - name: ui
+   type: html5
+   properties:
+     url: ui
+   requires:
+   - name: srv-api
+     group: destinations
+     properties:
+       url: ~{url}
+`)
+		mta, _ := mta.Unmarshal(mtaContent)
+		node, _ := getContentNode(mtaContent)
+		errors, warnings := checkEnvVarCollisions(mta, node, "", true)
+		Ω(errors).Should(BeEmpty())
+		Ω(warnings).Should(HaveLen(2))
+		Ω(warnings[0].Msg).Should(Equal(`the "plugins" environment variable of the "srv" module is defined by ` +
+			`the module properties, the "plugins" list; the deployer uses the value of the "plugins" list`))
+		Ω(warnings[0].Line).Should(Equal(18))
+		Ω(warnings[1].Msg).Should(Equal(`the "url" environment variable of the "srv" module is defined by ` +
+			`the module properties, the "db" required dependency; the deployer uses the value of the "db" required dependency`))
+		Ω(warnings[1].Line).Should(Equal(16))
+	})
+})
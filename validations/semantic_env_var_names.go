@@ -0,0 +1,142 @@
+package validate
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/SAP/cloud-mta/mta"
+)
+
+const (
+	unsafeEnvVarNameMsg = `the "%s" %s of %s cannot be used as an environment variable name; ` +
+		`use only letters, digits and underscores, and don't start with a digit`
+	envVarNameNotUniqueMsg = `the "%s" %s of %s is not unique when it is converted to upper case; ` +
+		`the "%s" name on line %d is also converted to "%s"`
+
+	propertyNameKind = "property name"
+	groupNameKind    = "group name"
+	listNameKind     = "list name"
+
+	moduleNameOwner          = `the "%s" module`
+	requiresNameOwner        = `the "%s" dependency required by the "%s" module`
+	providedPropertySetOwner = `the "%s" property set provided by the "%s" module`
+)
+
+// envVarNameRegExp - the environment variable names of the portable character set, which don't start with a digit
+var envVarNameRegExp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// upperCaseNames - the names which are used as environment variables, by their upper case
+type upperCaseNames map[string]*yaml.Node
+
+// checkEnvVarNames - checks that the names which are used as environment variable names contain only letters, digits
+// and underscores, don't start with a digit, and stay unique when they are converted to upper case.
+// The names are the names of the module properties, the properties of the requires sections, the groups and the lists,
+// and the properties of the provided property sets which are required without properties, a group or a list.
+// The issues are warnings also in the strict mode: the deployer accepts these names, but they can't be read as
+// environment variables by all the runtimes.
+func checkEnvVarNames(mta *mta.MTA, mtaNode *yaml.Node, source string, strict bool) ([]YamlValidationIssue, []YamlValidationIssue) {
+	var unsafeNames []YamlValidationIssue
+	var warnings []YamlValidationIssue
+
+	// The provided property sets whose properties are exposed as they are
+	directlyRequired := make(map[string]bool)
+
+	modulesNode := getPropContent(mtaNode, modulesYamlField)
+	for i, module := range mta.Modules {
+		if i >= len(modulesNode) {
+			break
+		}
+		moduleNode := modulesNode[i]
+		names := make(upperCaseNames)
+		owner := fmt.Sprintf(moduleNameOwner, module.Name)
+
+		unsafe, notUnique := checkPropertiesEnvVarNames(getPropValueByName(moduleNode, propertiesYamlField), owner, names)
+		unsafeNames = append(unsafeNames, unsafe...)
+		warnings = append(warnings, notUnique...)
+
+		requiresNodes := getPropContent(moduleNode, requiresYamlField)
+		for j, requires := range module.Requires {
+			if j >= len(requiresNodes) {
+				break
+			}
+			requiresNode := requiresNodes[j]
+			field, kind := groupYamlField, groupNameKind
+			if len(requires.List) > 0 {
+				field, kind = listYamlField, listNameKind
+			}
+			nameNode := getPropValueByName(requiresNode, field)
+			propertiesNode := getPropValueByName(requiresNode, propertiesYamlField)
+			switch {
+			case nameNode != nil && nameNode.Kind == yaml.ScalarNode:
+				unsafeNames = append(unsafeNames, checkEnvVarName(nameNode, kind, fmt.Sprintf(requiresNameOwner, requires.Name, module.Name))...)
+				warnings = append(warnings, checkUpperCaseName(nameNode, kind, owner, names)...)
+			case propertiesNode != nil:
+				unsafe, notUnique = checkPropertiesEnvVarNames(propertiesNode, fmt.Sprintf(requiresNameOwner, requires.Name, module.Name), names)
+				unsafeNames = append(unsafeNames, unsafe...)
+				warnings = append(warnings, notUnique...)
+			default:
+				directlyRequired[requires.Name] = true
+			}
+		}
+	}
+
+	for i, module := range mta.Modules {
+		if i >= len(modulesNode) {
+			break
+		}
+		providesNodes := getPropContent(modulesNode[i], providesYamlField)
+		for j, provides := range module.Provides {
+			if j >= len(providesNodes) || !directlyRequired[provides.Name] {
+				continue
+			}
+			owner := fmt.Sprintf(providedPropertySetOwner, provides.Name, module.Name)
+			unsafe, notUnique := checkPropertiesEnvVarNames(getPropValueByName(providesNodes[j], propertiesYamlField), owner, make(upperCaseNames))
+			unsafeNames = append(unsafeNames, unsafe...)
+			warnings = append(warnings, notUnique...)
+		}
+	}
+
+	return nil, append(warnings, unsafeNames...)
+}
+
+// checkPropertiesEnvVarNames - checks the names of the properties of the owner, and adds them to the names
+func checkPropertiesEnvVarNames(propertiesNode *yaml.Node, owner string, names upperCaseNames) (unsafeNames []YamlValidationIssue, notUnique []YamlValidationIssue) {
+	if propertiesNode == nil || propertiesNode.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(propertiesNode.Content); i += 2 {
+		keyNode := propertiesNode.Content[i]
+		unsafeNames = append(unsafeNames, checkEnvVarName(keyNode, propertyNameKind, owner)...)
+		notUnique = append(notUnique, checkUpperCaseName(keyNode, propertyNameKind, owner, names)...)
+	}
+	return unsafeNames, notUnique
+}
+
+func checkEnvVarName(nameNode *yaml.Node, kind string, owner string) []YamlValidationIssue {
+	if envVarNameRegExp.MatchString(nameNode.Value) {
+		return nil
+	}
+	return []YamlValidationIssue{{Msg: fmt.Sprintf(unsafeEnvVarNameMsg, nameNode.Value, kind, owner), Line: nameNode.Line, Column: nameNode.Column}}
+}
+
+// checkUpperCaseName - checks that the name is not another name in upper case, and adds it to the names.
+// Names which are defined several times are reported by the environment variable collisions validation.
+func checkUpperCaseName(nameNode *yaml.Node, kind string, owner string, names upperCaseNames) []YamlValidationIssue {
+	upperCaseName := strings.ToUpper(nameNode.Value)
+	existing, ok := names[upperCaseName]
+	if !ok {
+		names[upperCaseName] = nameNode
+		return nil
+	}
+	if existing.Value == nameNode.Value {
+		return nil
+	}
+	return []YamlValidationIssue{{
+		Msg:    fmt.Sprintf(envVarNameNotUniqueMsg, nameNode.Value, kind, owner, existing.Value, existing.Line, upperCaseName),
+		Line:   nameNode.Line,
+		Column: nameNode.Column,
+	}}
+}
@@ -0,0 +1,83 @@
+package validate
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/SAP/cloud-mta/mta"
+)
+
+var _ = Describe("SemanticEnvVarNames", func() {
+	mtaContent := []byte(`
+ID: mta.envvars
+_schema-version: '3.2'
+version: 0.0.1
+
+modules:
+- name: srv
+  type: nodejs
+  properties:
+    API_URL: module
+    api-key: module
+    1st: module
+  provides:
+  - name: srv-api
+    properties:
+      url: srv
+      Url: srv
+  - name: srv-config
+    properties:
+      log.level: info
+  requires:
+  - name: db
+    properties:
+      api_url: ~{url}
+  - name: audit
+    list: audit-plugins
+    properties:
+      name.with.dots: audit
+  - name: metrics
+    group: Api_Url
+    properties:
+      url: metrics
+
+- name: ui
+  type: html5
+  requires:
+  - name: srv-api
+  - name: srv-config
+    properties:
+      LOG_LEVEL: ~{log.level}
+`)
+	var mtaStr *mta.MTA
+	BeforeEach(func() {
+		var err error
+		mtaStr, err = mta.Unmarshal(mtaContent)
+		Ω(err).Should(Succeed())
+	})
+
+	srvOwner := fmt.Sprintf(moduleNameOwner, "srv")
+	unsafeNames := []YamlValidationIssue{
+		{Msg: fmt.Sprintf(unsafeEnvVarNameMsg, "api-key", propertyNameKind, srvOwner), Line: 11, Column: 5},
+		{Msg: fmt.Sprintf(unsafeEnvVarNameMsg, "1st", propertyNameKind, srvOwner), Line: 12, Column: 5},
+		{Msg: fmt.Sprintf(unsafeEnvVarNameMsg, "audit-plugins", listNameKind, fmt.Sprintf(requiresNameOwner, "audit", "srv")), Line: 26, Column: 11},
+	}
+	notUniqueNames := []YamlValidationIssue{
+		{Msg: fmt.Sprintf(envVarNameNotUniqueMsg, "api_url", propertyNameKind, fmt.Sprintf(requiresNameOwner, "db", "srv"), "API_URL", 10, "API_URL"), Line: 24, Column: 7},
+		{Msg: fmt.Sprintf(envVarNameNotUniqueMsg, "Api_Url", groupNameKind, srvOwner, "API_URL", 10, "API_URL"), Line: 30, Column: 12},
+		{Msg: fmt.Sprintf(envVarNameNotUniqueMsg, "Url", propertyNameKind, fmt.Sprintf(providedPropertySetOwner, "srv-api", "srv"), "url", 16, "URL"), Line: 17, Column: 7},
+	}
+
+	var _ = table.DescribeTable("reports unsafe names as warnings", func(strict bool) {
+		node, _ := getContentNode(mtaContent)
+		errs, warns := checkEnvVarNames(mtaStr, node, "", strict)
+		Ω(errs).Should(BeEmpty())
+		Ω(warns).Should(Equal(append(append([]YamlValidationIssue{}, notUniqueNames...), unsafeNames...)))
+	},
+		table.Entry("in the strict mode", true),
+		table.Entry("in the non-strict mode", false),
+	)
+})
@@ -2,7 +2,6 @@ package validate
 
 import (
 	"gopkg.in/yaml.v3"
-	"strings"
 
 	"github.com/SAP/cloud-mta/mta"
 )
@@ -26,11 +25,20 @@ func runExtSemanticValidations(mtaExt *mta.EXT, root *yaml.Node, source string,
 // getSemanticValidations - gets list of all semantic validations minus excludes validations
 func getExtSemanticValidations(exclude string) []checkExtSemantic {
 	var validations []checkExtSemantic
-	if !strings.Contains(exclude, namesValidation) {
-		validations = append(validations, checkSingleExtendNames)
+	excluded := getExcludedValidations(exclude)
+	if !excluded[namesValidation] {
+		validations = append(validations, extSemanticRule(namesValidation, checkSingleExtendNames))
 	}
-	if !strings.Contains(exclude, deprecatedOptsValidation) {
-		validations = append(validations, checkExtDeprecatedOpts)
+	if !excluded[deprecatedOptsValidation] {
+		validations = append(validations, extSemanticRule(deprecatedOptsValidation, checkExtDeprecatedOpts))
 	}
 	return validations
 }
+
+// extSemanticRule - gets the semantic validation of the extension which reports its issues with the ID of the rule
+func extSemanticRule(rule string, validation checkExtSemantic) checkExtSemantic {
+	return func(mtaExt *mta.EXT, root *yaml.Node, source string, strict bool) ([]YamlValidationIssue, []YamlValidationIssue) {
+		errors, warnings := validation(mtaExt, root, source, strict)
+		return setIssuesRule(errors, rule), setIssuesRule(warnings, rule)
+	}
+}
@@ -130,22 +130,22 @@ resources:
 			errors, warn := checkParamsAndPropertiesMetadata(mta, node, "", true)
 			Ω(len(warn)).Should(Equal(0))
 			Ω(errors).Should(ConsistOf(
-				YamlValidationIssue{fmt.Sprintf(unknownNameInMetadataMsg, "b", "parameter"), 11, 3},
-				YamlValidationIssue{fmt.Sprintf(emptyRequiredFieldMsg, "memory", "parameter"), 18, 6},
-				YamlValidationIssue{fmt.Sprintf(unknownNameInMetadataMsg, "x", "property"), 27, 6},
-				YamlValidationIssue{fmt.Sprintf(unknownNameInMetadataMsg, "b", "parameter"), 34, 10},
-				YamlValidationIssue{fmt.Sprintf(unknownNameInMetadataMsg, "a", "parameter"), 41, 14},
-				YamlValidationIssue{fmt.Sprintf(unknownNameInMetadataMsg, "b", "property"), 51, 10},
-				YamlValidationIssue{fmt.Sprintf(unknownNameInMetadataMsg, "a", "parameter"), 56, 10},
-				YamlValidationIssue{propertiesMetadataWithListOrGroupMsg, 50, 8},
-				YamlValidationIssue{fmt.Sprintf(unknownNameInMetadataMsg, "b", "property"), 65, 10},
-				YamlValidationIssue{fmt.Sprintf(unknownNameInMetadataMsg, "m", "parameter"), 74, 6},
-				YamlValidationIssue{fmt.Sprintf(emptyRequiredFieldMsg, "b", "property"), 81, 6},
-				YamlValidationIssue{fmt.Sprintf(unknownNameInMetadataMsg, "b", "property"), 94, 10},
-				YamlValidationIssue{fmt.Sprintf(unknownNameInMetadataMsg, "a", "parameter"), 99, 10},
-				YamlValidationIssue{propertiesMetadataWithListOrGroupMsg, 93, 8},
-				YamlValidationIssue{propertiesMetadataWithListOrGroupMsg, 107, 6},
-				YamlValidationIssue{propertiesMetadataWithListOrGroupMsg, 110, 6},
+				YamlValidationIssue{Msg: fmt.Sprintf(unknownNameInMetadataMsg, "b", "parameter"), Line: 11, Column: 3},
+				YamlValidationIssue{Msg: fmt.Sprintf(emptyRequiredFieldMsg, "memory", "parameter"), Line: 18, Column: 6},
+				YamlValidationIssue{Msg: fmt.Sprintf(unknownNameInMetadataMsg, "x", "property"), Line: 27, Column: 6},
+				YamlValidationIssue{Msg: fmt.Sprintf(unknownNameInMetadataMsg, "b", "parameter"), Line: 34, Column: 10},
+				YamlValidationIssue{Msg: fmt.Sprintf(unknownNameInMetadataMsg, "a", "parameter"), Line: 41, Column: 14},
+				YamlValidationIssue{Msg: fmt.Sprintf(unknownNameInMetadataMsg, "b", "property"), Line: 51, Column: 10},
+				YamlValidationIssue{Msg: fmt.Sprintf(unknownNameInMetadataMsg, "a", "parameter"), Line: 56, Column: 10},
+				YamlValidationIssue{Msg: propertiesMetadataWithListOrGroupMsg, Line: 50, Column: 8},
+				YamlValidationIssue{Msg: fmt.Sprintf(unknownNameInMetadataMsg, "b", "property"), Line: 65, Column: 10},
+				YamlValidationIssue{Msg: fmt.Sprintf(unknownNameInMetadataMsg, "m", "parameter"), Line: 74, Column: 6},
+				YamlValidationIssue{Msg: fmt.Sprintf(emptyRequiredFieldMsg, "b", "property"), Line: 81, Column: 6},
+				YamlValidationIssue{Msg: fmt.Sprintf(unknownNameInMetadataMsg, "b", "property"), Line: 94, Column: 10},
+				YamlValidationIssue{Msg: fmt.Sprintf(unknownNameInMetadataMsg, "a", "parameter"), Line: 99, Column: 10},
+				YamlValidationIssue{Msg: propertiesMetadataWithListOrGroupMsg, Line: 93, Column: 8},
+				YamlValidationIssue{Msg: propertiesMetadataWithListOrGroupMsg, Line: 107, Column: 6},
+				YamlValidationIssue{Msg: propertiesMetadataWithListOrGroupMsg, Line: 110, Column: 6},
 			))
 		})
 
@@ -0,0 +1,501 @@
+package validate
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/SAP/cloud-mta/internal/fs"
+	"github.com/SAP/cloud-mta/mta"
+)
+
+// PolicyFileName - the name of the policy file in the project folder, e.g.
+//
+//	policies:
+//	- name: no-standard-hana-plan
+//	  select:
+//	    kind: resource
+//	    type: com.sap.xs.hdi-container
+//	  assert:
+//	  - parameter: service-plan
+//	    not-equals: standard
+//	- name: no-standard-hana-plan-in-dev
+//	  select:
+//	    kind: resource
+//	    type: com.sap.xs.hdi-container
+//	    extension: "*.dev"
+//	  assert:
+//	  - parameter: service-plan
+//	    not-equals: standard
+//	- name: nodejs-memory
+//	  severity: warning
+//	  message: the nodejs modules must not use more than 1G of memory
+//	  select:
+//	    kind: module
+//	    type: nodejs
+//	  assert:
+//	  - parameter: memory
+//	    exists: true
+//	    max-size: 1G
+//
+// The types and names of the selectors are glob patterns. The parameters, properties and build parameters of the
+// assertions can be nested, e.g. "config.tags". Conditions other than "exists" are checked only when the value is defined.
+//
+// The extension of a selector is a glob pattern of the IDs of the MTA extension descriptors, e.g. of the descriptors of
+// an environment. Policies with an extension are checked only when the MTA is validated with a matching extension
+// descriptor, against the MTA descriptor merged with its extension descriptors; the violations are reported on the
+// descriptor which defines the value. The other policies are checked against the MTA descriptor.
+const PolicyFileName = ".mtapolicy.yaml"
+
+const (
+	readPolicyFileFailedMsg = `could not read the "%s" policy file`
+	policyViolatedMsg       = `the "%s" policy is violated: %s`
+
+	policyValueNotDefinedMsg    = `the "%s" %s of the "%s" %s is not defined`
+	policyValueDefinedMsg       = `the "%s" %s of the "%s" %s must not be defined`
+	policyValueNotEqualMsg      = `the "%s" %s of the "%s" %s must be "%s"`
+	policyValueEqualMsg         = `the "%s" %s of the "%s" %s must not be "%s"`
+	policyValueNotMatchMsg      = `the "%s" %s of the "%s" %s must match the "%s" pattern`
+	policyValueNotContainMsg    = `the "%s" %s of the "%s" %s must contain "%s"`
+	policyValueNotNumberMsg     = `the "%s" %s of the "%s" %s must be a number`
+	policyValueNotSizeMsg       = `the "%s" %s of the "%s" %s must be a size, e.g. 512M or 1G`
+	policyValueTooSmallMsg      = `the "%s" %s of the "%s" %s must be at least %s`
+	policyValueTooLargeMsg      = `the "%s" %s of the "%s" %s must be at most %s`
+	policyNameNotDefinedMsg     = `the name of the policy with the %d index is not defined`
+	policyKindIncorrectMsg      = `the "%s" kind of the "%s" policy is incorrect; expected one of the following: module, resource`
+	policyPatternIncorrectMsg   = `the "%s" pattern of the "%s" policy is incorrect`
+	policySizeIncorrectMsg      = `the "%s" size of the "%s" policy is incorrect`
+	policySeverityIncorrectMsg  = `the "%s" severity of the "%s" policy is incorrect; expected one of the following: error, warning`
+	policyAssertionValueMsg     = `the assertion with the %d index of the "%s" policy must define one of the following: parameter, property, build-parameter`
+	policyAssertionConditionMsg = `the assertion with the %d index of the "%s" policy does not define any condition`
+)
+
+var sizeRegExp = regexp.MustCompile(`(?i)^\s*(\d+(?:\.\d+)?)\s*([kmgt]?)b?\s*$`)
+
+var sizeUnits = map[string]float64{"": 1, "k": 1 << 10, "m": 1 << 20, "g": 1 << 30, "t": 1 << 40}
+
+type policyFile struct {
+	Policies []policy `yaml:"policies"`
+}
+
+// policy - a policy, which asserts the values of the selected modules or resources
+type policy struct {
+	Name string `yaml:"name"`
+	// Message - replaces the description of the violated assertion in the issue
+	Message string `yaml:"message"`
+	// Severity - the severity of the violations: "error" (default) or "warning"
+	Severity string            `yaml:"severity"`
+	Select   policySelector    `yaml:"select"`
+	Assert   []policyAssertion `yaml:"assert"`
+}
+
+type policySelector struct {
+	// Kind - "module" or "resource"
+	Kind string `yaml:"kind"`
+	Type string `yaml:"type"`
+	Name string `yaml:"name"`
+	// Extension - the pattern of the IDs of the extension descriptors with which the policy is checked
+	Extension string `yaml:"extension"`
+}
+
+type policyAssertion struct {
+	Parameter      string   `yaml:"parameter"`
+	Property       string   `yaml:"property"`
+	BuildParameter string   `yaml:"build-parameter"`
+	Exists         *bool    `yaml:"exists"`
+	Equals         *string  `yaml:"equals"`
+	NotEquals      *string  `yaml:"not-equals"`
+	Matches        string   `yaml:"matches"`
+	Contains       *string  `yaml:"contains"`
+	Min            *float64 `yaml:"min"`
+	Max            *float64 `yaml:"max"`
+	MinSize        string   `yaml:"min-size"`
+	MaxSize        string   `yaml:"max-size"`
+
+	pattern *regexp.Regexp
+}
+
+// policyDescriptor - a descriptor which is checked by the policies: the MTA descriptor or one of its extension
+// descriptors, in the order in which they are merged
+type policyDescriptor struct {
+	path    string
+	id      string
+	content []byte
+	root    *yaml.Node
+}
+
+// policyIssues - the violations of the policies in a descriptor
+type policyIssues struct {
+	errors   []YamlValidationIssue
+	warnings []YamlValidationIssue
+}
+
+// checkPolicies - checks the policies of the policy file in the project folder, if it exists
+func checkPolicies(mta *mta.MTA, mtaNode *yaml.Node, source string, strict bool) ([]YamlValidationIssue, []YamlValidationIssue) {
+	policies, err := loadPolicies(source)
+	if err != nil {
+		return appendIssue(nil, err.Error(), 0, 0), nil
+	}
+	var mtaPolicies []policy
+	for _, p := range policies {
+		if p.Select.Extension == "" {
+			mtaPolicies = append(mtaPolicies, p)
+		}
+	}
+	return checkPoliciesOnNode(mtaPolicies, mtaNode)
+}
+
+// checkExtensionPolicies - checks the policies with extensions of the policy file in the project folder against the
+// MTA descriptor merged with the extension descriptors, which are merged without errors. The suppression comments
+// and the configuration are applied to the issues, which are returned by the paths of the descriptors.
+// The errors of the policy file are reported by the validation of the MTA descriptor.
+func checkExtensionPolicies(projectPath string, mtaPath string, extPaths []string, config *Config) map[string]policyIssues {
+	policies, err := loadPolicies(projectPath)
+	if err != nil || len(policies) == 0 {
+		return nil
+	}
+	descriptors, err := getPolicyDescriptors(mtaPath, extPaths)
+	if err != nil {
+		return nil
+	}
+	var extPolicies []policy
+	for _, p := range policies {
+		for _, descriptor := range descriptors[1:] {
+			if p.Select.Extension != "" && matchesPattern(p.Select.Extension, descriptor.id) {
+				extPolicies = append(extPolicies, p)
+				break
+			}
+		}
+	}
+	result := make(map[string]policyIssues)
+	for i, issues := range checkPoliciesOnDescriptors(extPolicies, descriptors) {
+		suppressions := getSuppressions(descriptors[i].root, lineBreakRegExp.Split(string(descriptors[i].content), -1))
+		errIssues := removeSuppressedIssues(suppressions, setIssuesRule(issues.errors, policyValidation))
+		warnIssues := removeSuppressedIssues(suppressions, setIssuesRule(issues.warnings, policyValidation))
+		errIssues, warnIssues = config.apply(descriptors[i].root, i > 0, errIssues, warnIssues)
+		if len(errIssues) > 0 || len(warnIssues) > 0 {
+			result[descriptors[i].path] = policyIssues{errors: errIssues, warnings: warnIssues}
+		}
+	}
+	return result
+}
+
+// getPolicyDescriptors returns the MTA descriptor and its extension descriptors in the order of the extends chain
+func getPolicyDescriptors(mtaPath string, extPaths []string) ([]policyDescriptor, error) {
+	mtaDescriptor, err := readPolicyDescriptor(mtaPath)
+	if err != nil {
+		return nil, err
+	}
+	extends := make(map[string]policyDescriptor)
+	for _, extPath := range extPaths {
+		descriptor, err := readPolicyDescriptor(extPath)
+		if err != nil {
+			return nil, err
+		}
+		extends[getScalarPropValue(descriptor.root, extendsYamlField)] = descriptor
+	}
+	descriptors := []policyDescriptor{mtaDescriptor}
+	for current, ok := extends[mtaDescriptor.id]; ok && len(descriptors) <= len(extPaths); current, ok = extends[current.id] {
+		descriptors = append(descriptors, current)
+	}
+	return descriptors, nil
+}
+
+func readPolicyDescriptor(path string) (policyDescriptor, error) {
+	content, err := fs.ReadFile(path)
+	if err != nil {
+		return policyDescriptor{}, err
+	}
+	root, err := getContentNode(content)
+	if err != nil {
+		return policyDescriptor{}, err
+	}
+	return policyDescriptor{path: path, id: getScalarPropValue(root, idYamlField), content: content, root: root}, nil
+}
+
+func loadPolicies(projectPath string) ([]policy, error) {
+	policyPath := filepath.Join(projectPath, PolicyFileName)
+	if _, err := os.Stat(policyPath); os.IsNotExist(err) {
+		return nil, nil
+	}
+	content, err := fs.ReadFile(policyPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, readPolicyFileFailedMsg, policyPath)
+	}
+	policies, err := parsePolicies(content)
+	if err != nil {
+		return nil, errors.Wrapf(err, readPolicyFileFailedMsg, policyPath)
+	}
+	return policies, nil
+}
+
+func parsePolicies(content []byte) ([]policy, error) {
+	file := policyFile{}
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	for i := range file.Policies {
+		p := &file.Policies[i]
+		if p.Name == "" {
+			return nil, errors.Errorf(policyNameNotDefinedMsg, i)
+		}
+		if p.Select.Kind != moduleEntityKind && p.Select.Kind != resourceEntityKind {
+			return nil, errors.Errorf(policyKindIncorrectMsg, p.Select.Kind, p.Name)
+		}
+		if p.Severity != "" && p.Severity != SeverityError && p.Severity != SeverityWarning {
+			return nil, errors.Errorf(policySeverityIncorrectMsg, p.Severity, p.Name)
+		}
+		for _, pattern := range []string{p.Select.Type, p.Select.Name, p.Select.Extension} {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, errors.Errorf(policyPatternIncorrectMsg, pattern, p.Name)
+			}
+		}
+		for j := range p.Assert {
+			if err := p.Assert[j].init(j, p.Name); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return file.Policies, nil
+}
+
+// init validates the assertion and compiles its pattern
+func (a *policyAssertion) init(index int, policyName string) error {
+	if _, _, valueName := a.value(); valueName == "" {
+		return errors.Errorf(policyAssertionValueMsg, index, policyName)
+	}
+	if a.Exists == nil && a.Equals == nil && a.NotEquals == nil && a.Matches == "" && a.Contains == nil &&
+		a.Min == nil && a.Max == nil && a.MinSize == "" && a.MaxSize == "" {
+		return errors.Errorf(policyAssertionConditionMsg, index, policyName)
+	}
+	if a.Matches != "" {
+		pattern, err := regexp.Compile(a.Matches)
+		if err != nil {
+			return errors.Errorf(policyPatternIncorrectMsg, a.Matches, policyName)
+		}
+		a.pattern = pattern
+	}
+	for _, size := range []string{a.MinSize, a.MaxSize} {
+		if _, ok := parseSize(size); size != "" && !ok {
+			return errors.Errorf(policySizeIncorrectMsg, size, policyName)
+		}
+	}
+	return nil
+}
+
+// value returns the field of the asserted value, the kind of the value and its name
+func (a *policyAssertion) value() (field string, kind string, name string) {
+	switch {
+	case a.Parameter != "":
+		return parametersYamlField, parameterEntityKind, a.Parameter
+	case a.Property != "":
+		return propertiesYamlField, propertyEntityKind, a.Property
+	case a.BuildParameter != "":
+		return buildParametersYamlField, buildParamEntityKind, a.BuildParameter
+	}
+	return "", "", ""
+}
+
+func checkPoliciesOnNode(policies []policy, mtaNode *yaml.Node) (errors []YamlValidationIssue, warnings []YamlValidationIssue) {
+	issues := checkPoliciesOnDescriptors(policies, []policyDescriptor{{root: mtaNode}})
+	return issues[0].errors, issues[0].warnings
+}
+
+// checkPoliciesOnDescriptors checks the policies on the modules and resources of the MTA descriptor, which is the first
+// descriptor. Their values are looked up in the descriptors in the reverse order, as if they were merged.
+// The issues are returned by the indexes of the descriptors.
+func checkPoliciesOnDescriptors(policies []policy, descriptors []policyDescriptor) []policyIssues {
+	issues := make([]policyIssues, len(descriptors))
+	for _, p := range policies {
+		entitiesField := modulesYamlField
+		if p.Select.Kind == resourceEntityKind {
+			entitiesField = resourcesYamlField
+		}
+		entitiesNode := getPropValueByName(descriptors[0].root, entitiesField)
+		if entitiesNode == nil || entitiesNode.Kind != yaml.SequenceNode {
+			continue
+		}
+		for index, entityNode := range entitiesNode.Content {
+			name := getScalarPropValue(entityNode, nameYamlField)
+			if !matchesPattern(p.Select.Name, name) || !matchesPattern(p.Select.Type, getScalarPropValue(entityNode, typeYamlField)) {
+				continue
+			}
+			entityNodes := []*yaml.Node{entityNode}
+			for _, descriptor := range descriptors[1:] {
+				entityNodes = append(entityNodes, getEntityNodeByName(descriptor.root, entitiesField, name))
+			}
+			for _, a := range p.Assert {
+				violations, descriptorIndex := a.check(entityNodes, p.Select.Kind, name)
+				for _, violation := range violations {
+					line, column := violation.Line, violation.Column
+					if violation.Line == 0 {
+						line, column, _ = getIndexedNodePropPosition(entitiesNode, index, nameYamlField)
+					}
+					msg := violation.Msg
+					if p.Message != "" {
+						msg = p.Message
+					}
+					descriptorIssues := &issues[descriptorIndex]
+					if p.Severity == SeverityWarning {
+						descriptorIssues.warnings = appendIssue(descriptorIssues.warnings, fmt.Sprintf(policyViolatedMsg, p.Name, msg), line, column)
+					} else {
+						descriptorIssues.errors = appendIssue(descriptorIssues.errors, fmt.Sprintf(policyViolatedMsg, p.Name, msg), line, column)
+					}
+				}
+			}
+		}
+	}
+	return issues
+}
+
+// getEntityNodeByName returns the node of the module or resource with the name in the descriptor, or nil
+func getEntityNodeByName(root *yaml.Node, entitiesField string, name string) *yaml.Node {
+	entitiesNode := getPropValueByName(root, entitiesField)
+	if entitiesNode == nil || entitiesNode.Kind != yaml.SequenceNode {
+		return nil
+	}
+	for _, entityNode := range entitiesNode.Content {
+		if getScalarPropValue(entityNode, nameYamlField) == name {
+			return entityNode
+		}
+	}
+	return nil
+}
+
+// lookupValue returns the node of the asserted value and the index of the entity node which defines it. The values
+// of the later entity nodes take precedence; the index of a value which is not defined is 0.
+func (a *policyAssertion) lookupValue(entityNodes []*yaml.Node) (*yaml.Node, int) {
+	field, _, valueName := a.value()
+	for i := len(entityNodes) - 1; i >= 0; i-- {
+		valueNode := getPropValueByName(entityNodes[i], field)
+		for _, key := range strings.Split(valueName, ".") {
+			valueNode = getPropValueByName(resolveAlias(valueNode), key)
+		}
+		if valueNode = resolveAlias(valueNode); valueNode != nil {
+			return valueNode, i
+		}
+	}
+	return nil, 0
+}
+
+// check returns the violations of the assertion by the module or resource, and the index of the entity node on which
+// they are reported. Violations on values which are not defined don't have a position.
+func (a *policyAssertion) check(entityNodes []*yaml.Node, entityKind string, entityName string) ([]YamlValidationIssue, int) {
+	_, valueKind, valueName := a.value()
+	valueNode, index := a.lookupValue(entityNodes)
+	violation := func(format string, args ...interface{}) []YamlValidationIssue {
+		msg := fmt.Sprintf(format, append([]interface{}{valueName, valueKind, entityName, entityKind}, args...)...)
+		if valueNode == nil {
+			return []YamlValidationIssue{{Msg: msg}}
+		}
+		return []YamlValidationIssue{{Msg: msg, Line: valueNode.Line, Column: valueNode.Column}}
+	}
+
+	if valueNode == nil {
+		if a.Exists != nil && *a.Exists {
+			return violation(policyValueNotDefinedMsg), index
+		}
+		return nil, index
+	}
+
+	var violations []YamlValidationIssue
+	if a.Exists != nil && !*a.Exists {
+		violations = append(violations, violation(policyValueDefinedMsg)...)
+	}
+	if a.Equals != nil && valueNode.Value != *a.Equals {
+		violations = append(violations, violation(policyValueNotEqualMsg, *a.Equals)...)
+	}
+	if a.NotEquals != nil && valueNode.Kind == yaml.ScalarNode && valueNode.Value == *a.NotEquals {
+		violations = append(violations, violation(policyValueEqualMsg, *a.NotEquals)...)
+	}
+	if a.pattern != nil && (valueNode.Kind != yaml.ScalarNode || !a.pattern.MatchString(valueNode.Value)) {
+		violations = append(violations, violation(policyValueNotMatchMsg, a.Matches)...)
+	}
+	if a.Contains != nil && !nodeContains(valueNode, *a.Contains) {
+		violations = append(violations, violation(policyValueNotContainMsg, *a.Contains)...)
+	}
+	if a.Min != nil || a.Max != nil {
+		number, err := strconv.ParseFloat(valueNode.Value, 64)
+		if err != nil || valueNode.Kind != yaml.ScalarNode {
+			violations = append(violations, violation(policyValueNotNumberMsg)...)
+		} else if a.Min != nil && number < *a.Min {
+			violations = append(violations, violation(policyValueTooSmallMsg, formatNumber(*a.Min))...)
+		} else if a.Max != nil && number > *a.Max {
+			violations = append(violations, violation(policyValueTooLargeMsg, formatNumber(*a.Max))...)
+		}
+	}
+	if a.MinSize != "" || a.MaxSize != "" {
+		size, ok := parseSize(valueNode.Value)
+		minSize, _ := parseSize(a.MinSize)
+		maxSize, _ := parseSize(a.MaxSize)
+		if !ok || valueNode.Kind != yaml.ScalarNode {
+			violations = append(violations, violation(policyValueNotSizeMsg)...)
+		} else if a.MinSize != "" && size < minSize {
+			violations = append(violations, violation(policyValueTooSmallMsg, a.MinSize)...)
+		} else if a.MaxSize != "" && size > maxSize {
+			violations = append(violations, violation(policyValueTooLargeMsg, a.MaxSize)...)
+		}
+	}
+	return violations, index
+}
+
+// nodeContains checks if the sequence node contains the value, or if the scalar node's value contains it
+func nodeContains(node *yaml.Node, value string) bool {
+	if node.Kind == yaml.ScalarNode {
+		return strings.Contains(node.Value, value)
+	}
+	if node.Kind == yaml.SequenceNode {
+		for _, item := range node.Content {
+			if item = resolveAlias(item); item.Kind == yaml.ScalarNode && item.Value == value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func getScalarPropValue(node *yaml.Node, name string) string {
+	valueNode := resolveAlias(getPropValueByName(node, name))
+	if valueNode == nil || valueNode.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return valueNode.Value
+}
+
+// matchesPattern checks if the value matches the glob pattern; empty patterns match all the values
+func matchesPattern(pattern string, value string) bool {
+	if pattern == "" {
+		return true
+	}
+	matched, _ := path.Match(pattern, value)
+	return matched
+}
+
+// parseSize parses sizes such as "512M", "1G" or "1.5GB" to bytes; the units are multiples of 1024
+func parseSize(value string) (float64, bool) {
+	match := sizeRegExp.FindStringSubmatch(value)
+	if match == nil {
+		return 0, false
+	}
+	size, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, false
+	}
+	return size * sizeUnits[strings.ToLower(match[2])], true
+}
+
+func formatNumber(number float64) string {
+	return strconv.FormatFloat(number, 'f', -1, 64)
+}
@@ -0,0 +1,177 @@
+package validate
+
+import (
+	"fmt"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Policies", func() {
+	content := []byte(`
+ID: mta.policy
+modules:
+- name: srv
+  type: nodejs
+  parameters:
+    memory: 2G
+    instances: 3
+    tags: [a, b]
+    config:
+      region: eu10
+- name: ui
+  type: html5
+resources:
+- name: db
+  type: com.sap.xs.hdi-container
+  parameters:
+    service-plan: standard
+`)
+	violation := func(policyName string, msg string, line int, column int) YamlValidationIssue {
+		return YamlValidationIssue{Msg: fmt.Sprintf(policyViolatedMsg, policyName, msg), Line: line, Column: column}
+	}
+
+	var _ = table.DescribeTable("checks the assertions", func(policyText string, expected ...YamlValidationIssue) {
+		policies, err := parsePolicies([]byte(policyText))
+		Ω(err).Should(Succeed())
+		root, err := getContentNode(content)
+		Ω(err).Should(Succeed())
+		errs, _ := checkPoliciesOnNode(policies, root)
+		if len(expected) == 0 {
+			Ω(errs).Should(BeEmpty())
+		} else {
+			Ω(errs).Should(Equal(expected))
+		}
+	},
+		table.Entry("exists", `policies: [{name: p, select: {kind: module}, assert: [{parameter: memory, exists: true}]}]`,
+			violation("p", fmt.Sprintf(policyValueNotDefinedMsg, "memory", "parameter", "ui", "module"), 12, 9)),
+		table.Entry("doesn't exist", `policies: [{name: p, select: {kind: module, name: srv}, assert: [{parameter: memory, exists: false}]}]`,
+			violation("p", fmt.Sprintf(policyValueDefinedMsg, "memory", "parameter", "srv", "module"), 7, 13)),
+		table.Entry("equals", `policies: [{name: p, select: {kind: module, type: nodejs}, assert: [{parameter: instances, equals: 1}]}]`,
+			violation("p", fmt.Sprintf(policyValueNotEqualMsg, "instances", "parameter", "srv", "module", "1"), 8, 16)),
+		table.Entry("not equals", `policies: [{name: p, select: {kind: resource, type: "com.sap.xs.*"}, assert: [{parameter: service-plan, not-equals: standard}]}]`,
+			violation("p", fmt.Sprintf(policyValueEqualMsg, "service-plan", "parameter", "db", "resource", "standard"), 18, 19)),
+		table.Entry("matches", `policies: [{name: p, select: {kind: module}, assert: [{parameter: config.region, matches: "^us"}]}]`,
+			violation("p", fmt.Sprintf(policyValueNotMatchMsg, "config.region", "parameter", "srv", "module", "^us"), 11, 15)),
+		table.Entry("contains", `policies: [{name: p, select: {kind: module}, assert: [{parameter: tags, contains: c}, {parameter: tags, contains: a}]}]`,
+			violation("p", fmt.Sprintf(policyValueNotContainMsg, "tags", "parameter", "srv", "module", "c"), 9, 11)),
+		table.Entry("numeric comparison", `policies: [{name: p, select: {kind: module}, assert: [{parameter: instances, min: 1, max: 2}]}]`,
+			violation("p", fmt.Sprintf(policyValueTooLargeMsg, "instances", "parameter", "srv", "module", "2"), 8, 16)),
+		table.Entry("not a number", `policies: [{name: p, select: {kind: module}, assert: [{parameter: memory, min: 1}]}]`,
+			violation("p", fmt.Sprintf(policyValueNotNumberMsg, "memory", "parameter", "srv", "module"), 7, 13)),
+		table.Entry("size comparison", `policies: [{name: p, select: {kind: module}, assert: [{parameter: memory, min-size: 512M, max-size: 1G}]}]`,
+			violation("p", fmt.Sprintf(policyValueTooLargeMsg, "memory", "parameter", "srv", "module", "1G"), 7, 13)),
+		table.Entry("size in range", `policies: [{name: p, select: {kind: module}, assert: [{parameter: memory, min-size: 2048MB, max-size: 2G}]}]`),
+		table.Entry("custom message", `policies: [{name: p, message: no standard plans, select: {kind: resource}, assert: [{parameter: service-plan, not-equals: standard}]}]`,
+			violation("p", "no standard plans", 18, 19)),
+		table.Entry("not selected", `policies: [{name: p, select: {kind: module, name: "ui*", type: nodejs}, assert: [{property: a, exists: true}]}]`),
+	)
+
+	It("reports violations of policies with the warning severity as warnings", func() {
+		policies, err := parsePolicies([]byte(`policies: [{name: p, severity: warning, select: {kind: module}, assert: [{parameter: memory, exists: true}]}]`))
+		Ω(err).Should(Succeed())
+		root, _ := getContentNode(content)
+		errs, warns := checkPoliciesOnNode(policies, root)
+		Ω(errs).Should(BeEmpty())
+		Ω(warns).Should(HaveLen(1))
+	})
+
+	var _ = table.DescribeTable("fails on an invalid policy file", func(policyText string, expectedErr string) {
+		_, err := parsePolicies([]byte(policyText))
+		Ω(err).Should(HaveOccurred())
+		Ω(err.Error()).Should(ContainSubstring(expectedErr))
+	},
+		table.Entry("no name", `policies: [{select: {kind: module}, assert: [{parameter: a, exists: true}]}]`,
+			fmt.Sprintf(policyNameNotDefinedMsg, 0)),
+		table.Entry("incorrect kind", `policies: [{name: p, select: {kind: hook}, assert: [{parameter: a, exists: true}]}]`,
+			fmt.Sprintf(policyKindIncorrectMsg, "hook", "p")),
+		table.Entry("incorrect severity", `policies: [{name: p, severity: info, select: {kind: module}}]`,
+			fmt.Sprintf(policySeverityIncorrectMsg, "info", "p")),
+		table.Entry("incorrect selector pattern", `policies: [{name: p, select: {kind: module, name: "["}}]`,
+			fmt.Sprintf(policyPatternIncorrectMsg, "[", "p")),
+		table.Entry("incorrect extension pattern", `policies: [{name: p, select: {kind: module, extension: "["}}]`,
+			fmt.Sprintf(policyPatternIncorrectMsg, "[", "p")),
+		table.Entry("no value", `policies: [{name: p, select: {kind: module}, assert: [{exists: true}]}]`,
+			fmt.Sprintf(policyAssertionValueMsg, 0, "p")),
+		table.Entry("no condition", `policies: [{name: p, select: {kind: module}, assert: [{parameter: a}]}]`,
+			fmt.Sprintf(policyAssertionConditionMsg, 0, "p")),
+		table.Entry("incorrect regular expression", `policies: [{name: p, select: {kind: module}, assert: [{parameter: a, matches: "("}]}]`,
+			fmt.Sprintf(policyPatternIncorrectMsg, "(", "p")),
+		table.Entry("incorrect size", `policies: [{name: p, select: {kind: module}, assert: [{parameter: a, max-size: 1X}]}]`,
+			fmt.Sprintf(policySizeIncorrectMsg, "1X", "p")),
+		table.Entry("unknown field", `policies: [{name: p, select: {kind: module}, assert: [{parameter: a, equal: 1}]}]`,
+			"field equal not found"),
+	)
+
+	var _ = table.DescribeTable("parses sizes", func(size string, expected float64, expectedOk bool) {
+		result, ok := parseSize(size)
+		Ω(ok).Should(Equal(expectedOk))
+		Ω(result).Should(Equal(expected))
+	},
+		table.Entry("bytes", "100", 100.0, true),
+		table.Entry("kilobytes", "2k", 2048.0, true),
+		table.Entry("megabytes", "512MB", 512.0*1024*1024, true),
+		table.Entry("gigabytes", "1.5G", 1.5*1024*1024*1024, true),
+		table.Entry("unknown unit", "1X", 0.0, false),
+	)
+
+	It("validates the policies of the project with the semantic validations", func() {
+		warn, err := MtaYaml(getTestPath("policyProject"), "mta.yaml", true, true, true, "")
+		Ω(err).Should(HaveOccurred())
+		Ω(err.Error()).Should(ContainSubstring("line 17: " + fmt.Sprintf(policyViolatedMsg, "no-standard-hana-plan",
+			fmt.Sprintf(policyValueEqualMsg, "service-plan", "parameter", "db", "resource", "standard"))))
+		Ω(warn).Should(ContainSubstring("line 11: " + fmt.Sprintf(policyViolatedMsg, "nodejs-memory",
+			"the nodejs modules must not use more than 1G of memory")))
+
+		mtaPath := getTestPath("policyProject", "mta.yaml")
+		result := Validate(mtaPath, nil)
+		Ω(result[mtaPath]).Should(HaveLen(2))
+		Ω(result[mtaPath][0].Rule).Should(Equal(policyValidation))
+		Ω(result[mtaPath][1].Rule).Should(Equal(policyValidation))
+
+		_, err = MtaYaml(getTestPath("policyProject"), "mta.yaml", true, true, true, policyValidation)
+		Ω(err).Should(Succeed())
+	})
+
+	It("checks the policies with extensions against the MTA descriptor merged with the extension descriptors", func() {
+		mtaPath := getTestPath("policyExtProject", "mta.yaml")
+		devPath := getTestPath("policyExtProject", "dev.mtaext")
+		prodPath := getTestPath("policyExtProject", "prod.mtaext")
+
+		result := Validate(mtaPath, nil)
+		Ω(result[mtaPath]).Should(BeEmpty())
+
+		result = Validate(mtaPath, []string{devPath})
+		Ω(result[mtaPath]).Should(Equal([]FileValidationIssue{{
+			Severity: SeverityWarning,
+			Message: fmt.Sprintf(policyViolatedMsg, "nodejs-memory-in-dev",
+				fmt.Sprintf(policyValueTooLargeMsg, "memory", "parameter", "srv", "module", "1G")),
+			Line: 11, Column: 13, Rule: policyValidation,
+		}}))
+		Ω(result[devPath]).Should(Equal([]FileValidationIssue{{
+			Severity: SeverityError,
+			Message: fmt.Sprintf(policyViolatedMsg, "no-standard-hana-plan-in-dev",
+				fmt.Sprintf(policyValueEqualMsg, "service-plan", "parameter", "db", "resource", "standard")),
+			Line: 8, Column: 19, Rule: policyValidation,
+		}}))
+
+		result = Validate(mtaPath, []string{prodPath})
+		Ω(result[mtaPath]).Should(BeEmpty())
+		Ω(result[prodPath]).Should(BeEmpty())
+	})
+
+	It("reports a policy file which can't be read", func() {
+		// The policy file is not in a folder, so the policy file of the "project" can't be read
+		errs, _ := checkPolicies(nil, nil, getTestPath("policyProject", PolicyFileName), true)
+		Ω(errs).Should(HaveLen(1))
+		Ω(errs[0].Msg).Should(HavePrefix(fmt.Sprintf(readPolicyFileFailedMsg, filepath.Join(getTestPath("policyProject", PolicyFileName), PolicyFileName))))
+	})
+
+	It("doesn't check policies when the project doesn't have a policy file", func() {
+		errs, warns := checkPolicies(nil, nil, getTestPath("testproject"), true)
+		Ω(errs).Should(BeEmpty())
+		Ω(warns).Should(BeEmpty())
+	})
+})
@@ -37,15 +37,15 @@ func isNameUnique(mta *mta.MTA, mtaNode *yaml.Node, source string, strict bool)
 }
 
 func getModulePositionByIndex(mtaNode *yaml.Node, index int) (line int, column int) {
-	return getNamedObjectPositionByIndex(mtaNode, modulesYamlField, index)
+	return GetNamedObjectPositionByIndex(mtaNode, modulesYamlField, index)
 }
 
 func getResourcePositionByIndex(mtaNode *yaml.Node, index int) (line int, column int) {
-	return getNamedObjectPositionByIndex(mtaNode, resourcesYamlField, index)
+	return GetNamedObjectPositionByIndex(mtaNode, resourcesYamlField, index)
 }
 
 func getProvidedSetPositionByIndex(mtaNode *yaml.Node, moduleIndex, providedSetIndex int) (line int, column int) {
-	moduleNode := getNamedObjectNodeByIndex(mtaNode, modulesYamlField, moduleIndex)
+	moduleNode := GetNamedObjectNodeByIndex(mtaNode, modulesYamlField, moduleIndex)
 	provided := getPropValueByName(moduleNode, providesYamlField)
 	line, column, _ = getIndexedNodePropPosition(provided, providedSetIndex, nameYamlField)
 	return line, column
@@ -3,6 +3,7 @@ package validate
 import (
 	"gopkg.in/yaml.v3"
 	"strings"
+	"unicode"
 
 	"github.com/SAP/cloud-mta/mta"
 )
@@ -13,6 +14,7 @@ const (
 	configuration               = "configuration"
 	pathYamlField               = "path"
 	nameYamlField               = "name"
+	typeYamlField               = "type"
 	modulesYamlField            = "modules"
 	providesYamlField           = "provides"
 	resourcesYamlField          = "resources"
@@ -35,6 +37,8 @@ const (
 	publicYamlField             = "public"
 	listYamlField               = "list"
 	groupYamlField              = "group"
+	idYamlField                 = "ID"
+	extendsYamlField            = "extends"
 
 	npmOptsYamlField   = "npm-opts"
 	gruntOptsYamlField = "grunt-opts"
@@ -49,6 +53,10 @@ const (
 	deployerConstrValidation      = "deployerConstraints"
 	metadataValidation            = "metadata"
 	ifNoSourceParamBoolValidation = "checkNoSourceParam"
+	envVarCollisionsValidation    = "envVarCollisions"
+	policyValidation              = "policy"
+	deploymentOrderValidation     = "deploymentOrder"
+	envVarNamesValidation         = "envVarNames"
 
 	propertiesMtaField      = "Properties"
 	parametersMtaField      = "Parameters"
@@ -84,41 +92,79 @@ func runSemanticValidations(mtaStr *mta.MTA, root *yaml.Node, source string, exc
 	return errors, warnings
 }
 
-// getSemanticValidations - gets list of all semantic validations minus excludes validations
+// getSemanticValidations - gets list of all semantic validations, including the registered semantic rules, minus excludes validations
 func getSemanticValidations(exclude string) []checkSemantic {
 	var validations []checkSemantic
-	if !strings.Contains(exclude, pathsValidation) {
-		validations = append(validations, ifModulePathExists)
+	excluded := getExcludedValidations(exclude)
+	if !excluded[pathsValidation] {
+		validations = append(validations, semanticRule(pathsValidation, ifModulePathExists))
 	}
-	if !strings.Contains(exclude, emptyPathValidation) {
-		validations = append(validations, ifModulePathEmpty)
+	if !excluded[emptyPathValidation] {
+		validations = append(validations, semanticRule(emptyPathValidation, ifModulePathEmpty))
 	}
-	if !strings.Contains(exclude, namesValidation) {
-		validations = append(validations, isNameUnique)
+	if !excluded[namesValidation] {
+		validations = append(validations, semanticRule(namesValidation, isNameUnique))
 	}
-	if !strings.Contains(exclude, requiredValidation) {
-		validations = append(validations, ifRequiredDefined)
+	if !excluded[requiredValidation] {
+		validations = append(validations, semanticRule(requiredValidation, ifRequiredDefined))
 	}
-	if !strings.Contains(exclude, buildersValidation) {
-		validations = append(validations, checkBuildersSemantic)
+	if !excluded[buildersValidation] {
+		validations = append(validations, semanticRule(buildersValidation, checkBuildersSemantic))
 	}
-	if !strings.Contains(exclude, deprecatedOptsValidation) {
-		validations = append(validations, checkDeprecatedOpts)
+	if !excluded[deprecatedOptsValidation] {
+		validations = append(validations, semanticRule(deprecatedOptsValidation, checkDeprecatedOpts))
 	}
-	if !strings.Contains(exclude, deployerConstrValidation) {
-		validations = append(validations, checkDeployerConstraints)
+	if !excluded[deployerConstrValidation] {
+		validations = append(validations, semanticRule(deployerConstrValidation, checkDeployerConstraints))
 	}
-	if !strings.Contains(exclude, metadataValidation) {
-		validations = append(validations, checkParamsAndPropertiesMetadata)
+	if !excluded[metadataValidation] {
+		validations = append(validations, semanticRule(metadataValidation, checkParamsAndPropertiesMetadata))
 	}
-	if !strings.Contains(exclude, ifNoSourceParamBoolValidation) {
-		validations = append(validations, ifNoSourceParamBool)
+	if !excluded[ifNoSourceParamBoolValidation] {
+		validations = append(validations, semanticRule(ifNoSourceParamBoolValidation, ifNoSourceParamBool))
 	}
+	if !excluded[envVarCollisionsValidation] {
+		validations = append(validations, semanticRule(envVarCollisionsValidation, checkEnvVarCollisions))
+	}
+	if !excluded[envVarNamesValidation] {
+		validations = append(validations, semanticRule(envVarNamesValidation, checkEnvVarNames))
+	}
+	if !excluded[deploymentOrderValidation] {
+		validations = append(validations, semanticRule(deploymentOrderValidation, checkDeploymentOrder))
+	}
+	if !excluded[policyValidation] {
+		validations = append(validations, semanticRule(policyValidation, checkPolicies))
+	}
+	validations = append(validations, getCustomSemanticValidations(excluded)...)
 
 	return validations
 }
 
+// semanticRule - gets the semantic validation which reports its issues with the ID of the rule
+func semanticRule(rule string, validation checkSemantic) checkSemantic {
+	return func(mta *mta.MTA, root *yaml.Node, source string, strict bool) ([]YamlValidationIssue, []YamlValidationIssue) {
+		errors, warnings := validation(mta, root, source, strict)
+		return setIssuesRule(errors, rule), setIssuesRule(warnings, rule)
+	}
+}
+
+// getExcludedValidations - gets the names of the excluded validations, which are separated by commas or spaces
+func getExcludedValidations(exclude string) map[string]bool {
+	excluded := make(map[string]bool)
+	for _, name := range strings.FieldsFunc(exclude, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	}) {
+		excluded[name] = true
+	}
+	return excluded
+}
+
+// getIndexedNodePropPosition returns the position of the property value of the sequence item with the index.
+// The position of the item is returned when it doesn't have the property, and 0, 0 when the item doesn't exist.
 func getIndexedNodePropPosition(node *yaml.Node, index int, propName string) (line int, column int, propFound bool) {
+	if node == nil || index < 0 || index >= len(node.Content) {
+		return 0, 0, false
+	}
 	indexedNode := node.Content[index]
 	nameNode := getPropValueByName(indexedNode, propName)
 	if nameNode == nil {
@@ -127,12 +173,20 @@ func getIndexedNodePropPosition(node *yaml.Node, index int, propName string) (li
 	return nameNode.Line, nameNode.Column, true
 }
 
-func getNamedObjectNodeByIndex(parentNode *yaml.Node, fieldName string, index int) *yaml.Node {
+// GetNamedObjectNodeByIndex returns the node of the item with the index in the sequence property of the parent node,
+// e.g. the node of modules[index], or nil when the item doesn't exist
+func GetNamedObjectNodeByIndex(parentNode *yaml.Node, fieldName string, index int) *yaml.Node {
 	objectsNode := getPropValueByName(parentNode, fieldName)
+	if objectsNode == nil || index < 0 || index >= len(objectsNode.Content) {
+		return nil
+	}
 	return objectsNode.Content[index]
 }
 
-func getNamedObjectPositionByIndex(parentNode *yaml.Node, fieldName string, index int) (line int, column int) {
+// GetNamedObjectPositionByIndex returns the position of the name of the item with the index in the sequence property
+// of the parent node, e.g. the position of modules[index].name. The position of the item is returned when it doesn't
+// have a name, and 0, 0 when the item doesn't exist.
+func GetNamedObjectPositionByIndex(parentNode *yaml.Node, fieldName string, index int) (line int, column int) {
 	objectsNode := getPropValueByName(parentNode, fieldName)
 	line, column, _ = getIndexedNodePropPosition(objectsNode, index, nameYamlField)
 	return line, column
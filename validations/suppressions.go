@@ -0,0 +1,263 @@
+package validate
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The suppression comments, e.g.
+//
+//	# mta-validate-disable-next-line deprecatedOpts
+//	# mta-validate-disable paths, emptyPath
+//	# mta-validate-enable paths, emptyPath
+//
+// Comments without rules suppress the issues of all the rules.
+const (
+	disableNextLineDirective = "mta-validate-disable-next-line"
+	disableDirective         = "mta-validate-disable"
+	enableDirective          = "mta-validate-enable"
+)
+
+const (
+	unusedSuppressionMsg      = `the "%s" suppression comment does not suppress any issues`
+	unknownSuppressionRuleMsg = `the "%s" rule in the "%s" suppression comment is unknown`
+)
+
+var suppressionRegExp = regexp.MustCompile(`^#\s*(` + disableNextLineDirective + `|` + disableDirective + `|` +
+	enableDirective + `)(\s+.*)?$`)
+
+var lineBreakRegExp = regexp.MustCompile(`\r\n?|\n`)
+
+// suppression - a suppression comment and the lines on which it suppresses issues
+type suppression struct {
+	directive string
+	// rules - the IDs of the suppressed rules; all the rules are suppressed when it's empty
+	rules    []string
+	text     string
+	line     int
+	column   int
+	fromLine int
+	toLine   int
+	used     bool
+	// origin - the disable comment which this suppression continues after some of its rules were enabled
+	origin *suppression
+}
+
+// suppressIssues removes the issues which are suppressed by comments in the content, and adds warnings for the
+// suppression comments which don't suppress any issues. Comments for excluded rules are not reported.
+func suppressIssues(yamlContent []byte, exclude string, errIssues, warnIssues YamlValidationIssues) (YamlValidationIssues, YamlValidationIssues) {
+	root, err := getContentNode(yamlContent)
+	if err != nil {
+		return errIssues, warnIssues
+	}
+	suppressions := getSuppressions(root, lineBreakRegExp.Split(string(yamlContent), -1))
+	if len(suppressions) == 0 {
+		return errIssues, warnIssues
+	}
+
+	errIssues = removeSuppressedIssues(suppressions, errIssues)
+	warnIssues = removeSuppressedIssues(suppressions, warnIssues)
+
+	ruleIDs := make(map[string]bool)
+	for _, rule := range Rules() {
+		ruleIDs[rule.ID] = true
+	}
+	excluded := getExcludedValidations(exclude)
+	for _, s := range suppressions {
+		if s.origin != nil {
+			continue
+		}
+		allExcluded := len(s.rules) > 0
+		for _, rule := range s.rules {
+			if !ruleIDs[rule] {
+				warnIssues = appendRuleIssue(warnIssues, suppressionRule, fmt.Sprintf(unknownSuppressionRuleMsg, rule, s.text), s.line, s.column)
+			}
+			allExcluded = allExcluded && excluded[rule]
+		}
+		if !s.used && s.directive != enableDirective && !allExcluded {
+			warnIssues = appendRuleIssue(warnIssues, suppressionRule, fmt.Sprintf(unusedSuppressionMsg, s.text), s.line, s.column)
+		}
+	}
+	return errIssues, warnIssues
+}
+
+func removeSuppressedIssues(suppressions []*suppression, issues YamlValidationIssues) YamlValidationIssues {
+	var result YamlValidationIssues
+	for _, issue := range issues {
+		suppressed := false
+		for _, s := range suppressions {
+			if s.directive != enableDirective && issue.Line >= s.fromLine && issue.Line <= s.toLine &&
+				(len(s.rules) == 0 || containsString(s.rules, issue.Rule)) {
+				s.used = true
+				if s.origin != nil {
+					s.origin.used = true
+				}
+				suppressed = true
+			}
+		}
+		if !suppressed {
+			result = append(result, issue)
+		}
+	}
+	return result
+}
+
+// getSuppressions returns the suppression comments of the nodes, in the order of their lines
+func getSuppressions(root *yaml.Node, lines []string) []*suppression {
+	var suppressions []*suppression
+	nodeLines := make(map[int]bool)
+	addComments := func(comments string, line int, searchStep int) {
+		if comments == "" {
+			return
+		}
+		commentLines := strings.Split(comments, "\n")
+		if searchStep < 0 {
+			// Head comments end right before their node, so they are searched from the last one
+			for i := len(commentLines) - 1; i >= 0; i-- {
+				suppressions, line = addSuppression(suppressions, lines, commentLines[i], line, searchStep)
+			}
+		} else {
+			for _, comment := range commentLines {
+				suppressions, line = addSuppression(suppressions, lines, comment, line, searchStep)
+			}
+		}
+	}
+
+	var walk func(node *yaml.Node, lastLine int)
+	walk = func(node *yaml.Node, lastLine int) {
+		nodeLines[node.Line] = true
+		addComments(node.HeadComment, node.Line-1, -1)
+		addComments(node.LineComment, node.Line, 0)
+		addComments(node.FootComment, lastLine+1, 1)
+		if node.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				// The comments after a property are the foot comments of its key, but they follow its value
+				valueLastLine := getLastLine(node.Content[i+1])
+				walk(node.Content[i], valueLastLine)
+				walk(node.Content[i+1], valueLastLine)
+			}
+			return
+		}
+		for _, child := range node.Content {
+			walk(child, getLastLine(child))
+		}
+	}
+	walk(root, getLastLine(root))
+
+	sort.SliceStable(suppressions, func(i, j int) bool {
+		return suppressions[i].line < suppressions[j].line
+	})
+	sortedNodeLines := make([]int, 0, len(nodeLines))
+	for line := range nodeLines {
+		sortedNodeLines = append(sortedNodeLines, line)
+	}
+	sort.Ints(sortedNodeLines)
+
+	var openSuppressions, continuations []*suppression
+	for _, s := range suppressions {
+		switch s.directive {
+		case disableNextLineDirective:
+			// The next line is the next line with content, since comments and empty lines can be in between
+			i := sort.SearchInts(sortedNodeLines, s.line+1)
+			if i < len(sortedNodeLines) {
+				s.fromLine = sortedNodeLines[i]
+				s.toLine = sortedNodeLines[i]
+			}
+		case disableDirective:
+			s.fromLine = s.line + 1
+			s.toLine = math.MaxInt32
+			openSuppressions = append(openSuppressions, s)
+		case enableDirective:
+			var stillOpen []*suppression
+			for _, open := range openSuppressions {
+				if len(s.rules) == 0 || len(open.rules) > 0 && containsAllStrings(s.rules, open.rules) {
+					open.toLine = s.line - 1
+				} else if remaining := subtractStrings(open.rules, s.rules); len(open.rules) > 0 && len(remaining) < len(open.rules) {
+					// Only some of the rules are enabled, so the other rules stay disabled in a continuation
+					open.toLine = s.line - 1
+					origin := open
+					if open.origin != nil {
+						origin = open.origin
+					}
+					continuation := &suppression{directive: disableDirective, rules: remaining, text: open.text,
+						line: open.line, column: open.column, fromLine: s.line, toLine: math.MaxInt32, origin: origin}
+					continuations = append(continuations, continuation)
+					stillOpen = append(stillOpen, continuation)
+				} else {
+					stillOpen = append(stillOpen, open)
+				}
+			}
+			openSuppressions = stillOpen
+		}
+	}
+	return append(suppressions, continuations...)
+}
+
+// subtractStrings returns the values which are not in the removed values
+func subtractStrings(values []string, removed []string) []string {
+	var result []string
+	for _, value := range values {
+		if !containsString(removed, value) {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// addSuppression adds the comment if it's a suppression comment. The line of the comment is searched in the content
+// from the line in the direction of the step, and the line from which the next comment is searched is returned.
+func addSuppression(suppressions []*suppression, lines []string, comment string, line int, searchStep int) ([]*suppression, int) {
+	comment = strings.TrimSpace(comment)
+	commentLine, column := findCommentLine(lines, comment, line, searchStep)
+	nextLine := line
+	if commentLine > 0 {
+		nextLine = commentLine + searchStep
+	} else {
+		commentLine = line
+	}
+	match := suppressionRegExp.FindStringSubmatch(comment)
+	if match == nil {
+		return suppressions, nextLine
+	}
+	return append(suppressions, &suppression{
+		directive: match[1],
+		rules: strings.FieldsFunc(match[2], func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		}),
+		text:   strings.TrimSpace(strings.TrimPrefix(comment, "#")),
+		line:   commentLine,
+		column: column,
+	}), nextLine
+}
+
+// findCommentLine returns the line and the column of the comment, searching from the line in the direction of the step
+// over the comments and the empty lines. Line comments (a step of 0) are searched only on the line.
+func findCommentLine(lines []string, comment string, line int, searchStep int) (int, int) {
+	for line > 0 && line <= len(lines) {
+		text := lines[line-1]
+		if index := strings.Index(text, comment); index >= 0 {
+			return line, index + 1
+		}
+		trimmed := strings.TrimSpace(text)
+		if searchStep == 0 || trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			break
+		}
+		line += searchStep
+	}
+	return 0, 0
+}
+
+func containsAllStrings(values []string, required []string) bool {
+	for _, value := range required {
+		if !containsString(values, value) {
+			return false
+		}
+	}
+	return true
+}
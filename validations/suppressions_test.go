@@ -0,0 +1,137 @@
+package validate
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Suppression comments", func() {
+	content := []byte(`ID: mta.suppress
+_schema-version: '3.2'
+modules:
+# mta-validate-disable-next-line names
+- name: srv
+  type: nodejs
+  path: srv # mta-validate-disable-next-line
+
+  build-parameters:
+    npm-opts: {}
+# mta-validate-disable paths, emptyPath
+- name: ui
+  path: ui
+# mta-validate-enable paths emptyPath
+- name: db
+  path: db
+`)
+	nameIssue := YamlValidationIssue{Msg: fmt.Sprintf(`the "%s" %s name is already in use; %s %s was found with the same name on line %d`, "srv", "module", "srv", "module", 1), Line: 5, Column: 9, Rule: namesValidation}
+	optsIssue := YamlValidationIssue{Msg: fmt.Sprintf(deprecatedOptMsg, "npm-opts", "url"), Line: 9, Column: 3, Rule: deprecatedOptsValidation}
+	nestedOptsIssue := YamlValidationIssue{Msg: fmt.Sprintf(deprecatedOptMsg, "npm-opts", "url"), Line: 10, Column: 5, Rule: deprecatedOptsValidation}
+	uiPathIssue := YamlValidationIssue{Msg: fmt.Sprintf(`the "%s" path of the "%s" module does not exist`, "ui", "ui"), Line: 13, Column: 9, Rule: pathsValidation}
+	dbPathIssue := YamlValidationIssue{Msg: fmt.Sprintf(`the "%s" path of the "%s" module does not exist`, "db", "db"), Line: 16, Column: 9, Rule: pathsValidation}
+	unusedIssue := func(text string, line int, column int) YamlValidationIssue {
+		return YamlValidationIssue{Msg: fmt.Sprintf(unusedSuppressionMsg, text), Line: line, Column: column, Rule: suppressionRule}
+	}
+
+	var _ = table.DescribeTable("suppresses issues", func(exclude string, errs YamlValidationIssues, expectedErrs, expectedWarns YamlValidationIssues) {
+		resultErrs, resultWarns := suppressIssues(content, exclude, errs, nil)
+		Ω(resultErrs).Should(Equal(expectedErrs))
+		Ω(resultWarns).Should(Equal(expectedWarns))
+	},
+		table.Entry("on the next lines and in blocks", "",
+			YamlValidationIssues{nameIssue, optsIssue, nestedOptsIssue, uiPathIssue, dbPathIssue},
+			YamlValidationIssues{nestedOptsIssue, dbPathIssue}, nil),
+		table.Entry("reports unused suppression comments", "",
+			YamlValidationIssues{dbPathIssue},
+			YamlValidationIssues{dbPathIssue},
+			YamlValidationIssues{
+				unusedIssue("mta-validate-disable-next-line names", 4, 1),
+				unusedIssue("mta-validate-disable-next-line", 7, 13),
+				unusedIssue("mta-validate-disable paths, emptyPath", 11, 1),
+			}),
+		table.Entry("doesn't report suppression comments of excluded rules", "paths,emptyPath,names",
+			YamlValidationIssues{optsIssue},
+			nil, nil),
+	)
+
+	partialContent := []byte(`# mta-validate-disable paths, emptyPath
+ID: mta
+modules:
+- name: srv
+# mta-validate-enable paths
+  type: nodejs
+- name: a
+  type: nodejs
+  path: nope
+- name: b
+  path: ""
+`)
+	nopePathIssue := YamlValidationIssue{Msg: fmt.Sprintf(`the "%s" path of the "%s" module does not exist`, "nope", "a"), Line: 9, Column: 9, Rule: pathsValidation}
+	srvPathIssue := YamlValidationIssue{Msg: fmt.Sprintf(`the path of the "%s" module is not defined`, "srv"), Line: 4, Column: 9, Rule: emptyPathValidation}
+	bPathIssue := YamlValidationIssue{Msg: fmt.Sprintf(`the path of the "%s" module is empty`, "b"), Line: 11, Column: 9, Rule: emptyPathValidation}
+
+	var _ = table.DescribeTable("suppresses issues after some of the rules are enabled", func(errs YamlValidationIssues, expectedErrs, expectedWarns YamlValidationIssues) {
+		resultErrs, resultWarns := suppressIssues(partialContent, "", errs, nil)
+		Ω(resultErrs).Should(Equal(expectedErrs))
+		Ω(resultWarns).Should(Equal(expectedWarns))
+	},
+		table.Entry("reports the issues of the enabled rules", YamlValidationIssues{srvPathIssue, nopePathIssue, bPathIssue},
+			YamlValidationIssues{nopePathIssue}, nil),
+		table.Entry("uses the comment when only the rules which are still disabled have issues", YamlValidationIssues{nopePathIssue, bPathIssue},
+			YamlValidationIssues{nopePathIssue}, nil),
+		table.Entry("reports an unused comment once", YamlValidationIssues{nopePathIssue},
+			YamlValidationIssues{nopePathIssue},
+			YamlValidationIssues{unusedIssue("mta-validate-disable paths, emptyPath", 1, 1)}),
+	)
+
+	It("reports unknown rules", func() {
+		_, warns := suppressIssues([]byte("# mta-validate-disable-next-line nmes\nID: mta\n"), "", nil, nil)
+		Ω(warns).Should(Equal(YamlValidationIssues{
+			{Msg: fmt.Sprintf(unknownSuppressionRuleMsg, "nmes", "mta-validate-disable-next-line nmes"), Line: 1, Column: 1, Rule: suppressionRule},
+			unusedIssue("mta-validate-disable-next-line nmes", 1, 1),
+		}))
+	})
+
+	It("suppresses the issues of all the rules until the end of the block", func() {
+		blockContent := []byte(`ID: mta
+modules:
+- name: srv
+  parameters:
+    # mta-validate-disable
+    a: 1
+    b: 2
+  # mta-validate-enable
+  path: srv
+`)
+		errs, warns := suppressIssues(blockContent, "", YamlValidationIssues{
+			{Msg: fmt.Sprintf(propertyTypeMsg, "a", "a string"), Line: 6, Column: 8, Rule: propertyTypeRule},
+			{Msg: fmt.Sprintf(propertyTypeMsg, "b", "a string"), Line: 7, Column: 8, Rule: propertyTypeRule},
+			{Msg: fmt.Sprintf(`the "%s" path of the "%s" module does not exist`, "srv", "srv"), Line: 9, Column: 9, Rule: pathsValidation},
+		}, nil)
+		Ω(errs).Should(Equal(YamlValidationIssues{{Msg: fmt.Sprintf(`the "%s" path of the "%s" module does not exist`, "srv", "srv"), Line: 9, Column: 9, Rule: pathsValidation}}))
+		Ω(warns).Should(BeEmpty())
+	})
+
+	It("suppresses the issues of the validation of the project", func() {
+		mtaContent := `ID: mta.suppress
+_schema-version: '3.2'
+version: 1.0.0
+modules:
+- name: srv
+  type: nodejs
+  path: srv
+  build-parameters:
+    # mta-validate-disable-next-line deprecatedOpts
+    npm-opts:
+      no-optional: true
+`
+		errs, warns := validate([]byte(mtaContent), getTestPath("configProject"), true, true, false, "")
+		Ω(errs).Should(HaveLen(1))
+		Ω(warns).Should(BeEmpty())
+		errs, warns = suppressIssues([]byte(mtaContent), "", errs, warns)
+		Ω(errs).Should(BeEmpty())
+		Ω(warns).Should(BeEmpty())
+	})
+})
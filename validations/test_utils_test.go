@@ -13,5 +13,8 @@ func assertNoValidationErrors(errors []YamlValidationIssue) {
 }
 
 func expectSingleValidationError(actual []YamlValidationIssue, expectedMsg string, expectedLine int, expectedColumn int) {
-	Ω(actual).Should(ConsistOf(YamlValidationIssue{expectedMsg, expectedLine, expectedColumn}))
+	// The rule of the issue is not compared
+	Ω(actual).Should(HaveLen(1))
+	Ω(YamlValidationIssue{Msg: actual[0].Msg, Line: actual[0].Line, Column: actual[0].Column}).
+		Should(Equal(YamlValidationIssue{Msg: expectedMsg, Line: expectedLine, Column: expectedColumn}))
 }